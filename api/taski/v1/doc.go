@@ -0,0 +1,5 @@
+// Package taskiv1 holds the protobuf messages and the gRPC service of the
+// taski API. Regenerate the .pb.go files after editing taski.proto.
+package taskiv1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/taski/v1/taski.proto
@@ -0,0 +1,984 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: api/taski/v1/taski.proto
+
+package taskiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Task mirrors io.Task. The id is the task's position in the database and
+// is what every command uses to target it.
+type Task struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uid         string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Title       string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Date        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	Due         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due,proto3" json:"due,omitempty"`
+	Priority    string                 `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Recurrence  string                 `protobuf:"bytes,8,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	IsDeleted   bool                   `protobuf:"varint,9,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	DeletedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Tags        []string               `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	Contexts    []string               `protobuf:"bytes,12,rep,name=contexts,proto3" json:"contexts,omitempty"`
+	// status is one lowercase word such as "doing", "done" or a custom board
+	// column, and empty while the task is pending.
+	Status string `protobuf:"bytes,13,opt,name=status,proto3" json:"status,omitempty"`
+	// remind lists when to be reminded before the due date, such as
+	// "30m-before", or "due" for the due time itself.
+	Remind []string `protobuf:"bytes,14,rep,name=remind,proto3" json:"remind,omitempty"`
+	// custom holds the custom fields declared in config.json by name, in
+	// their canonical text form.
+	Custom map[string]string `protobuf:"bytes,15,rep,name=custom,proto3" json:"custom,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// notes are the annotations added with taski note, oldest first.
+	Notes         []*Note `protobuf:"bytes,16,rep,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Task) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Task) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Task) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Task) GetDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Due
+	}
+	return nil
+}
+
+func (x *Task) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *Task) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Task) GetIsDeleted() bool {
+	if x != nil {
+		return x.IsDeleted
+	}
+	return false
+}
+
+func (x *Task) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetContexts() []string {
+	if x != nil {
+		return x.Contexts
+	}
+	return nil
+}
+
+func (x *Task) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Task) GetRemind() []string {
+	if x != nil {
+		return x.Remind
+	}
+	return nil
+}
+
+func (x *Task) GetCustom() map[string]string {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
+}
+
+func (x *Task) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+// Note mirrors io.Note, an annotation written at time.
+type Note struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Note) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Note) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Database mirrors io.Database. Size counts the active tasks.
+type Database struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Tasks         []*Task                `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Database) Reset() {
+	*x = Database{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Database) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Database) ProtoMessage() {}
+
+func (x *Database) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Database.ProtoReflect.Descriptor instead.
+func (*Database) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Database) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Database) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type ListTasksRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	IncludeTrash bool                   `protobuf:"varint,1,opt,name=include_trash,json=includeTrash,proto3" json:"include_trash,omitempty"`
+	// Filter expression as accepted by --where, e.g. "title:report".
+	Where         string `protobuf:"bytes,2,opt,name=where,proto3" json:"where,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTasksRequest) GetIncludeTrash() bool {
+	if x != nil {
+		return x.IncludeTrash
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetWhere() string {
+	if x != nil {
+		return x.Where
+	}
+	return ""
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type AddTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Due           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due,proto3" json:"due,omitempty"`
+	Priority      string                 `protobuf:"bytes,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Contexts      []string               `protobuf:"bytes,6,rep,name=contexts,proto3" json:"contexts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTaskRequest) Reset() {
+	*x = AddTaskRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTaskRequest) ProtoMessage() {}
+
+func (x *AddTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTaskRequest.ProtoReflect.Descriptor instead.
+func (*AddTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Due
+	}
+	return nil
+}
+
+func (x *AddTaskRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddTaskRequest) GetContexts() []string {
+	if x != nil {
+		return x.Contexts
+	}
+	return nil
+}
+
+type ChangeTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeTaskRequest) Reset() {
+	*x = ChangeTaskRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeTaskRequest) ProtoMessage() {}
+
+func (x *ChangeTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeTaskRequest.ProtoReflect.Descriptor instead.
+func (*ChangeTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChangeTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChangeTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChangeTaskRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DeleteTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTaskRequest) Reset() {
+	*x = RestoreTaskRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTaskRequest) ProtoMessage() {}
+
+func (x *RestoreTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTaskRequest.ProtoReflect.Descriptor instead.
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestoreTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAllRequest) Reset() {
+	*x = RestoreAllRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAllRequest) ProtoMessage() {}
+
+func (x *RestoreAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAllRequest.ProtoReflect.Descriptor instead.
+func (*RestoreAllRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{9}
+}
+
+type ExportTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of todotxt, markdown, csv, html.
+	Format        string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	IncludeTrash  bool   `protobuf:"varint,2,opt,name=include_trash,json=includeTrash,proto3" json:"include_trash,omitempty"`
+	Where         string `protobuf:"bytes,3,opt,name=where,proto3" json:"where,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTasksRequest) Reset() {
+	*x = ExportTasksRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTasksRequest) ProtoMessage() {}
+
+func (x *ExportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ExportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportTasksRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportTasksRequest) GetIncludeTrash() bool {
+	if x != nil {
+		return x.IncludeTrash
+	}
+	return false
+}
+
+func (x *ExportTasksRequest) GetWhere() string {
+	if x != nil {
+		return x.Where
+	}
+	return ""
+}
+
+type ExportTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTasksResponse) Reset() {
+	*x = ExportTasksResponse{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTasksResponse) ProtoMessage() {}
+
+func (x *ExportTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksResponse.ProtoReflect.Descriptor instead.
+func (*ExportTasksResponse) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportTasksResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IncludeTrash  bool                   `protobuf:"varint,1,opt,name=include_trash,json=includeTrash,proto3" json:"include_trash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_api_taski_v1_taski_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_taski_v1_taski_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_taski_v1_taski_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchTasksRequest) GetIncludeTrash() bool {
+	if x != nil {
+		return x.IncludeTrash
+	}
+	return false
+}
+
+var File_api_taski_v1_taski_proto protoreflect.FileDescriptor
+
+const file_api_taski_v1_taski_proto_rawDesc = "" +
+	"\n" +
+	"\x18api/taski/v1/taski.proto\x12\btaski.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc9\x04\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x10\n" +
+	"\x03uid\x18\x02 \x01(\tR\x03uid\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12.\n" +
+	"\x04date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12,\n" +
+	"\x03due\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x03due\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\b \x01(\tR\n" +
+	"recurrence\x12\x1d\n" +
+	"\n" +
+	"is_deleted\x18\t \x01(\bR\tisDeleted\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x1a\n" +
+	"\bcontexts\x18\f \x03(\tR\bcontexts\x12\x16\n" +
+	"\x06status\x18\r \x01(\tR\x06status\x12\x16\n" +
+	"\x06remind\x18\x0e \x03(\tR\x06remind\x122\n" +
+	"\x06custom\x18\x0f \x03(\v2\x1a.taski.v1.Task.CustomEntryR\x06custom\x12$\n" +
+	"\x05notes\x18\x10 \x03(\v2\x0e.taski.v1.NoteR\x05notes\x1a9\n" +
+	"\vCustomEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"J\n" +
+	"\x04Note\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"D\n" +
+	"\bDatabase\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12$\n" +
+	"\x05tasks\x18\x02 \x03(\v2\x0e.taski.v1.TaskR\x05tasks\"M\n" +
+	"\x10ListTasksRequest\x12#\n" +
+	"\rinclude_trash\x18\x01 \x01(\bR\fincludeTrash\x12\x14\n" +
+	"\x05where\x18\x02 \x01(\tR\x05where\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\xc2\x01\n" +
+	"\x0eAddTaskRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12,\n" +
+	"\x03due\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03due\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\tR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1a\n" +
+	"\bcontexts\x18\x06 \x03(\tR\bcontexts\"[\n" +
+	"\x11ChangeTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"#\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"$\n" +
+	"\x12RestoreTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x13\n" +
+	"\x11RestoreAllRequest\"g\n" +
+	"\x12ExportTasksRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12#\n" +
+	"\rinclude_trash\x18\x02 \x01(\bR\fincludeTrash\x12\x14\n" +
+	"\x05where\x18\x03 \x01(\tR\x05where\"/\n" +
+	"\x13ExportTasksResponse\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\"8\n" +
+	"\x11WatchTasksRequest\x12#\n" +
+	"\rinclude_trash\x18\x01 \x01(\bR\fincludeTrash2\xad\x04\n" +
+	"\x05Taski\x12;\n" +
+	"\tListTasks\x12\x1a.taski.v1.ListTasksRequest\x1a\x12.taski.v1.Database\x123\n" +
+	"\aGetTask\x12\x18.taski.v1.GetTaskRequest\x1a\x0e.taski.v1.Task\x123\n" +
+	"\aAddTask\x12\x18.taski.v1.AddTaskRequest\x1a\x0e.taski.v1.Task\x129\n" +
+	"\n" +
+	"ChangeTask\x12\x1b.taski.v1.ChangeTaskRequest\x1a\x0e.taski.v1.Task\x129\n" +
+	"\n" +
+	"DeleteTask\x12\x1b.taski.v1.DeleteTaskRequest\x1a\x0e.taski.v1.Task\x12;\n" +
+	"\vRestoreTask\x12\x1c.taski.v1.RestoreTaskRequest\x1a\x0e.taski.v1.Task\x12=\n" +
+	"\n" +
+	"RestoreAll\x12\x1b.taski.v1.RestoreAllRequest\x1a\x12.taski.v1.Database\x12J\n" +
+	"\vExportTasks\x12\x1c.taski.v1.ExportTasksRequest\x1a\x1d.taski.v1.ExportTasksResponse\x12?\n" +
+	"\n" +
+	"WatchTasks\x12\x1b.taski.v1.WatchTasksRequest\x1a\x12.taski.v1.Database0\x01B1Z/github.com/tristnaja/taski/api/taski/v1;taskiv1b\x06proto3"
+
+var (
+	file_api_taski_v1_taski_proto_rawDescOnce sync.Once
+	file_api_taski_v1_taski_proto_rawDescData []byte
+)
+
+func file_api_taski_v1_taski_proto_rawDescGZIP() []byte {
+	file_api_taski_v1_taski_proto_rawDescOnce.Do(func() {
+		file_api_taski_v1_taski_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_taski_v1_taski_proto_rawDesc), len(file_api_taski_v1_taski_proto_rawDesc)))
+	})
+	return file_api_taski_v1_taski_proto_rawDescData
+}
+
+var file_api_taski_v1_taski_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_api_taski_v1_taski_proto_goTypes = []any{
+	(*Task)(nil),                  // 0: taski.v1.Task
+	(*Note)(nil),                  // 1: taski.v1.Note
+	(*Database)(nil),              // 2: taski.v1.Database
+	(*ListTasksRequest)(nil),      // 3: taski.v1.ListTasksRequest
+	(*GetTaskRequest)(nil),        // 4: taski.v1.GetTaskRequest
+	(*AddTaskRequest)(nil),        // 5: taski.v1.AddTaskRequest
+	(*ChangeTaskRequest)(nil),     // 6: taski.v1.ChangeTaskRequest
+	(*DeleteTaskRequest)(nil),     // 7: taski.v1.DeleteTaskRequest
+	(*RestoreTaskRequest)(nil),    // 8: taski.v1.RestoreTaskRequest
+	(*RestoreAllRequest)(nil),     // 9: taski.v1.RestoreAllRequest
+	(*ExportTasksRequest)(nil),    // 10: taski.v1.ExportTasksRequest
+	(*ExportTasksResponse)(nil),   // 11: taski.v1.ExportTasksResponse
+	(*WatchTasksRequest)(nil),     // 12: taski.v1.WatchTasksRequest
+	nil,                           // 13: taski.v1.Task.CustomEntry
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_api_taski_v1_taski_proto_depIdxs = []int32{
+	14, // 0: taski.v1.Task.date:type_name -> google.protobuf.Timestamp
+	14, // 1: taski.v1.Task.due:type_name -> google.protobuf.Timestamp
+	14, // 2: taski.v1.Task.deleted_at:type_name -> google.protobuf.Timestamp
+	13, // 3: taski.v1.Task.custom:type_name -> taski.v1.Task.CustomEntry
+	1,  // 4: taski.v1.Task.notes:type_name -> taski.v1.Note
+	14, // 5: taski.v1.Note.time:type_name -> google.protobuf.Timestamp
+	0,  // 6: taski.v1.Database.tasks:type_name -> taski.v1.Task
+	14, // 7: taski.v1.AddTaskRequest.due:type_name -> google.protobuf.Timestamp
+	3,  // 8: taski.v1.Taski.ListTasks:input_type -> taski.v1.ListTasksRequest
+	4,  // 9: taski.v1.Taski.GetTask:input_type -> taski.v1.GetTaskRequest
+	5,  // 10: taski.v1.Taski.AddTask:input_type -> taski.v1.AddTaskRequest
+	6,  // 11: taski.v1.Taski.ChangeTask:input_type -> taski.v1.ChangeTaskRequest
+	7,  // 12: taski.v1.Taski.DeleteTask:input_type -> taski.v1.DeleteTaskRequest
+	8,  // 13: taski.v1.Taski.RestoreTask:input_type -> taski.v1.RestoreTaskRequest
+	9,  // 14: taski.v1.Taski.RestoreAll:input_type -> taski.v1.RestoreAllRequest
+	10, // 15: taski.v1.Taski.ExportTasks:input_type -> taski.v1.ExportTasksRequest
+	12, // 16: taski.v1.Taski.WatchTasks:input_type -> taski.v1.WatchTasksRequest
+	2,  // 17: taski.v1.Taski.ListTasks:output_type -> taski.v1.Database
+	0,  // 18: taski.v1.Taski.GetTask:output_type -> taski.v1.Task
+	0,  // 19: taski.v1.Taski.AddTask:output_type -> taski.v1.Task
+	0,  // 20: taski.v1.Taski.ChangeTask:output_type -> taski.v1.Task
+	0,  // 21: taski.v1.Taski.DeleteTask:output_type -> taski.v1.Task
+	0,  // 22: taski.v1.Taski.RestoreTask:output_type -> taski.v1.Task
+	2,  // 23: taski.v1.Taski.RestoreAll:output_type -> taski.v1.Database
+	11, // 24: taski.v1.Taski.ExportTasks:output_type -> taski.v1.ExportTasksResponse
+	2,  // 25: taski.v1.Taski.WatchTasks:output_type -> taski.v1.Database
+	17, // [17:26] is the sub-list for method output_type
+	8,  // [8:17] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_taski_v1_taski_proto_init() }
+func file_api_taski_v1_taski_proto_init() {
+	if File_api_taski_v1_taski_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_taski_v1_taski_proto_rawDesc), len(file_api_taski_v1_taski_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_taski_v1_taski_proto_goTypes,
+		DependencyIndexes: file_api_taski_v1_taski_proto_depIdxs,
+		MessageInfos:      file_api_taski_v1_taski_proto_msgTypes,
+	}.Build()
+	File_api_taski_v1_taski_proto = out.File
+	file_api_taski_v1_taski_proto_goTypes = nil
+	file_api_taski_v1_taski_proto_depIdxs = nil
+}
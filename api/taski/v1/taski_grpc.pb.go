@@ -0,0 +1,439 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: api/taski/v1/taski.proto
+
+package taskiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Taski_ListTasks_FullMethodName   = "/taski.v1.Taski/ListTasks"
+	Taski_GetTask_FullMethodName     = "/taski.v1.Taski/GetTask"
+	Taski_AddTask_FullMethodName     = "/taski.v1.Taski/AddTask"
+	Taski_ChangeTask_FullMethodName  = "/taski.v1.Taski/ChangeTask"
+	Taski_DeleteTask_FullMethodName  = "/taski.v1.Taski/DeleteTask"
+	Taski_RestoreTask_FullMethodName = "/taski.v1.Taski/RestoreTask"
+	Taski_RestoreAll_FullMethodName  = "/taski.v1.Taski/RestoreAll"
+	Taski_ExportTasks_FullMethodName = "/taski.v1.Taski/ExportTasks"
+	Taski_WatchTasks_FullMethodName  = "/taski.v1.Taski/WatchTasks"
+)
+
+// TaskiClient is the client API for Taski service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Taski covers the CLI commands. Requests are authenticated with an
+// "authorization: Bearer <token>" metadata entry.
+type TaskiClient interface {
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*Database, error)
+	GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	ChangeTask(ctx context.Context, in *ChangeTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	RestoreAll(ctx context.Context, in *RestoreAllRequest, opts ...grpc.CallOption) (*Database, error)
+	ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (*ExportTasksResponse, error)
+	// WatchTasks sends the current database and then a new snapshot every
+	// time the database file changes, from any process.
+	WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Database], error)
+}
+
+type taskiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskiClient(cc grpc.ClientConnInterface) TaskiClient {
+	return &taskiClient{cc}
+}
+
+func (c *taskiClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*Database, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Database)
+	err := c.cc.Invoke(ctx, Taski_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, Taski_GetTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, Taski_AddTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) ChangeTask(ctx context.Context, in *ChangeTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, Taski_ChangeTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, Taski_DeleteTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, Taski_RestoreTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) RestoreAll(ctx context.Context, in *RestoreAllRequest, opts ...grpc.CallOption) (*Database, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Database)
+	err := c.cc.Invoke(ctx, Taski_RestoreAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (*ExportTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTasksResponse)
+	err := c.cc.Invoke(ctx, Taski_ExportTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskiClient) WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Database], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Taski_ServiceDesc.Streams[0], Taski_WatchTasks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTasksRequest, Database]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Taski_WatchTasksClient = grpc.ServerStreamingClient[Database]
+
+// TaskiServer is the server API for Taski service.
+// All implementations must embed UnimplementedTaskiServer
+// for forward compatibility.
+//
+// Taski covers the CLI commands. Requests are authenticated with an
+// "authorization: Bearer <token>" metadata entry.
+type TaskiServer interface {
+	ListTasks(context.Context, *ListTasksRequest) (*Database, error)
+	GetTask(context.Context, *GetTaskRequest) (*Task, error)
+	AddTask(context.Context, *AddTaskRequest) (*Task, error)
+	ChangeTask(context.Context, *ChangeTaskRequest) (*Task, error)
+	DeleteTask(context.Context, *DeleteTaskRequest) (*Task, error)
+	RestoreTask(context.Context, *RestoreTaskRequest) (*Task, error)
+	RestoreAll(context.Context, *RestoreAllRequest) (*Database, error)
+	ExportTasks(context.Context, *ExportTasksRequest) (*ExportTasksResponse, error)
+	// WatchTasks sends the current database and then a new snapshot every
+	// time the database file changes, from any process.
+	WatchTasks(*WatchTasksRequest, grpc.ServerStreamingServer[Database]) error
+	mustEmbedUnimplementedTaskiServer()
+}
+
+// UnimplementedTaskiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTaskiServer struct{}
+
+func (UnimplementedTaskiServer) ListTasks(context.Context, *ListTasksRequest) (*Database, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTaskiServer) GetTask(context.Context, *GetTaskRequest) (*Task, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTask not implemented")
+}
+func (UnimplementedTaskiServer) AddTask(context.Context, *AddTaskRequest) (*Task, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddTask not implemented")
+}
+func (UnimplementedTaskiServer) ChangeTask(context.Context, *ChangeTaskRequest) (*Task, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeTask not implemented")
+}
+func (UnimplementedTaskiServer) DeleteTask(context.Context, *DeleteTaskRequest) (*Task, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTask not implemented")
+}
+func (UnimplementedTaskiServer) RestoreTask(context.Context, *RestoreTaskRequest) (*Task, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreTask not implemented")
+}
+func (UnimplementedTaskiServer) RestoreAll(context.Context, *RestoreAllRequest) (*Database, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreAll not implemented")
+}
+func (UnimplementedTaskiServer) ExportTasks(context.Context, *ExportTasksRequest) (*ExportTasksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportTasks not implemented")
+}
+func (UnimplementedTaskiServer) WatchTasks(*WatchTasksRequest, grpc.ServerStreamingServer[Database]) error {
+	return status.Error(codes.Unimplemented, "method WatchTasks not implemented")
+}
+func (UnimplementedTaskiServer) mustEmbedUnimplementedTaskiServer() {}
+func (UnimplementedTaskiServer) testEmbeddedByValue()               {}
+
+// UnsafeTaskiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskiServer will
+// result in compilation errors.
+type UnsafeTaskiServer interface {
+	mustEmbedUnimplementedTaskiServer()
+}
+
+func RegisterTaskiServer(s grpc.ServiceRegistrar, srv TaskiServer) {
+	// If the following call panics, it indicates UnimplementedTaskiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Taski_ServiceDesc, srv)
+}
+
+func _Taski_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_GetTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).GetTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_GetTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).GetTask(ctx, req.(*GetTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).AddTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_AddTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).AddTask(ctx, req.(*AddTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_ChangeTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).ChangeTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_ChangeTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).ChangeTask(ctx, req.(*ChangeTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).DeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_DeleteTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).DeleteTask(ctx, req.(*DeleteTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_RestoreTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).RestoreTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_RestoreTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).RestoreTask(ctx, req.(*RestoreTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_RestoreAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).RestoreAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_RestoreAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).RestoreAll(ctx, req.(*RestoreAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_ExportTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskiServer).ExportTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Taski_ExportTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskiServer).ExportTasks(ctx, req.(*ExportTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taski_WatchTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskiServer).WatchTasks(m, &grpc.GenericServerStream[WatchTasksRequest, Database]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Taski_WatchTasksServer = grpc.ServerStreamingServer[Database]
+
+// Taski_ServiceDesc is the grpc.ServiceDesc for Taski service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Taski_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "taski.v1.Taski",
+	HandlerType: (*TaskiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTasks",
+			Handler:    _Taski_ListTasks_Handler,
+		},
+		{
+			MethodName: "GetTask",
+			Handler:    _Taski_GetTask_Handler,
+		},
+		{
+			MethodName: "AddTask",
+			Handler:    _Taski_AddTask_Handler,
+		},
+		{
+			MethodName: "ChangeTask",
+			Handler:    _Taski_ChangeTask_Handler,
+		},
+		{
+			MethodName: "DeleteTask",
+			Handler:    _Taski_DeleteTask_Handler,
+		},
+		{
+			MethodName: "RestoreTask",
+			Handler:    _Taski_RestoreTask_Handler,
+		},
+		{
+			MethodName: "RestoreAll",
+			Handler:    _Taski_RestoreAll_Handler,
+		},
+		{
+			MethodName: "ExportTasks",
+			Handler:    _Taski_ExportTasks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTasks",
+			Handler:       _Taski_WatchTasks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/taski/v1/taski.proto",
+}
@@ -1,51 +1,213 @@
 package cmd
 
 import (
-	"flag"
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/contexts"
+	"github.com/tristnaja/taski/internal/quickadd"
+	"github.com/tristnaja/taski/internal/recur"
+	"github.com/tristnaja/taski/internal/remind"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func RunAdd(args []string, fileName string) error {
-	cmd := flag.NewFlagSet("add", flag.ContinueOnError)
+	cmd := newFlagSet("add")
 	var title string
 	var description string
+	var due string
+	var priority string
+	var repeat string
+	var reminders string
+	var sets assignments
 
 	cmd.StringVar(&title, "title", "", "Task Title")
 	cmd.StringVar(&title, "t", "", "Task Title (shorthand)")
-	cmd.StringVar(&description, "desc", "", "Task Description")
+	cmd.StringVar(&description, "desc", "", "Task Description (optional)")
 	cmd.StringVar(&description, "d", "", "Task Description (shorthand)")
+	cmd.StringVar(&due, "due", "", "Due Date (YYYY-MM-DD, \"YYYY-MM-DD HH:MM\", tomorrow, friday, 3d)")
+	cmd.StringVar(&priority, "priority", "", "Priority (high, medium, low)")
+	cmd.StringVar(&priority, "p", "", "Priority (shorthand)")
+	cmd.StringVar(&repeat, "repeat", "", "Repeat From the Due Date (daily, weekly, weekdays, monthly, yearly or an RRULE)")
+	cmd.StringVar(&repeat, "r", "", "Repeat Rule (shorthand)")
+	cmd.StringVar(&reminders, "remind", "", "Remind Before the Due Date, e.g. 30m-before,1d-before, due or none")
+	cmd.Var(&sets, "set", "Set a Custom Field, name=value (repeatable)")
 
-	err := cmd.Parse(args)
+	positional, err := parseArgs(cmd, args)
 
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return err
 	}
 
-	if title == "" || description == "" {
+	var task taski.Task
+
+	if len(positional) > 0 {
+		if title != "" {
+			return Usagef("give the title either with --title or as text, not both")
+		}
+
+		task, err = quickadd.Parse(strings.Join(positional, " "), time.Now())
+
+		if err != nil {
+			return Usagef("parsing task: %w", err)
+		}
+	} else {
+		task.Title = title
+	}
+
+	if task.Title == "" {
 		cmd.Usage()
-		return fmt.Errorf("unfilled arguments")
+		return Usagef("unfilled arguments")
+	}
+
+	task.Description = description
+	task.Date = time.Now()
+
+	if due != "" {
+		task.Due, err = parseDue(due)
+
+		if err != nil {
+			return Usagef("parsing due date: %w", err)
+		}
 	}
 
-	task := io.Task{
-		Title:       title,
-		Description: description,
-		Date:        time.Now(),
-		IsDeleted:   false,
+	if priority != "" {
+		task.Priority = priority
 	}
 
-	err = io.AddTask(task, fileName)
+	if repeat != "" {
+		if task.Due == nil {
+			return Usagef("--repeat needs a due date to repeat from")
+		}
+
+		rule, err := recur.Parse(repeat)
+
+		if err != nil {
+			return Usagef("parsing repeat rule: %w", err)
+		}
+
+		task.Recurrence = rule.String()
+	}
+
+	if reminders != "" {
+		if task.Due == nil {
+			return Usagef("--remind needs a due date to remind before")
+		}
+
+		task.Remind, err = remind.Normalize(reminders)
+
+		if err != nil {
+			return Usagef("parsing reminders: %w", err)
+		}
+	}
+
+	custom, err := sets.custom(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	uda.Apply(&task, custom)
+
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return fmt.Errorf("adding task: %w\n", err)
+	}
+
+	contexts.Apply(&task, active)
+
+	task, err = taski.Open(fileName).Add(context.Background(), task)
 
 	if err != nil {
-		return fmt.Errorf("adding task: %v\n", err)
+		return fmt.Errorf("adding task: %w\n", err)
 	}
 
 	fmt.Println("Added New Task:")
-	fmt.Printf("Title: %v\n", title)
-	fmt.Printf("Description: %v\n", description)
+	fmt.Printf("Title: %v\n", task.Title)
+	fmt.Printf("Index: %d\n", task.ID)
+
+	if task.Description != "" {
+		fmt.Printf("Description: %v\n", task.Description)
+	}
+
+	if len(task.Tags) > 0 || len(task.Contexts) > 0 {
+		fmt.Printf("Labels: %v\n", labels(task))
+	}
+
+	if task.Due != nil {
+		fmt.Printf("Due: %v\n", task.Due.Format("02 Jan 2006, 15:04"))
+	}
+
+	if task.Priority != "" {
+		fmt.Printf("Priority: %v\n", task.Priority)
+	}
+
+	if task.Recurrence != "" {
+		fmt.Printf("Repeats: %v\n", task.Recurrence)
+	}
+
+	if len(task.Remind) > 0 {
+		fmt.Printf("Reminders: %v\n", strings.Join(task.Remind, ", "))
+	}
+
+	if len(task.Custom) > 0 {
+		fmt.Printf("Fields: %v\n", customFields(task.Custom))
+	}
+
 	fmt.Println("\nTo view, type: taski view")
 
 	return nil
 }
+
+func parseDue(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	due, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+
+	if err == nil {
+		return &due, nil
+	}
+
+	due, err = quickadd.ParseDate(value, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &due, nil
+}
+
+// labels renders the tags and contexts of a task the way they are typed.
+func labels(task taski.Task) string {
+	var words []string
+
+	for _, tag := range task.Tags {
+		words = append(words, "+"+tag)
+	}
+
+	for _, context := range task.Contexts {
+		words = append(words, "@"+context)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// customFields renders custom field values as name=value pairs in name
+// order.
+func customFields(custom map[string]string) string {
+	var pairs []string
+
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		pairs = append(pairs, name+"="+custom[name])
+	}
+
+	return strings.Join(pairs, ", ")
+}
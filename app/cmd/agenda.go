@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/agenda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunAgenda(args []string, fileName string) error {
+	cmd := newFlagSet("agenda")
+	var days int
+	var from, where string
+
+	cmd.IntVar(&days, "days", 7, "Number of Days to List")
+	cmd.IntVar(&days, "n", 7, "Number of Days to List (shorthand)")
+	cmd.StringVar(&from, "from", "today", "First Day to List, e.g. 2026-11-02 or monday")
+	cmd.StringVar(&where, "where", "", "Only List Tasks Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if days < 1 {
+		return Usagef("--days must be at least 1, got %d", days)
+	}
+
+	now := time.Now()
+	start, err := parseDue(from)
+
+	if err != nil {
+		return Usagef("parsing --from: %w", err)
+	}
+
+	first := agenda.Day(*start)
+	end := first.AddDate(0, 0, days)
+
+	where, err = narrow(where, fileName)
+
+	if err != nil {
+		return fmt.Errorf("listing agenda: %w\n", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{Where: where})
+
+	if err != nil {
+		return fmt.Errorf("listing agenda: %w\n", err)
+	}
+
+	color := useColor()
+	today := agenda.Day(now)
+
+	if !first.After(today) {
+		if overdue := agenda.Overdue(tasks, first); len(overdue) > 0 {
+			fmt.Println(paint("Overdue:", "1;31", color))
+
+			for _, entry := range overdue {
+				fmt.Println(agendaLine(entry, "2006-01-02", color))
+			}
+
+			fmt.Println()
+		}
+	}
+
+	entries := agenda.Entries(tasks, first, end)
+
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		heading := day.Format("Mon 02 Jan 2006") + ":"
+
+		if day.Equal(today) {
+			heading = day.Format("Mon 02 Jan 2006") + " (today):"
+		}
+
+		fmt.Println(paint(heading, "1", color))
+		empty := true
+
+		for _, entry := range entries {
+			if agenda.Day(entry.Due).Equal(day) {
+				fmt.Println(agendaLine(entry, "15:04", color))
+				empty = false
+			}
+		}
+
+		if empty {
+			fmt.Println(paint("  -", "2", color))
+		}
+	}
+
+	return nil
+}
+
+// agendaLine prints an entry as its id, due time (midnight is left out),
+// title and labels; projected occurrences are marked with ↻.
+func agendaLine(entry agenda.Entry, layout string, color bool) string {
+	when := strings.Repeat(" ", len(layout))
+	due := entry.Due.Local()
+
+	if layout != "15:04" || due.Hour() != 0 || due.Minute() != 0 {
+		when = due.Format(layout)
+	}
+
+	line := fmt.Sprintf("  #%-3d %s  %s", entry.Task.ID, when, entry.Task.Title)
+
+	if label := labels(entry.Task); label != "" {
+		line += " " + paint(label, "36", color)
+	}
+
+	if entry.Projected {
+		line += paint(" ↻", "2", color)
+	}
+
+	return line
+}
+
+func RunCalendar(args []string, fileName string) error {
+	cmd := newFlagSet("calendar")
+	var month, where string
+
+	cmd.StringVar(&month, "month", "", "Month to Show as YYYY-MM (default: this month)")
+	cmd.StringVar(&month, "m", "", "Month to Show (shorthand)")
+	cmd.StringVar(&where, "where", "", "Only Count Tasks Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	if month != "" {
+		first, err = time.ParseInLocation("2006-01", month, time.Local)
+
+		if err != nil {
+			return Usagef("invalid month %q, expected YYYY-MM", month)
+		}
+	}
+
+	next := first.AddDate(0, 1, 0)
+
+	where, err = narrow(where, fileName)
+
+	if err != nil {
+		return fmt.Errorf("showing calendar: %w\n", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{Where: where})
+
+	if err != nil {
+		return fmt.Errorf("showing calendar: %w\n", err)
+	}
+
+	entries := agenda.Entries(tasks, first, next)
+	counts := agenda.ByDay(entries)
+	color := useColor()
+	today := agenda.Day(now)
+
+	fmt.Println(paint(first.Format("January 2006"), "1", color))
+	fmt.Println("Mon     Tue     Wed     Thu     Fri     Sat     Sun")
+
+	// Weeks start on Monday; blank cells pad the first week.
+	line := strings.Repeat(" ", 8*((int(first.Weekday())+6)%7))
+
+	for day := first; day.Before(next); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", day.Day())
+
+		if count := counts[day]; count > 0 {
+			cell += fmt.Sprintf(" (%d)", count)
+		}
+
+		padding := strings.Repeat(" ", 8-len(cell))
+
+		switch {
+		case day.Equal(today):
+			cell = paint(cell, "7", color)
+		case counts[day] > 0:
+			cell = paint(cell, "1", color)
+		}
+
+		line += cell
+
+		if day.Weekday() == time.Sunday {
+			fmt.Println(strings.TrimRight(line, " "))
+			line = ""
+		} else {
+			line += padding
+		}
+	}
+
+	if line != "" {
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	projected := 0
+
+	for _, entry := range entries {
+		if entry.Projected {
+			projected++
+		}
+	}
+
+	fmt.Printf("\nDue this month: %d (%d from repeating tasks)\n", len(entries), projected)
+	fmt.Printf("To list them by day, type: taski agenda --from %v --days %d\n", first.Format("2006-01-02"), next.AddDate(0, 0, -1).Day())
+
+	return nil
+}
+
+func paint(text string, style string, color bool) string {
+	if !color {
+		return text
+	}
+
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/filter"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+// parseArgs parses flags found anywhere among the positional arguments, so
+// "taski add Buy milk -d notes" works like "taski add -d notes Buy milk",
+// and returns the positional arguments.
+func parseArgs(cmd *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		err := cmd.Parse(args)
+
+		if err != nil {
+			return nil, Usagef("parsing arguments: %w", err)
+		}
+
+		args = cmd.Args()
+
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseIDs reads task IDs given as separate arguments, comma separated
+// lists and ranges, e.g. "3-7,10 12". Duplicates are dropped. A range must
+// end before count, the number of tasks, so a slip such as 0-999999999
+// fails at once instead of listing every number.
+func parseIDs(values []string, count int) ([]int, error) {
+	var ids []int
+	seen := make(map[int]bool)
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part == "" {
+				continue
+			}
+
+			from, to, isRange := strings.Cut(part, "-")
+			first, err := strconv.Atoi(from)
+
+			if err != nil || first < 0 {
+				return nil, Usagef("invalid task id %q", part)
+			}
+
+			last := first
+
+			if isRange {
+				last, err = strconv.Atoi(to)
+
+				if err != nil || last < first {
+					return nil, Usagef("invalid task range %q", part)
+				}
+
+				if last >= count {
+					return nil, &io.IndexError{Index: max(first, count)}
+				}
+			}
+
+			for id := first; id <= last; id++ {
+				add(id)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// taskCount returns the number of tasks in the database, trash included,
+// which is where the task IDs end.
+func taskCount(fileName string) (int, error) {
+	db, err := io.ReadAll(fileName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(db.Tasks), nil
+}
+
+// selection is the set of tasks a bulk command works on: either explicit
+// IDs or every task matching a --where filter.
+type selection struct {
+	ids   []int
+	where string
+	match filter.Filter
+}
+
+func newSelection(positional []string, index int, where string, fileName string, count int) (selection, error) {
+	ids, err := parseIDs(positional, count)
+
+	if err != nil {
+		return selection{}, err
+	}
+
+	if index != -1 {
+		ids = append([]int{index}, ids...)
+	}
+
+	if where == "" {
+		return selection{ids: ids}, nil
+	}
+
+	if len(ids) > 0 {
+		return selection{}, Usagef("give task ids or --where, not both")
+	}
+
+	fields, err := uda.Load(fileName)
+
+	if err != nil {
+		return selection{}, err
+	}
+
+	match, err := filter.ParseFields(where, fields)
+
+	if err != nil {
+		return selection{}, Usagef("parsing filter: %w", err)
+	}
+
+	return selection{where: where, match: match}, nil
+}
+
+func (s selection) empty() bool {
+	return len(s.ids) == 0 && s.where == ""
+}
+
+// resolve returns the selected IDs. With --where these are the matching
+// tasks that are in trash when trash is set, or active otherwise.
+func (s selection) resolve(db *taski.Database, trash bool) ([]int, error) {
+	if s.where == "" {
+		return s.ids, nil
+	}
+
+	var ids []int
+
+	for _, task := range db.Tasks {
+		if task.IsDeleted == trash && s.match.Match(task) {
+			ids = append(ids, task.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: nothing matches %q", taski.ErrNotFound, s.where)
+	}
+
+	return ids, nil
+}
+
+// assignments collects the values of the repeatable --set name=value flag.
+type assignments []string
+
+func (a *assignments) String() string {
+	return strings.Join(*a, ", ")
+}
+
+func (a *assignments) Set(value string) error {
+	*a = append(*a, value)
+	return nil
+}
+
+// custom checks the assignments against the custom fields declared in the
+// config of the database, which is only read when --set was given.
+func (a assignments) custom(fileName string) (map[string]string, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	fields, err := uda.Load(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := fields.Assign(a, time.Now())
+
+	if err != nil {
+		return nil, Usagef("parsing --set: %w", err)
+	}
+
+	return values, nil
+}
+
+// splitList splits a comma separated flag value, dropping blank items.
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/contexts"
+	"github.com/tristnaja/taski/internal/quickadd"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+// batchOp is one parsed line of a batch script. It changes the in-memory
+// database and describes what it did.
+type batchOp func(db *taski.Database) ([]string, error)
+
+var errDryRun = errors.New("dry run")
+
+func RunBatch(args []string, fileName string) error {
+	cmd := newFlagSet("batch")
+	var input string
+	var dryRun bool
+
+	cmd.StringVar(&input, "file", "", "Batch Script (default: stdin)")
+	cmd.StringVar(&input, "f", "", "Batch Script (shorthand)")
+	cmd.BoolVar(&dryRun, "dry-run", false, "Check The Script Without Writing Anything")
+	cmd.BoolVar(&dryRun, "n", false, "Check The Script Without Writing Anything (shorthand)")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = os.Stdin
+
+	if input != "" {
+		file, err := os.Open(input)
+
+		if err != nil {
+			return fmt.Errorf("opening batch script: %w", err)
+		}
+
+		defer file.Close()
+		reader = file
+	}
+
+	type line struct {
+		number int
+		op     batchOp
+	}
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("reading batch script: %w", err)
+	}
+
+	var lines []line
+	scanner := bufio.NewScanner(reader)
+
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		op, err := parseBatchLine(text, fileName, &count)
+
+		if err != nil {
+			return fmt.Errorf("line %d: %w", number, err)
+		}
+
+		lines = append(lines, line{number, op})
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		return fmt.Errorf("reading batch script: %w", err)
+	}
+
+	var report []string
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		for _, l := range lines {
+			done, err := l.op(db)
+
+			if err != nil {
+				return fmt.Errorf("line %d: %w", l.number, err)
+			}
+
+			report = append(report, done...)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return fmt.Errorf("batch aborted, nothing was changed: %w", err)
+	}
+
+	if dryRun {
+		fmt.Println("Dry Run, Nothing Written:")
+	} else {
+		fmt.Printf("Applied %d Operation(s):\n", len(lines))
+	}
+
+	for _, entry := range report {
+		fmt.Println(entry)
+	}
+
+	return nil
+}
+
+// parseBatchLine parses one line of a batch script. count is the number of
+// tasks when the line runs; an add raises it for the lines after it.
+func parseBatchLine(text string, fileName string, count *int) (batchOp, error) {
+	words, err := splitWords(text)
+
+	if err != nil {
+		return nil, Usagef("%w", err)
+	}
+
+	command := Lookup(words[0])
+
+	if command == nil {
+		return nil, Usagef("unknown batch command %q, usable: add, change, delete, restore, done", words[0])
+	}
+
+	cmd := flag.NewFlagSet(command.Name, flag.ContinueOnError)
+	cmd.SetOutput(io.Discard)
+
+	var sets assignments
+
+	if command.Name == "add" {
+		var description string
+
+		cmd.StringVar(&description, "desc", "", "")
+		cmd.StringVar(&description, "d", "", "")
+		cmd.Var(&sets, "set", "")
+
+		positional, err := parseArgs(cmd, words[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		task, err := quickadd.Parse(strings.Join(positional, " "), time.Now())
+
+		if err != nil {
+			return nil, Usagef("parsing task: %w", err)
+		}
+
+		task.Description = description
+		task.Date = time.Now()
+
+		custom, err := sets.custom(fileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		uda.Apply(&task, custom)
+
+		active, err := contexts.Active(fileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		contexts.Apply(&task, active)
+		*count++
+
+		return func(db *taski.Database) ([]string, error) {
+			added := db.Add(task)
+			return []string{fmt.Sprintf("added %d %s", added.ID, added.Title)}, nil
+		}, nil
+	}
+
+	var where, title, description string
+	var undo bool
+
+	cmd.StringVar(&where, "where", "", "")
+	cmd.StringVar(&where, "w", "", "")
+
+	switch command.Name {
+	case "change":
+		cmd.StringVar(&title, "title", "", "")
+		cmd.StringVar(&title, "t", "", "")
+		cmd.StringVar(&description, "desc", "", "")
+		cmd.StringVar(&description, "d", "", "")
+		cmd.Var(&sets, "set", "")
+	case "done":
+		cmd.BoolVar(&undo, "undo", false, "")
+	case "delete", "restore":
+	default:
+		return nil, Usagef("%q cannot be used in a batch, usable: add, change, delete, restore, done", command.Name)
+	}
+
+	positional, err := parseArgs(cmd, words[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := newSelection(positional, -1, where, fileName, *count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if selected.empty() {
+		return nil, Usagef("%s needs task ids or --where", command.Name)
+	}
+
+	if command.Name == "change" && title == "" && description == "" && len(sets) == 0 {
+		return nil, taski.ErrNoChange
+	}
+
+	custom, err := sets.custom(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(db *taski.Database) ([]string, error) {
+		ids, err := selected.resolve(db, command.Name == "restore")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var report []string
+		verb := pastTense[command.Name]
+
+		if command.Name == "done" && undo {
+			verb = "reopened"
+		}
+
+		for _, id := range ids {
+			switch command.Name {
+			case "change":
+				err = db.Update(id, func(task *taski.Task) error {
+					if task.IsDeleted {
+						return fmt.Errorf("task %d is in trash: %w", id, taski.ErrNotFound)
+					}
+
+					if title != "" {
+						task.Title = title
+					}
+
+					if description != "" {
+						task.Description = description
+					}
+
+					uda.Apply(task, custom)
+
+					return nil
+				})
+			case "delete":
+				err = db.Delete(id)
+			case "restore":
+				err = db.Restore(id)
+			case "done":
+				status := taski.StatusDone
+
+				if undo {
+					status = taski.StatusPending
+				}
+
+				err = setStatus(db, id, status)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			report = append(report, fmt.Sprintf("%s %d", verb, id))
+		}
+
+		return report, nil
+	}, nil
+}
+
+var pastTense = map[string]string{
+	"change":  "changed",
+	"delete":  "deleted",
+	"restore": "restored",
+	"done":    "completed",
+}
+
+// splitWords splits a line into words like a shell would, honouring single
+// and double quotes and backslash escapes.
+func splitWords(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tristnaja/taski/internal/board"
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunBoard(args []string, fileName string) error {
+	cmd := newFlagSet("board")
+	var field, where string
+
+	cmd.StringVar(&field, "by", "", "Group Columns by status, tag, context or priority (default: from config, else status)")
+	cmd.StringVar(&field, "b", "", "Field to Group by (shorthand)")
+	cmd.StringVar(&where, "where", "", "Only Show Tasks Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	where, err = narrow(where, fileName)
+
+	if err != nil {
+		return fmt.Errorf("showing board: %w\n", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{Where: where})
+
+	if err != nil {
+		return fmt.Errorf("showing board: %w\n", err)
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("showing board: %w\n", err)
+	}
+
+	b, err := newBoard(cfg, field, tasks)
+
+	if err != nil {
+		return err
+	}
+
+	err = b.Render(os.Stdout, terminalWidth(), useColor())
+
+	if err != nil {
+		return fmt.Errorf("showing board: %w\n", err)
+	}
+
+	for _, column := range b.Columns {
+		warnOverLimit(column)
+	}
+
+	return nil
+}
+
+func RunMove(args []string, fileName string) error {
+	cmd := newFlagSet("move")
+	var field string
+
+	cmd.StringVar(&field, "by", "", "Board Field the Column Belongs to (default: from config, else status)")
+	cmd.StringVar(&field, "b", "", "Board Field (shorthand)")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 2 {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	column := positional[len(positional)-1]
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("moving task: %w\n", err)
+	}
+
+	ids, err := parseIDs(positional[:len(positional)-1], count)
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("moving task: %w\n", err)
+	}
+
+	var moved board.Board
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		b, err := newBoard(cfg, field, activeTasks(db))
+
+		if err != nil {
+			return err
+		}
+
+		if _, found := b.Column(column); !found && len(cfg.Board.Columns) > 0 && b.Field == boardField(cfg, "") {
+			return fmt.Errorf("%w: no column %q on the board", taski.ErrInvalid, column)
+		}
+
+		for _, id := range ids {
+			task, err := db.Get(id)
+
+			if err != nil {
+				return err
+			}
+
+			if task.IsDeleted {
+				return fmt.Errorf("task %d is in trash: %w", id, taski.ErrNotFound)
+			}
+
+			err = db.Update(id, func(task *taski.Task) error {
+				return b.Move(task, column)
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		moved, err = newBoard(cfg, field, activeTasks(db))
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("moving task: %w\n", err)
+	}
+
+	if len(ids) == 1 {
+		fmt.Printf("Moved Task to %v:\n", column)
+	} else {
+		fmt.Printf("Moved Tasks to %v:\n", column)
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Index: %d\n", id)
+	}
+
+	if target, found := moved.Column(column); found {
+		warnOverLimit(target)
+	}
+
+	fmt.Println("\nTo view, type: taski board")
+
+	return nil
+}
+
+// newBoard builds the board for field with the columns and WIP limits from
+// config.json. Configured columns only apply to the configured field.
+func newBoard(cfg config.Config, field string, tasks []taski.Task) (board.Board, error) {
+	field = boardField(cfg, field)
+	var columns []string
+
+	if field == boardField(cfg, "") {
+		columns = cfg.Board.Columns
+	}
+
+	b, err := board.New(tasks, field, columns, cfg.Board.WIP)
+
+	if err != nil {
+		return board.Board{}, Usagef("%w", err)
+	}
+
+	return b, nil
+}
+
+// boardField is field, or the configured field, or status.
+func boardField(cfg config.Config, field string) string {
+	switch {
+	case field != "":
+		return field
+	case cfg.Board.Field != "":
+		return cfg.Board.Field
+	default:
+		return "status"
+	}
+}
+
+func activeTasks(db *taski.Database) []taski.Task {
+	var active []taski.Task
+
+	for _, task := range db.Tasks {
+		if !task.IsDeleted {
+			active = append(active, task)
+		}
+	}
+
+	return active
+}
+
+func warnOverLimit(column board.Column) {
+	if column.Over() {
+		fmt.Fprintf(os.Stderr, "Warning: %v holds %d tasks, over its WIP limit of %d\n", column.Name, len(column.Tasks), column.Limit)
+	}
+}
@@ -1,17 +1,20 @@
 package cmd
 
 import (
-	"flag"
+	"context"
 	"fmt"
 
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func RunChange(args []string, fileName string) error {
-	cmd := flag.NewFlagSet("change", flag.ContinueOnError)
+	cmd := newFlagSet("change")
 	var index int
 	var title string
 	var description string
+	var where string
+	var sets assignments
 
 	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
 	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
@@ -19,28 +22,96 @@ func RunChange(args []string, fileName string) error {
 	cmd.StringVar(&title, "t", "", "New Task Title (shorthand)")
 	cmd.StringVar(&description, "desc", "", "New Task Description")
 	cmd.StringVar(&description, "d", "", "New Task Description (shorthand)")
+	cmd.StringVar(&where, "where", "", "Change Every Active Task Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+	cmd.Var(&sets, "set", "Set a Custom Field, name=value, or remove it with name= (repeatable)")
 
-	err := cmd.Parse(args)
+	positional, err := parseArgs(cmd, args)
 
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return err
 	}
 
-	if index == -1 && title == "" || index == -1 && description == "" {
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("changing task: %w\n", err)
+	}
+
+	selected, err := newSelection(positional, index, where, fileName, count)
+
+	if err != nil {
+		return err
+	}
+
+	if selected.empty() {
 		cmd.Usage()
-		return fmt.Errorf("unfilled arguments")
+		return Usagef("unfilled arguments")
 	}
 
-	err = io.ChangeTask(fileName, index, title, description)
+	if title == "" && description == "" && len(sets) == 0 {
+		return fmt.Errorf("changing task: %w\n", taski.ErrNoChange)
+	}
+
+	custom, err := sets.custom(fileName)
 
 	if err != nil {
-		return fmt.Errorf("changing task: %v\n", err)
+		return err
+	}
+
+	var ids []int
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		ids, err = selected.resolve(db, false)
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			err = db.Update(id, func(task *taski.Task) error {
+				if task.IsDeleted {
+					return fmt.Errorf("task %d is in trash: %w", id, taski.ErrNotFound)
+				}
+
+				if title != "" {
+					task.Title = title
+				}
+
+				if description != "" {
+					task.Description = description
+				}
+
+				uda.Apply(task, custom)
+
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("changing task: %w\n", err)
 	}
 
 	fmt.Println("Changed Task:")
 	fmt.Printf("Title: %v\n", title)
-	fmt.Printf("Index: %d\n", index)
+
+	for _, id := range ids {
+		fmt.Printf("Index: %d\n", id)
+	}
+
 	fmt.Printf("Description: %v\n", description)
+
+	if len(custom) > 0 {
+		fmt.Printf("Fields: %v\n", customFields(custom))
+	}
+
 	fmt.Println("\nTo view, type: taski view")
 
 	return nil
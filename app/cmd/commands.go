@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Command struct {
+	Name    string
+	Aliases []string
+	// Usage is the synopsis after "taski", one line per form.
+	Usage   string
+	Summary string
+	// Mutates marks commands that change the database, which are
+	// committed to git when git sync is configured.
+	Mutates bool
+	Hidden  bool
+	Run     func(args []string, fileName string) error
+}
+
+// commands is the command table; it is filled in init because help and
+// completion refer back to it.
+var commands []*Command
+
+func init() {
+	commands = []*Command{
+		{Name: "add", Aliases: []string{"new"}, Mutates: true, Run: RunAdd,
+			Usage:   "add \"<title> [+tag] [@context] [due:<date>] [!<priority>]\" [--desc <description>]\nadd --title <title> [--desc <description>] [--due <date>] [--priority <level>] [--repeat <rule>] [--remind <when>] [--set <field>=<value>]...",
+			Summary: "Add a new task. Words starting with + are tags, @ contexts."},
+		{Name: "view", Aliases: []string{"ls", "list"}, Run: RunView,
+			Usage:   "view [--compact] [--where <filter>] [--sort <keys>] [--columns <fields>]\nview --long [--raw]",
+			Summary: "Display all active tasks as a table, or with their descriptions."},
+		{Name: "show", Aliases: []string{"info"}, Run: RunShow,
+			Usage:   "show <id|range>... [--raw]",
+			Summary: "Show every detail of a task, with its description rendered as Markdown."},
+		{Name: "change", Aliases: []string{"modify", "mod"}, Mutates: true, Run: RunChange,
+			Usage:   "change <id|range>... [--title <title>] [--desc <description>] [--set <field>=<value>]...\nchange --where <filter> [--title <title>] [--desc <description>] [--set <field>=<value>]...",
+			Summary: "Change the title, description and/or custom fields of tasks."},
+		{Name: "note", Aliases: []string{"annotate"}, Mutates: true, Run: RunNote,
+			Usage:   "note <id> \"<text>\"\nnote <id>\nnote <id> --edit <n> \"<text>\"\nnote <id> --remove <n>",
+			Summary: "Add a timestamped note to a task without touching its description, or list, edit and remove notes."},
+		{Name: "edit", Mutates: true, Run: RunEdit,
+			Usage:   "edit <id>\nedit --all",
+			Summary: "Edit a task, or the whole list, as Markdown in $EDITOR."},
+		{Name: "delete", Aliases: []string{"rm", "del"}, Mutates: true, Run: RunDelete,
+			Usage:   "delete <id|range>...\ndelete --where <filter>",
+			Summary: "Move tasks to trash; they are purged after 30 days."},
+		{Name: "restore", Mutates: true, Run: RunRestore,
+			Usage:   "restore <id|range>...\nrestore --where <filter>\nrestore --all",
+			Summary: "Restore tasks, or all tasks, from trash."},
+		{Name: "done", Aliases: []string{"complete"}, Mutates: true, Run: RunDone,
+			Usage:   "done <id|range>... [--undo]\ndone --where <filter> [--undo]",
+			Summary: "Mark tasks as done, or as pending again with --undo."},
+		{Name: "board", Aliases: []string{"kanban"}, Run: RunBoard,
+			Usage:   "board [--by <status|tag|context|priority>] [--where <filter>]",
+			Summary: "Show the tasks as a Kanban board with WIP counts."},
+		{Name: "move", Aliases: []string{"mv"}, Mutates: true, Run: RunMove,
+			Usage:   "move <id|range>... <column> [--by <status|tag|context|priority>]",
+			Summary: "Move tasks to another column of the board."},
+		{Name: "agenda", Run: RunAgenda,
+			Usage:   "agenda [--days <n>] [--from <date>] [--where <filter>]",
+			Summary: "List overdue tasks and the tasks due on each of the next days."},
+		{Name: "calendar", Aliases: []string{"cal"}, Run: RunCalendar,
+			Usage:   "calendar [--month <YYYY-MM>] [--where <filter>]",
+			Summary: "Show a month grid with the number of tasks due each day."},
+		{Name: "context", Run: RunContext,
+			Usage:   "context\ncontext set <name>\ncontext none\ncontext list",
+			Summary: "Narrow views and new tasks to a context such as @office, or clear it."},
+		{Name: "remind", Run: RunRemind,
+			Usage:   "remind list [--days <n>]\nremind snooze <id|range>... [--for <duration>]",
+			Summary: "List upcoming reminders or snooze the reminders of tasks."},
+		{Name: "daemon", Run: RunDaemon,
+			Usage:   "daemon [--interval <duration>] [--notify <stdout,desktop,webhook,email>] [--once]",
+			Summary: "Deliver reminders for due tasks until stopped."},
+		{Name: "webhook", Run: RunWebhook,
+			Usage:   "webhook list\nwebhook flush",
+			Summary: "List webhooks and pending events, or deliver pending events now."},
+		{Name: "batch", Mutates: true, Run: RunBatch,
+			Usage:   "batch [--file <script>] [--dry-run]",
+			Summary: "Apply add, change, delete, restore and done lines all at once or not at all."},
+		{Name: "export", Run: RunExport,
+			Usage:   "export --format <todotxt|markdown|csv|html> [--output <file>] [--where <filter>] [--trash]",
+			Summary: "Export tasks as todo.txt, Markdown, CSV or an HTML report."},
+		{Name: "ical", Mutates: true, Run: RunIcal,
+			Usage:   "ical export [--output <file>] [--trash]\nical import --input <file>",
+			Summary: "Export or import tasks as iCalendar VTODOs."},
+		{Name: "sync", Mutates: true, Run: RunSync,
+			Usage:   "sync caldav [--url <url>] [--user <name>] [--password <password>] [--conflict <policy>]\nsync git [--repo <dir>] [--remote <remote>] [--branch <branch>]",
+			Summary: "Two-way sync with a CalDAV server or a git repository."},
+		{Name: "merge", Mutates: true, Run: RunMerge,
+			Usage:   "merge <other.json>",
+			Summary: "Merge another copy of the database into this one."},
+		{Name: "serve", Run: RunServe,
+			Usage:   "serve [--addr <addr>] [--grpc-addr <addr>] [--token <token>]",
+			Summary: "Serve the tasks over a REST API, web UI and gRPC."},
+		{Name: "help", Run: RunHelp,
+			Usage:   "help [command]",
+			Summary: "Show help for taski or one of its commands."},
+		{Name: "completion", Run: RunCompletion,
+			Usage:   "completion <bash|zsh|fish>",
+			Summary: "Print a shell completion script."},
+		{Name: "__complete", Hidden: true, Run: runComplete,
+			Usage:   "__complete <words...>",
+			Summary: "Print completion candidates for the given words."},
+	}
+}
+
+// usageOutput is where flag sets print their usage; help redirects it to
+// stdout.
+var usageOutput io.Writer = os.Stderr
+
+// Lookup finds a command by name or alias, falling back to an external
+// command on PATH.
+func Lookup(name string) *Command {
+	if command := builtin(name); command != nil {
+		return command
+	}
+
+	return plugin(name)
+}
+
+// builtin finds a command of the command table by name or alias.
+func builtin(name string) *Command {
+	for _, command := range commands {
+		if command.Name == name {
+			return command
+		}
+
+		for _, alias := range command.Aliases {
+			if alias == name {
+				return command
+			}
+		}
+	}
+
+	return nil
+}
+
+// Execute runs the command named by args[0].
+func Execute(args []string, fileName string) error {
+	if len(args) < 1 {
+		printOverview(os.Stderr)
+		return Usagef("parsing args: arguments not enough")
+	}
+
+	command := Lookup(args[0])
+
+	if command == nil {
+		return unknownCommand(args[0])
+	}
+
+	return command.Run(args[1:], fileName)
+}
+
+func unknownCommand(name string) error {
+	suggestions := Suggest(name)
+
+	if len(suggestions) > 0 {
+		return Usagef("unknown command %q, did you mean %q?", name, suggestions[0])
+	}
+
+	var names []string
+
+	for _, command := range commands {
+		if !command.Hidden {
+			names = append(names, command.Name)
+		}
+	}
+
+	return Usagef("unknown command %q, usable: %v", name, strings.Join(names, ", "))
+}
+
+// Suggest returns the command names closest to a mistyped one.
+func Suggest(name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	var candidates []candidate
+
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+
+		best := -1
+
+		for _, known := range append([]string{command.Name}, command.Aliases...) {
+			distance := editDistance(name, known)
+
+			if strings.HasPrefix(known, name) && len(name) >= 2 {
+				distance = 0
+			}
+
+			if best == -1 || distance < best {
+				best = distance
+			}
+		}
+
+		if best <= 2 && best < len(name) {
+			candidates = append(candidates, candidate{command.Name, best})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var names []string
+
+	for _, c := range candidates {
+		names = append(names, c.name)
+	}
+
+	return names
+}
+
+// editDistance is the Levenshtein distance counting a swap of two adjacent
+// letters as one edit, the most common typo in command names.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+// newFlagSet creates the flag set for a command with a usage message built
+// from the command table.
+func newFlagSet(name string) *flag.FlagSet {
+	cmd := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.SetOutput(usageOutput)
+	cmd.Usage = func() {
+		out := cmd.Output()
+		fmt.Fprintf(out, "Usage of %s:\n", name)
+
+		if command := Lookup(strings.Fields(name)[0]); command != nil {
+			for _, line := range strings.Split(command.Usage, "\n") {
+				fmt.Fprintf(out, "  taski %s\n", line)
+			}
+
+			fmt.Fprintf(out, "\n%s\n", command.Summary)
+
+			if len(command.Aliases) > 0 {
+				fmt.Fprintf(out, "Aliases: %s\n", strings.Join(command.Aliases, ", "))
+			}
+		}
+
+		fmt.Fprintln(out, "\nFlags:")
+		cmd.PrintDefaults()
+	}
+
+	return cmd
+}
+
+// subcommandHelp reports whether args ask for help on a command that
+// takes a subcommand, printing its usage if so.
+func subcommandHelp(name string, args []string) bool {
+	if len(args) < 1 {
+		return false
+	}
+
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		command := Lookup(name)
+		fmt.Fprintf(usageOutput, "Usage of %s:\n", name)
+
+		for _, line := range strings.Split(command.Usage, "\n") {
+			fmt.Fprintf(usageOutput, "  taski %s\n", line)
+		}
+
+		fmt.Fprintf(usageOutput, "\n%s\nRun \"taski %s <subcommand> --help\" for its flags.\n", command.Summary, name)
+
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// subcommands lists the fixed first arguments of commands that take one.
+var subcommands = map[string][]string{
+	"ical":       {"export", "import"},
+	"sync":       {"caldav", "git"},
+	"remind":     {"list", "snooze"},
+	"webhook":    {"list", "flush"},
+	"context":    {"set", "none", "list"},
+	"completion": {"bash", "zsh", "fish"},
+}
+
+// idCommands take task IDs as positional arguments.
+var idCommands = map[string]bool{
+	"change":  true,
+	"delete":  true,
+	"restore": true,
+	"done":    true,
+	"edit":    true,
+	"show":    true,
+	"note":    true,
+	"move":    true,
+}
+
+// idFlags are the flags whose value is a task index.
+var idFlags = map[string]bool{
+	"-i":      true,
+	"--index": true,
+	"-index":  true,
+}
+
+func RunCompletion(args []string, fileName string) error {
+	if subcommandHelp("completion", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) < 1 {
+		return Usagef("unfilled arguments: usage: taski completion <bash|zsh|fish>")
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		return Usagef("unknown shell %q, usable: bash, zsh, fish", args[0])
+	}
+
+	return nil
+}
+
+// runComplete prints one candidate per line for the word being completed,
+// which is the last of words. Candidates may carry a tab-separated
+// description for shells that show one.
+func runComplete(words []string, fileName string) error {
+	for _, candidate := range Complete(words, fileName) {
+		fmt.Println(candidate)
+	}
+
+	return nil
+}
+
+// Complete returns completion candidates for the last of words, the words
+// after "taski" on the command line.
+func Complete(words []string, fileName string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	current := words[len(words)-1]
+
+	if len(words) == 1 {
+		return commandNames(current)
+	}
+
+	name := words[0]
+	previous := words[len(words)-2]
+
+	command := Lookup(name)
+
+	if command == nil {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(current, "+"):
+		return labelsFor(current, fileName, func(task io.Task) []string { return task.Tags })
+	case strings.HasPrefix(current, "@"):
+		return labelsFor(current, fileName, func(task io.Task) []string { return task.Contexts })
+	case idFlags[previous], idCommands[command.Name] && !strings.HasPrefix(current, "-"):
+		return taskIDs(current, fileName, command.Name == "restore")
+	}
+
+	if command.Name == "help" && len(words) == 2 {
+		return commandNames(current)
+	}
+
+	if len(words) == 2 {
+		return withPrefix(subcommands[command.Name], current)
+	}
+
+	return nil
+}
+
+func commandNames(prefix string) []string {
+	var names []string
+
+	for _, command := range commands {
+		if !command.Hidden {
+			names = append(names, command.Name)
+			names = append(names, command.Aliases...)
+		}
+	}
+
+	names = append(names, Plugins()...)
+
+	return withPrefix(names, prefix)
+}
+
+// taskIDs lists active tasks, or tasks in trash when trash is set.
+func taskIDs(prefix string, fileName string, trash bool) []string {
+	if _, err := os.Stat(fileName); err != nil {
+		return nil
+	}
+
+	db, err := io.ReadAll(fileName)
+
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+
+	for _, task := range db.Tasks {
+		if task.IsDeleted != trash {
+			continue
+		}
+
+		id := strconv.Itoa(task.ID)
+
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id+"\t"+task.Title)
+		}
+	}
+
+	return ids
+}
+
+// labelsFor lists the distinct tags or contexts in use, keeping the sigil
+// of prefix.
+func labelsFor(prefix string, fileName string, field func(io.Task) []string) []string {
+	if _, err := os.Stat(fileName); err != nil {
+		return nil
+	}
+
+	db, err := io.ReadTask(fileName)
+
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var values []string
+
+	for _, task := range db.Tasks {
+		for _, label := range field(task) {
+			value := prefix[:1] + label
+
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+
+	sort.Strings(values)
+
+	return withPrefix(values, prefix)
+}
+
+func withPrefix(values []string, prefix string) []string {
+	var matched []string
+
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matched = append(matched, value)
+		}
+	}
+
+	return matched
+}
+
+const bashCompletion = `# bash completion for taski
+# Load with: source <(taski completion bash)
+_taski() {
+	local IFS=$'\n'
+	local candidates
+	candidates=$(taski __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1)
+	COMPREPLY=($(compgen -W "${candidates}" -- "${COMP_WORDS[COMP_CWORD]}"))
+}
+complete -o default -F _taski taski
+`
+
+const zshCompletion = `#compdef taski
+# zsh completion for taski
+# Load with: source <(taski completion zsh)
+_taski() {
+	local -a candidates
+	local line
+	for line in "${(@f)$(taski __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+		[[ -n $line ]] && candidates+=("${line/$'\t'/:}")
+	done
+	_describe 'taski' candidates
+}
+compdef _taski taski
+`
+
+const fishCompletion = `# fish completion for taski
+# Load with: taski completion fish | source
+function __taski_complete
+	set -l words (commandline -opc)[2..-1] (commandline -ct)
+	taski __complete $words 2>/dev/null
+end
+complete -c taski -f -a '(__taski_complete)'
+`
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/contexts"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func RunContext(args []string, fileName string) error {
+	if subcommandHelp("context", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) == 0 {
+		return runContextShow(fileName)
+	}
+
+	switch args[0] {
+	case "set":
+		return runContextSet(args[1:], fileName)
+	case contexts.None:
+		return runContextNone(args[1:], fileName)
+	case "list", "ls":
+		return runContextList(args[1:], fileName)
+	default:
+		return Usagef("unknown context subcommand %q, usable: set, none, list", args[0])
+	}
+}
+
+func runContextShow(fileName string) error {
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return fmt.Errorf("reading context: %w\n", err)
+	}
+
+	if active == "" {
+		fmt.Println("No context set. To set one, type: taski context set <name>")
+		return nil
+	}
+
+	fmt.Printf("Context: @%v\n", active)
+
+	return nil
+}
+
+func runContextSet(args []string, fileName string) error {
+	if len(args) != 1 {
+		return Usagef("unfilled arguments: usage: taski context set <name>")
+	}
+
+	name, err := contexts.Normalize(args[0])
+
+	if err != nil {
+		return Usagef("parsing context: %w", err)
+	}
+
+	err = contexts.Set(fileName, name)
+
+	if err != nil {
+		return fmt.Errorf("setting context: %w\n", err)
+	}
+
+	fmt.Printf("Context Set: @%v\n", name)
+	fmt.Printf("Views only list tasks in @%v and new tasks are added to it.\n", name)
+	fmt.Println("\nTo clear, type: taski context none")
+
+	return nil
+}
+
+func runContextNone(args []string, fileName string) error {
+	if len(args) > 0 {
+		return Usagef("context none takes no arguments")
+	}
+
+	err := contexts.Set(fileName, "")
+
+	if err != nil {
+		return fmt.Errorf("clearing context: %w\n", err)
+	}
+
+	fmt.Println("Context Cleared, Views List Every Task.")
+
+	return nil
+}
+
+// runContextList prints the contexts of the active tasks with their number
+// of tasks, marking the active context.
+func runContextList(args []string, fileName string) error {
+	if len(args) > 0 {
+		return Usagef("context list takes no arguments")
+	}
+
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return fmt.Errorf("listing contexts: %w\n", err)
+	}
+
+	db, err := io.ReadAll(fileName)
+
+	if err != nil {
+		return fmt.Errorf("listing contexts: %w\n", err)
+	}
+
+	counts := make(map[string]int)
+
+	for _, task := range db.Tasks {
+		if task.IsDeleted {
+			continue
+		}
+
+		for _, name := range task.Contexts {
+			counts[strings.ToLower(name)]++
+		}
+	}
+
+	// The active context is listed even before it has any tasks.
+	if _, found := counts[strings.ToLower(active)]; !found && active != "" {
+		counts[strings.ToLower(active)] = 0
+	}
+
+	if len(counts) == 0 {
+		fmt.Println("No contexts yet. To add a task to one, type: taski add \"<title> @<context>\"")
+		return nil
+	}
+
+	names := make([]string, 0, len(counts))
+
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Println("Contexts:")
+
+	for _, name := range names {
+		mark := " "
+
+		if strings.EqualFold(name, active) {
+			mark = "*"
+		}
+
+		fmt.Printf("%s @%v (%d task(s))\n", mark, name, counts[name])
+	}
+
+	return nil
+}
+
+// narrow adds the active context to the --where filter of a view.
+func narrow(where string, fileName string) (string, error) {
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return "", err
+	}
+
+	return contexts.Where(where, active), nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/remind"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunDaemon(args []string, fileName string) error {
+	cmd := newFlagSet("daemon")
+	var interval time.Duration
+	var notify string
+	var once bool
+
+	cmd.DurationVar(&interval, "interval", 30*time.Second, "How Often to Check for Due Reminders")
+	cmd.StringVar(&notify, "notify", "", "Notifiers to Use: stdout, desktop, webhook, email (default: from config, else stdout)")
+	cmd.BoolVar(&once, "once", false, "Deliver the Reminders Due Now and Exit")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if interval < time.Second {
+		return Usagef("--interval must be at least 1s, got %v", interval)
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("starting daemon: %w\n", err)
+	}
+
+	names := cfg.Reminders.Notifiers
+
+	if notify != "" {
+		names = strings.Split(notify, ",")
+	}
+
+	if password := os.Getenv("TASKI_SMTP_PASSWORD"); password != "" {
+		cfg.Reminders.Email.Password = password
+	}
+
+	notifiers, err := remind.NewNotifiers(names, cfg.Reminders, os.Stdout)
+
+	if err != nil {
+		return Usagef("%w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !once {
+		fmt.Printf("Watching %v for reminders, press Ctrl+C to stop\n", fileName)
+	}
+
+	var tasks []taski.Task
+	version := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// The database is only read again once it changed on disk.
+		if current := fileVersion(fileName); current != version {
+			tasks, err = taski.Open(fileName).List(ctx, taski.ListOptions{})
+
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Warning: reading tasks: %v\n", err)
+			} else {
+				version = current
+			}
+		}
+
+		err = deliver(ctx, tasks, cfg.Reminders.Default, remind.StatePath(fileName), notifiers)
+
+		if err != nil {
+			return fmt.Errorf("delivering reminders: %w\n", err)
+		}
+
+		// Webhook events retried with backoff go out from here too.
+		FlushWebhooks(fileName)
+
+		if once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("Stopped.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver hands the reminders due now to every notifier. A reminder counts
+// as delivered once one notifier took it; failures are warned about and,
+// if every notifier failed, retried on the next round.
+func deliver(ctx context.Context, tasks []taski.Task, defaults []string, statePath string, notifiers []remind.Notifier) error {
+	state, err := remind.LoadState(statePath)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var delivered []remind.Reminder
+
+	for _, reminder := range remind.Due(tasks, defaults, state, now) {
+		sent := false
+
+		for _, notifier := range notifiers {
+			err = notifier.Notify(ctx, reminder)
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: reminding of task %d: %v\n", reminder.Task.ID, err)
+				continue
+			}
+
+			sent = true
+		}
+
+		if sent {
+			delivered = append(delivered, reminder)
+		}
+	}
+
+	if len(delivered) == 0 {
+		return nil
+	}
+
+	return remind.UpdateState(statePath, func(state *remind.State) {
+		for _, reminder := range delivered {
+			state.MarkSent(reminder)
+		}
+
+		state.Prune(now.Add(-remind.MaxLate))
+	})
+}
+
+// fileVersion changes whenever the file is written.
+func fileVersion(fileName string) string {
+	info, err := os.Stat(fileName)
+
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
@@ -1,38 +1,79 @@
 package cmd
 
 import (
-	"flag"
+	"context"
 	"fmt"
 
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func RunDelete(args []string, fileName string) error {
-	cmd := flag.NewFlagSet("delete", flag.ContinueOnError)
+	cmd := newFlagSet("delete")
 	var index int
+	var where string
 
 	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
 	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
+	cmd.StringVar(&where, "where", "", "Delete Every Active Task Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
 
-	err := cmd.Parse(args)
+	positional, err := parseArgs(cmd, args)
 
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return err
 	}
 
-	if index == -1 {
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("deleting task: %w\n", err)
+	}
+
+	selected, err := newSelection(positional, index, where, fileName, count)
+
+	if err != nil {
+		return err
+	}
+
+	if selected.empty() {
 		cmd.Usage()
-		return fmt.Errorf("unfilled arguments")
+		return Usagef("unfilled arguments")
 	}
 
-	err = io.RemoveTask(fileName, index)
+	var ids []int
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		ids, err = selected.resolve(db, false)
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			err = db.Delete(id)
+
+			if err != nil {
+				return fmt.Errorf("deleting task: %w", err)
+			}
+		}
+
+		return nil
+	})
 
 	if err != nil {
-		return fmt.Errorf("deleting task: %v\n", err)
+		return fmt.Errorf("deleting task: %w\n", err)
+	}
+
+	if len(ids) == 1 {
+		fmt.Println("Deleted Task:")
+	} else {
+		fmt.Println("Deleted Tasks:")
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Index: %d\n", id)
 	}
 
-	fmt.Println("Deleted Task:")
-	fmt.Printf("Index: %d\n", index)
 	fmt.Println("\nTo view, type: taski view")
 	fmt.Println("To restore, type: taski restore")
 
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunDone(args []string, fileName string) error {
+	cmd := newFlagSet("done")
+	var index int
+	var where string
+	var undo bool
+
+	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
+	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
+	cmd.StringVar(&where, "where", "", "Complete Every Active Task Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+	cmd.BoolVar(&undo, "undo", false, "Mark The Tasks as Pending Again")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("completing task: %w\n", err)
+	}
+
+	selected, err := newSelection(positional, index, where, fileName, count)
+
+	if err != nil {
+		return err
+	}
+
+	if selected.empty() {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	status := taski.StatusDone
+
+	if undo {
+		status = taski.StatusPending
+	}
+
+	var ids []int
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		ids, err = selected.resolve(db, false)
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			err = setStatus(db, id, status)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("completing task: %w\n", err)
+	}
+
+	verb := "Completed"
+
+	if undo {
+		verb = "Reopened"
+	}
+
+	if len(ids) == 1 {
+		fmt.Printf("%s Task:\n", verb)
+	} else {
+		fmt.Printf("%s Tasks:\n", verb)
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Index: %d\n", id)
+	}
+
+	fmt.Println("\nTo view, type: taski view")
+
+	return nil
+}
+
+// setStatus refuses tasks in trash, which have to be restored first.
+func setStatus(db *taski.Database, id int, status string) error {
+	task, err := db.Get(id)
+
+	if err != nil {
+		return err
+	}
+
+	if task.IsDeleted {
+		return fmt.Errorf("task %d is in trash: %w", id, taski.ErrNotFound)
+	}
+
+	return db.SetStatus(id, status)
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/contexts"
+	"github.com/tristnaja/taski/internal/frontmatter"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunEdit(args []string, fileName string) error {
+	cmd := newFlagSet("edit")
+	var index int
+	var all bool
+
+	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
+	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
+	cmd.BoolVar(&all, "all", false, "Edit Every Active Task as One Document")
+	cmd.BoolVar(&all, "a", false, "Edit Every Active Task as One Document (shorthand)")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w\n", err)
+	}
+
+	ids, err := parseIDs(positional, count)
+
+	if err != nil {
+		return err
+	}
+
+	if index != -1 {
+		ids = append(ids, index)
+	}
+
+	fields, err := uda.Load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w\n", err)
+	}
+
+	switch {
+	case all && len(ids) > 0:
+		return Usagef("give a task id or --all, not both")
+	case all:
+		return editAll(fileName, fields)
+	case len(ids) == 0:
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	case len(ids) > 1:
+		return Usagef("edit takes one task id; use --all to edit several")
+	}
+
+	return editOne(fileName, ids[0], fields)
+}
+
+func editOne(fileName string, id int, fields uda.Fields) error {
+	repo := taski.Open(fileName)
+	task, err := repo.Get(context.Background(), id)
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w\n", err)
+	}
+
+	if task.IsDeleted {
+		return fmt.Errorf("editing task: task %d is in trash: %w\n", id, taski.ErrNotFound)
+	}
+
+	original := frontmatter.Encode(task, false, fields)
+	documents, path, err := editText(original, fields)
+
+	if err != nil || documents == nil {
+		return err
+	}
+
+	if len(documents) != 1 || documents[0].ID != -1 && documents[0].ID != id {
+		return fmt.Errorf("editing task: %w: expected task %d only (your edits are kept in %s)\n", taski.ErrInvalid, id, path)
+	}
+
+	edited := task
+	frontmatter.Apply(&edited, documents[0].Task)
+	diff := frontmatter.Diff(original, frontmatter.Encode(edited, false, fields))
+
+	if diff == "" {
+		fmt.Println("No changes, nothing was saved.")
+		os.Remove(path)
+		return nil
+	}
+
+	err = repo.Transact(context.Background(), func(db *taski.Database) error {
+		return db.Update(id, func(current *taski.Task) error {
+			if current.IsDeleted || frontmatter.Encode(*current, false, fields) != original {
+				return fmt.Errorf("task %d was changed while it was being edited", id)
+			}
+
+			frontmatter.Apply(current, documents[0].Task)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w (your edits are kept in %s)\n", err, path)
+	}
+
+	os.Remove(path)
+
+	fmt.Print(diff)
+	fmt.Println("\nEdited Task:")
+	fmt.Printf("Index: %d\n", id)
+	fmt.Println("\nTo view, type: taski view")
+
+	return nil
+}
+
+// editAll opens every active task of the active context in one document.
+// Documents without an id become new tasks in that context and tasks whose
+// document was removed go to trash.
+func editAll(fileName string, fields uda.Fields) error {
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w\n", err)
+	}
+
+	repo := taski.Open(fileName)
+	tasks, err := repo.List(context.Background(), taski.ListOptions{Where: contexts.Where("", active)})
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w\n", err)
+	}
+
+	original := frontmatter.EncodeAll(tasks, fields)
+	documents, path, err := editText(original, fields)
+
+	if err != nil || documents == nil {
+		return err
+	}
+
+	before := map[int]taski.Task{}
+
+	for _, task := range tasks {
+		before[task.ID] = task
+	}
+
+	var report strings.Builder
+	var added []taski.Task
+	edited := map[int]taski.Task{}
+	changes := map[int]taski.Task{}
+
+	for _, document := range documents {
+		if document.ID == -1 {
+			var task taski.Task
+			frontmatter.Apply(&task, document.Task)
+			contexts.Apply(&task, active)
+			task.Date = time.Now()
+			added = append(added, task)
+			continue
+		}
+
+		task, found := before[document.ID]
+
+		if !found {
+			return fmt.Errorf("editing task: %w: line %d: no active task %d (your edits are kept in %s)\n", taski.ErrInvalid, document.Line, document.ID, path)
+		}
+
+		if _, twice := edited[document.ID]; twice {
+			return fmt.Errorf("editing task: %w: line %d: task %d appears twice (your edits are kept in %s)\n", taski.ErrInvalid, document.Line, document.ID, path)
+		}
+
+		frontmatter.Apply(&task, document.Task)
+		edited[document.ID] = task
+		changes[document.ID] = document.Task
+	}
+
+	var removed []int
+
+	for _, task := range tasks {
+		after, kept := edited[task.ID]
+
+		if !kept {
+			fmt.Fprintf(&report, "Deleted Task %d: %s\n", task.ID, task.Title)
+			removed = append(removed, task.ID)
+			continue
+		}
+
+		diff := frontmatter.Diff(frontmatter.Encode(task, false, fields), frontmatter.Encode(after, false, fields))
+
+		if diff == "" {
+			delete(edited, task.ID)
+			continue
+		}
+
+		fmt.Fprintf(&report, "Task %d:\n%s\n", task.ID, diff)
+	}
+
+	if len(edited) == 0 && len(removed) == 0 && len(added) == 0 {
+		fmt.Println("No changes, nothing was saved.")
+		os.Remove(path)
+		return nil
+	}
+
+	err = repo.Transact(context.Background(), func(db *taski.Database) error {
+		for _, task := range tasks {
+			current, err := db.Get(task.ID)
+
+			if err != nil {
+				return err
+			}
+
+			if current.IsDeleted || frontmatter.Encode(current, true, fields) != frontmatter.Encode(task, true, fields) {
+				return fmt.Errorf("task %d was changed while it was being edited", task.ID)
+			}
+		}
+
+		for id := range edited {
+			err := db.Update(id, func(current *taski.Task) error {
+				frontmatter.Apply(current, changes[id])
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, id := range removed {
+			err := db.Delete(id)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, task := range added {
+			task = db.Add(task)
+			fmt.Fprintf(&report, "Added Task %d: %s\n", task.ID, task.Title)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("editing task: %w (your edits are kept in %s)\n", err, path)
+	}
+
+	os.Remove(path)
+
+	fmt.Print(report.String())
+	fmt.Printf("\nChanged: %d, Added: %d, Deleted: %d\n", len(edited), len(added), len(removed))
+	fmt.Println("\nTo view, type: taski view")
+
+	return nil
+}
+
+// editText writes text to a temporary Markdown file, opens it in the
+// editor and parses the result. It returns no documents when the file was
+// saved unchanged. The file is kept on errors so no edits are lost.
+func editText(text string, fields uda.Fields) ([]frontmatter.Document, string, error) {
+	file, err := os.CreateTemp("", "taski-*.md")
+
+	if err != nil {
+		return nil, "", fmt.Errorf("editing task: %w\n", err)
+	}
+
+	path := file.Name()
+	_, err = file.WriteString(text)
+	file.Close()
+
+	if err != nil {
+		os.Remove(path)
+		return nil, "", fmt.Errorf("editing task: %w\n", err)
+	}
+
+	err = runEditor(path)
+
+	if err != nil {
+		os.Remove(path)
+		return nil, "", fmt.Errorf("editing task: %w\n", err)
+	}
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("editing task: %w\n", err)
+	}
+
+	if string(content) == text {
+		fmt.Println("No changes, nothing was saved.")
+		os.Remove(path)
+		return nil, "", nil
+	}
+
+	documents, err := frontmatter.Decode(string(content), time.Now(), fields)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("editing task: %w: %w (your edits are kept in %s)\n", taski.ErrInvalid, err, path)
+	}
+
+	return documents, path, nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi. The
+// variable may hold arguments, e.g. "code --wait".
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+	}
+
+	words := strings.Fields(editor)
+	command := exec.Command(words[0], append(words[1:], path)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err := command.Run()
+
+	if err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+
+	return nil
+}
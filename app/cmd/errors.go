@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+// Exit codes returned by the taski binary. Scripts can rely on these staying
+// the same between releases.
+//
+//	0  success
+//	1  any other failure
+//	2  usage error: unknown command, bad or missing flags, invalid values
+//	3  task not found or index out of bounds
+//	4  database locked by another process
+//	5  database file is corrupt
+//	6  I/O failure reading or writing files
+//
+// An external command exits with its own code.
+const (
+	ExitOK       = 0
+	ExitFailure  = 1
+	ExitUsage    = 2
+	ExitNotFound = 3
+	ExitLocked   = 4
+	ExitCorrupt  = 5
+	ExitIO       = 6
+)
+
+// ErrUsage matches every error caused by how a command was invoked.
+var ErrUsage = errors.New("usage error")
+
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func (e *usageError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// Usagef formats an error that matches ErrUsage.
+func Usagef(format string, args ...any) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode maps an error returned by a command to its exit code.
+func ExitCode(err error) int {
+	var pathErr *fs.PathError
+	var pluginErr *PluginError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &pluginErr):
+		return pluginErr.Code
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, ErrUsage), errors.Is(err, taski.ErrInvalid), errors.Is(err, taski.ErrNoChange):
+		return ExitUsage
+	case errors.Is(err, taski.ErrNotFound), errors.Is(err, taski.ErrOutOfBounds):
+		return ExitNotFound
+	case errors.Is(err, taski.ErrLocked):
+		return ExitLocked
+	case errors.Is(err, taski.ErrCorrupt):
+		return ExitCorrupt
+	case errors.As(err, &pathErr):
+		return ExitIO
+	default:
+		return ExitFailure
+	}
+}
+
+// errorKinds names each exit code in JSON error output.
+var errorKinds = map[int]string{
+	ExitFailure:  "failure",
+	ExitUsage:    "usage",
+	ExitNotFound: "not_found",
+	ExitLocked:   "locked",
+	ExitCorrupt:  "corrupt",
+	ExitIO:       "io",
+}
+
+// ErrorJSON renders err as a single JSON object for --json mode.
+func ErrorJSON(err error) []byte {
+	code := ExitCode(err)
+	kind := errorKinds[code]
+
+	if errors.As(err, new(*PluginError)) {
+		kind = "plugin"
+	}
+
+	out := struct {
+		Error    string `json:"error"`
+		Kind     string `json:"kind"`
+		ExitCode int    `json:"exit_code"`
+	}{
+		Error:    strings.TrimSpace(err.Error()),
+		Kind:     kind,
+		ExitCode: code,
+	}
+
+	encoded, _ := json.Marshal(out)
+
+	return encoded
+}
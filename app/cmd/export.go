@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tristnaja/taski/internal/export"
+	"github.com/tristnaja/taski/internal/filter"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunExport(args []string, fileName string) error {
+	cmd := newFlagSet("export")
+	var format string
+	var output string
+	var where string
+	var trash bool
+
+	cmd.StringVar(&format, "format", "", "Export Format (todotxt, markdown, csv, html)")
+	cmd.StringVar(&format, "f", "", "Export Format (shorthand)")
+	cmd.StringVar(&output, "output", "", "Output File (default: stdout)")
+	cmd.StringVar(&output, "o", "", "Output File (shorthand)")
+	cmd.StringVar(&where, "where", "", "Filter Expression, e.g. 'title:report'")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+	cmd.BoolVar(&trash, "trash", false, "Include Tasks in Trash")
+
+	err := cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	if format == "" {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	err = export.Check(format)
+
+	if err != nil {
+		return Usagef("exporting task: %w", err)
+	}
+
+	fields, err := uda.Load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("exporting task: %w", err)
+	}
+
+	_, err = filter.ParseFields(where, fields)
+
+	if err != nil {
+		return Usagef("parsing filter: %w", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{IncludeTrash: trash, Where: where})
+
+	if err != nil {
+		return fmt.Errorf("exporting task: %w", err)
+	}
+
+	if output == "" {
+		err = export.Write(os.Stdout, format, tasks)
+
+		if err != nil {
+			return fmt.Errorf("exporting task: %w", err)
+		}
+
+		return nil
+	}
+
+	file, err := os.Create(output)
+
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+
+	defer file.Close()
+
+	err = export.Write(file, format, tasks)
+
+	if err != nil {
+		return fmt.Errorf("exporting task: %w", err)
+	}
+
+	fmt.Printf("Exported %d Task(s) to %v\n", len(tasks), output)
+
+	return nil
+}
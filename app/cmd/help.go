@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func RunHelp(args []string, fileName string) error {
+	if len(args) < 1 {
+		printOverview(os.Stdout)
+		return nil
+	}
+
+	command := Lookup(args[0])
+
+	if command == nil {
+		return unknownCommand(args[0])
+	}
+
+	usageOutput = os.Stdout
+	defer func() { usageOutput = os.Stderr }()
+
+	err := command.Run(append(args[1:], "--help"), fileName)
+
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return nil
+}
+
+func printOverview(out io.Writer) {
+	fmt.Fprintln(out, "usage: taski [--json] [--db <file>] <command> [args]")
+	fmt.Fprintln(out, "\nCommands:")
+
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+
+		fmt.Fprintf(out, "  %-11s %s\n", command.Name, command.Summary)
+	}
+
+	if plugins := Plugins(); len(plugins) > 0 {
+		fmt.Fprintln(out, "\nExternal Commands:")
+
+		for _, name := range plugins {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+	}
+
+	fmt.Fprintln(out, "\nGlobal Flags:")
+	fmt.Fprintln(out, "  --json       Print errors as JSON objects")
+	fmt.Fprintln(out, "  --db <file>  Use this database instead of data.json next to taski (or TASKI_DB)")
+	fmt.Fprintln(out, "\nRun \"taski help <command>\" for more about a command.")
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"github.com/tristnaja/taski/internal/hooks"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func init() {
+	// The hook scripts see every change before it is written and may
+	// change or reject it.
+	taski.Guard(hooks.Check)
+}
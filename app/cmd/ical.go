@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tristnaja/taski/internal/ical"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunIcal(args []string, fileName string) error {
+	if subcommandHelp("ical", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) < 1 {
+		return Usagef("unfilled arguments: usage: taski ical <export|import> [options]")
+	}
+
+	switch args[0] {
+	case "export":
+		return runIcalExport(args[1:], fileName)
+	case "import":
+		return runIcalImport(args[1:], fileName)
+	default:
+		return Usagef("unknown ical command %q, usable: export, import", args[0])
+	}
+}
+
+func runIcalExport(args []string, fileName string) error {
+	cmd := newFlagSet("ical export")
+	var output string
+	var trash bool
+
+	cmd.StringVar(&output, "output", "", "Output .ics File (default: stdout)")
+	cmd.StringVar(&output, "o", "", "Output .ics File (shorthand)")
+	cmd.BoolVar(&trash, "trash", false, "Include Tasks in Trash as CANCELLED")
+
+	err := cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{IncludeTrash: trash})
+
+	if err != nil {
+		return fmt.Errorf("exporting calendar: %w", err)
+	}
+
+	if output == "" {
+		err = ical.Encode(os.Stdout, tasks)
+
+		if err != nil {
+			return fmt.Errorf("exporting calendar: %w", err)
+		}
+
+		return nil
+	}
+
+	file, err := os.Create(output)
+
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+
+	defer file.Close()
+
+	err = ical.Encode(file, tasks)
+
+	if err != nil {
+		return fmt.Errorf("exporting calendar: %w", err)
+	}
+
+	fmt.Printf("Exported %d Task(s) to %v\n", len(tasks), output)
+
+	return nil
+}
+
+func runIcalImport(args []string, fileName string) error {
+	cmd := newFlagSet("ical import")
+	var input string
+
+	cmd.StringVar(&input, "input", "", "Input .ics File")
+	cmd.StringVar(&input, "i", "", "Input .ics File (shorthand)")
+
+	err := cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	if input == "" {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	file, err := os.Open(input)
+
+	if err != nil {
+		return fmt.Errorf("opening input file: %w", err)
+	}
+
+	defer file.Close()
+
+	tasks, err := ical.Decode(file)
+
+	if err != nil {
+		return fmt.Errorf("decoding calendar: %w", err)
+	}
+
+	added, updated, err := taski.Open(fileName).Import(context.Background(), tasks)
+
+	if err != nil {
+		return fmt.Errorf("importing calendar: %w", err)
+	}
+
+	fmt.Println("Imported Calendar:")
+	fmt.Printf("Added: %d\n", added)
+	fmt.Printf("Updated: %d\n", updated)
+	fmt.Println("\nTo view, type: taski view")
+
+	return nil
+}
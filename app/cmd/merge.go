@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tristnaja/taski/internal/crdt"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func RunMerge(args []string, fileName string) error {
+	cmd := newFlagSet("merge")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage of merge:\n  taski merge <other.json>")
+	}
+
+	err := cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	other := cmd.Arg(0)
+
+	if other == "" {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	// ReadAll creates missing files, which must not happen to the other replica.
+	_, err = os.Stat(other)
+
+	if err != nil {
+		return fmt.Errorf("opening other database: %w", err)
+	}
+
+	remote, err := io.ReadAll(other)
+
+	if err != nil {
+		return fmt.Errorf("merging task: %w", err)
+	}
+
+	var stats crdt.Stats
+
+	err = io.Rewrite(fileName, func(local io.Database) io.Database {
+		var merged io.Database
+		merged, stats = crdt.Merge(local, remote)
+		return merged
+	})
+
+	if err != nil {
+		return fmt.Errorf("merging task: %w", err)
+	}
+
+	fmt.Println("Merged Tasks:")
+	fmt.Printf("Added: %d\n", stats.Added)
+	fmt.Printf("Updated: %d\n", stats.Updated)
+	fmt.Printf("Removed: %d\n", stats.Removed)
+	fmt.Println("\nTo view, type: taski view")
+
+	return nil
+}
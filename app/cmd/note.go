@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunNote(args []string, fileName string) error {
+	cmd := newFlagSet("note")
+	var edit, remove int
+
+	cmd.IntVar(&edit, "edit", 0, "Replace the Text of Note Number N")
+	cmd.IntVar(&edit, "e", 0, "Note Number to Edit (shorthand)")
+	cmd.IntVar(&remove, "remove", 0, "Remove Note Number N")
+	cmd.IntVar(&remove, "rm", 0, "Note Number to Remove (shorthand)")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	id, err := strconv.Atoi(positional[0])
+
+	if err != nil || id < 0 {
+		return Usagef("invalid task id %q", positional[0])
+	}
+
+	text := strings.TrimSpace(strings.Join(positional[1:], " "))
+
+	switch {
+	case edit != 0 && remove != 0:
+		return Usagef("give --edit or --remove, not both")
+	case edit < 0 || remove < 0:
+		return Usagef("note numbers start at 1")
+	case remove != 0 && text != "":
+		return Usagef("--remove takes no text")
+	case edit != 0 && text == "":
+		return Usagef("--edit needs the new text, or use --remove")
+	case remove == 0 && text == "":
+		return listNotes(id, fileName)
+	}
+
+	var task taski.Task
+
+	err = taski.Open(fileName).Transact(context.Background(), func(db *taski.Database) error {
+		return db.Update(id, func(t *taski.Task) error {
+			if t.IsDeleted {
+				return fmt.Errorf("task %d is in trash: %w", id, taski.ErrNotFound)
+			}
+
+			number := max(edit, remove)
+
+			if number > len(t.Notes) {
+				return fmt.Errorf("task %d has no note %d: %w", id, number, taski.ErrNotFound)
+			}
+
+			notes := slices.Clone(t.Notes)
+
+			switch {
+			case edit != 0:
+				notes[edit-1].Text = text
+			case remove != 0:
+				notes = append(notes[:remove-1], notes[remove:]...)
+			default:
+				// Notes are kept to the second and told apart by the second
+				// they were written, so each new note gets a second of its own.
+				written := time.Now().Truncate(time.Second)
+
+				if len(notes) > 0 && !written.After(notes[len(notes)-1].Time) {
+					written = notes[len(notes)-1].Time.Truncate(time.Second).Add(time.Second)
+				}
+
+				notes = append(notes, taski.Note{Time: written, Text: text})
+			}
+
+			if len(notes) == 0 {
+				notes = nil
+			}
+
+			t.Notes = notes
+			task = *t
+
+			return nil
+		})
+	})
+
+	if err != nil {
+		return fmt.Errorf("changing notes: %w\n", err)
+	}
+
+	switch {
+	case edit != 0:
+		fmt.Printf("Edited Note %d of Task %d:\n", edit, id)
+		fmt.Println(formatNote(task.Notes[edit-1]))
+	case remove != 0:
+		fmt.Printf("Removed Note %d of Task %d.\n", remove, id)
+	default:
+		fmt.Printf("Added Note to Task %d:\n", id)
+		fmt.Println(formatNote(task.Notes[len(task.Notes)-1]))
+	}
+
+	fmt.Printf("\nTo view, type: taski show %d\n", id)
+
+	return nil
+}
+
+// listNotes prints the notes of a task, numbered for --edit and --remove.
+func listNotes(id int, fileName string) error {
+	task, err := taski.Open(fileName).Get(context.Background(), id)
+
+	if err != nil {
+		return fmt.Errorf("listing notes: %w\n", err)
+	}
+
+	if len(task.Notes) == 0 {
+		fmt.Printf("No notes on task %d yet. To add one, type: taski note %d \"<text>\"\n", id, id)
+		return nil
+	}
+
+	fmt.Printf("Notes on Task %d, %v:\n", id, task.Title)
+
+	for i, note := range task.Notes {
+		fmt.Printf("%d. %v\n", i+1, formatNote(note))
+	}
+
+	return nil
+}
+
+// formatNote renders a note as its time followed by its text, with the
+// lines after the first indented under it.
+func formatNote(note taski.Note) string {
+	return note.Time.Format("02 Jan 2006, 15:04") + "  " + strings.ReplaceAll(note.Text, "\n", "\n    ")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/config"
+)
+
+// PluginPrefix starts the names of external commands: "taski jira-link"
+// runs the first taski-jira-link executable on PATH, like git does.
+const PluginPrefix = "taski-"
+
+// Register adds a command to the command table, so that a Go extension
+// built into taski is run, listed and completed like the built-in
+// commands. It is meant to be called from the init function of the
+// extension package and panics when the name or an alias is taken.
+func Register(command *Command) {
+	if command.Name == "" || command.Run == nil {
+		panic("cmd: Register needs a command with a name and a Run function")
+	}
+
+	for _, name := range append([]string{command.Name}, command.Aliases...) {
+		if builtin(name) != nil {
+			panic(fmt.Sprintf("cmd: command %q is already registered", name))
+		}
+	}
+
+	commands = append(commands, command)
+}
+
+// PluginError is an external command that exited with a failure; taski
+// exits with the same code.
+type PluginError struct {
+	Name string
+	Code int
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("%v%v exited with status %d", PluginPrefix, e.Name, e.Code)
+}
+
+// plugin returns the command running the external executable for name, or
+// nil when there is none on PATH.
+func plugin(name string) *Command {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+
+	path, err := exec.LookPath(PluginPrefix + name)
+
+	if err != nil {
+		return nil
+	}
+
+	// An external command may change the database, so it is committed to
+	// git and its events are delivered like those of the built-in ones.
+	return &Command{
+		Name:    name,
+		Mutates: true,
+		Usage:   name + " [args]",
+		Summary: "External command " + path + ".",
+		Run: func(args []string, fileName string) error {
+			return runPlugin(name, path, args, fileName)
+		},
+	}
+}
+
+// runPlugin runs an external command with the terminal of taski. It finds
+// the database in TASKI_DB, so that taski commands it runs use the same
+// one, its config file in TASKI_CONFIG and taski itself in TASKI_BIN.
+func runPlugin(name string, path string, args []string, fileName string) error {
+	fileName, err := filepath.Abs(fileName)
+
+	if err != nil {
+		return fmt.Errorf("resolving database path: %w\n", err)
+	}
+
+	exe, err := os.Executable()
+
+	if err != nil {
+		return fmt.Errorf("locating executables: %w\n", err)
+	}
+
+	command := exec.Command(path, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = append(os.Environ(),
+		"TASKI_DB="+fileName,
+		"TASKI_CONFIG="+config.PathFor(fileName),
+		"TASKI_BIN="+exe,
+	)
+
+	err = command.Run()
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		return &PluginError{Name: name, Code: exitErr.ExitCode()}
+	}
+
+	if err != nil {
+		return fmt.Errorf("running %v: %w\n", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+// Plugins lists the names of the external commands on PATH that do not
+// clash with a command of the table.
+func Plugins() []string {
+	seen := map[string]bool{}
+	var names []string
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, found := strings.CutPrefix(entry.Name(), PluginPrefix)
+
+			if found && runtime.GOOS == "windows" {
+				name, found = strings.CutSuffix(name, ".exe")
+			}
+
+			if !found || name == "" || seen[name] || builtin(name) != nil {
+				continue
+			}
+
+			info, err := entry.Info()
+
+			if err != nil || info.IsDir() || runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
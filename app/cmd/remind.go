@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/remind"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunRemind(args []string, fileName string) error {
+	if subcommandHelp("remind", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) < 1 {
+		return Usagef("unfilled arguments: usage: taski remind <list|snooze> [options]")
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return runRemindList(args[1:], fileName)
+	case "snooze":
+		return runRemindSnooze(args[1:], fileName)
+	default:
+		return Usagef("unknown remind subcommand %q, usable: list, snooze", args[0])
+	}
+}
+
+func runRemindList(args []string, fileName string) error {
+	cmd := newFlagSet("remind list")
+	var days int
+
+	cmd.IntVar(&days, "days", 7, "Number of Days Ahead to List")
+	cmd.IntVar(&days, "n", 7, "Number of Days Ahead to List (shorthand)")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if days < 1 {
+		return Usagef("--days must be at least 1, got %d", days)
+	}
+
+	tasks, state, cfg, err := loadReminders(fileName)
+
+	if err != nil {
+		return fmt.Errorf("listing reminders: %w\n", err)
+	}
+
+	now := time.Now()
+	reminders := remind.Schedule(tasks, cfg.Reminders.Default, state, now, now.AddDate(0, 0, days))
+
+	if len(reminders) == 0 {
+		fmt.Printf("No reminders in the next %d days.\n", days)
+		return nil
+	}
+
+	fmt.Println("Upcoming Reminders:")
+
+	for _, reminder := range reminders {
+		fmt.Printf("  #%-3d %v  %v  (%v)\n", reminder.Task.ID, reminder.At.Local().Format("Mon 02 Jan 15:04"), reminder.Task.Title, describeOffset(reminder.Offset))
+	}
+
+	return nil
+}
+
+func runRemindSnooze(args []string, fileName string) error {
+	cmd := newFlagSet("remind snooze")
+	var length string
+
+	cmd.StringVar(&length, "for", "10m", "How Long to Snooze, e.g. 10m, 1h or 1d")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 1 {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("snoozing reminder: %w\n", err)
+	}
+
+	ids, err := parseIDs(positional, count)
+
+	if err != nil {
+		return err
+	}
+
+	duration, err := remind.ParseDuration(length)
+
+	if err != nil || duration <= 0 {
+		return Usagef("invalid --for %q, expected e.g. 10m, 1h or 1d", length)
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("snoozing reminder: %w\n", err)
+	}
+
+	repo := taski.Open(fileName)
+	var snoozed []taski.Task
+
+	for _, id := range ids {
+		task, err := repo.Get(context.Background(), id)
+
+		if err != nil {
+			return fmt.Errorf("snoozing reminder: %w\n", err)
+		}
+
+		if task.IsDeleted {
+			return fmt.Errorf("snoozing reminder: task %d is in trash: %w\n", id, taski.ErrNotFound)
+		}
+
+		if task.IsDone() {
+			return fmt.Errorf("snoozing reminder: task %d is done: %w\n", id, taski.ErrInvalid)
+		}
+
+		snoozed = append(snoozed, task)
+	}
+
+	until := time.Now().Add(duration)
+
+	err = remind.UpdateState(remind.StatePath(fileName), func(state *remind.State) {
+		for _, task := range snoozed {
+			state.Snooze(task, cfg.Reminders.Default, until)
+		}
+	})
+
+	if err != nil {
+		return fmt.Errorf("snoozing reminder: %w\n", err)
+	}
+
+	if len(ids) == 1 {
+		fmt.Printf("Snoozed Task until %v:\n", until.Format("Mon 02 Jan 15:04"))
+	} else {
+		fmt.Printf("Snoozed Tasks until %v:\n", until.Format("Mon 02 Jan 15:04"))
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Index: %d\n", id)
+	}
+
+	fmt.Println("\nTo see what is coming, type: taski remind list")
+
+	return nil
+}
+
+func loadReminders(fileName string) ([]taski.Task, remind.State, config.Config, error) {
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{})
+
+	if err != nil {
+		return nil, remind.State{}, config.Config{}, err
+	}
+
+	state, err := remind.LoadState(remind.StatePath(fileName))
+
+	if err != nil {
+		return nil, remind.State{}, config.Config{}, err
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return nil, remind.State{}, config.Config{}, err
+	}
+
+	return tasks, state, cfg, nil
+}
+
+// describeOffset reads a reminder setting out, e.g. "30m before due".
+func describeOffset(offset string) string {
+	switch offset {
+	case remind.AtDue:
+		return "at due time"
+	case remind.Snoozed:
+		return "snoozed"
+	default:
+		return strings.TrimSuffix(offset, "-before") + " before due"
+	}
+}
@@ -1,52 +1,92 @@
 package cmd
 
 import (
-	"flag"
+	"context"
 	"fmt"
 
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func RunRestore(args []string, fileName string) error {
-	cmd := flag.NewFlagSet("restore", flag.ContinueOnError)
+	cmd := newFlagSet("restore")
 	var all bool
 	var index int
+	var where string
 
 	cmd.BoolVar(&all, "all", false, "Restore All? (t or f)")
 	cmd.BoolVar(&all, "a", false, "Restore All? <t or f> (shorthand)")
 	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
 	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
+	cmd.StringVar(&where, "where", "", "Restore Every Task in Trash Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
 
-	err := cmd.Parse(args)
+	positional, err := parseArgs(cmd, args)
 
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return err
 	}
 
-	if all == false && index == -1 {
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("restoring task: %w\n", err)
+	}
+
+	selected, err := newSelection(positional, index, where, fileName, count)
+
+	if err != nil {
+		return err
+	}
+
+	if all == false && selected.empty() {
 		cmd.Usage()
-		return fmt.Errorf("unfilled arguments")
+		return Usagef("unfilled arguments")
 	}
 
-	if all == true && index != -1 {
-		return fmt.Errorf("When restoring all, you do not need to input an index")
+	if all == true && !selected.empty() {
+		return Usagef("When restoring all, you do not need to input an index")
 	}
 
+	repo := taski.Open(fileName)
+
 	if all == false {
-		err = io.RestoreTask(fileName, index)
+		var ids []int
+
+		err = repo.Transact(context.Background(), func(db *taski.Database) error {
+			ids, err = selected.resolve(db, true)
+
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				err = db.Restore(id)
+
+				if err != nil {
+					return fmt.Errorf("restoring task: %w", err)
+				}
+			}
+
+			return nil
+		})
+
 		if err != nil {
-			return fmt.Errorf("restoring task: %v\n", err)
+			return fmt.Errorf("restoring task: %w\n", err)
 		}
 
 		fmt.Println("Task Restored")
-		fmt.Printf("Index: %d\n", index)
+
+		for _, id := range ids {
+			fmt.Printf("Index: %d\n", id)
+		}
+
 		fmt.Println("\nTo view, type: taski view")
 		fmt.Println("To restore, type: taski restore")
 	} else {
-		err = io.RestoreAll(fileName)
+		err = repo.RestoreAll(context.Background())
 
 		if err != nil {
-			return fmt.Errorf("restoring task: %v\n", err)
+			return fmt.Errorf("restoring task: %w\n", err)
 		}
 
 		fmt.Println("All Tasks in Trash is Restored")
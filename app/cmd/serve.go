@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tristnaja/taski/internal/server"
+)
+
+func RunServe(args []string, fileName string) error {
+	cmd := newFlagSet("serve")
+	var addr string
+	var grpcAddr string
+	var token string
+
+	cmd.StringVar(&addr, "addr", ":8080", "Address to Listen On")
+	cmd.StringVar(&addr, "a", ":8080", "Address to Listen On (shorthand)")
+	cmd.StringVar(&grpcAddr, "grpc-addr", "", "Also Serve the gRPC API on This Address")
+	cmd.StringVar(&token, "token", os.Getenv("TASKI_TOKEN"), "API Token Clients Must Send as Bearer (or TASKI_TOKEN)")
+
+	err := cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	srv, err := server.New(fileName, token)
+
+	if err != nil {
+		cmd.Usage()
+		return fmt.Errorf("starting server: %w", err)
+	}
+
+	errs := make(chan error, 2)
+
+	if grpcAddr != "" {
+		listener, err := net.Listen("tcp", grpcAddr)
+
+		if err != nil {
+			return fmt.Errorf("listening for grpc: %w", err)
+		}
+
+		fmt.Printf("Serving gRPC on %v\n", listener.Addr())
+
+		go func() {
+			errs <- srv.GRPC().Serve(listener)
+		}()
+	}
+
+	fmt.Printf("Serving Tasks on %v\n", addr)
+
+	// Writes through the API queue webhook events like the CLI does.
+	go func() {
+		for range time.Tick(10 * time.Second) {
+			FlushWebhooks(fileName)
+		}
+	}()
+
+	go func() {
+		errs <- http.ListenAndServe(addr, srv.Handler())
+	}()
+
+	err = <-errs
+
+	if err != nil {
+		return fmt.Errorf("serving: %w", err)
+	}
+
+	return nil
+}
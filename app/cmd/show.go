@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/markdown"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func RunShow(args []string, fileName string) error {
+	cmd := newFlagSet("show")
+	var index int
+	var raw bool
+
+	cmd.IntVar(&index, "index", -1, "Index of The Targeted Task")
+	cmd.IntVar(&index, "i", -1, "Index of The Targeted Task (shorthand)")
+	cmd.BoolVar(&raw, "raw", false, "Print The Description as Written, Without Rendering")
+
+	positional, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := taskCount(fileName)
+
+	if err != nil {
+		return fmt.Errorf("showing task: %w\n", err)
+	}
+
+	ids, err := parseIDs(positional, count)
+
+	if err != nil {
+		return err
+	}
+
+	if index != -1 {
+		ids = append(ids, index)
+	}
+
+	if len(ids) == 0 {
+		cmd.Usage()
+		return Usagef("unfilled arguments")
+	}
+
+	repo := taski.Open(fileName)
+	var tasks []taski.Task
+
+	for _, id := range ids {
+		task, err := repo.Get(context.Background(), id)
+
+		if err != nil {
+			return fmt.Errorf("showing task: %w\n", err)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	color := useColor()
+
+	for i, task := range tasks {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		if color {
+			fmt.Printf("\x1b[1m%v\x1b[0m\n", task.Title)
+		} else {
+			fmt.Println(task.Title)
+		}
+
+		fmt.Printf("Index: %d\n", task.ID)
+		fmt.Printf("Status: %v\n", status(task))
+		fmt.Printf("Date: %v\n", task.Date.Format("02 Jan 2006, 15:04"))
+
+		if task.Due != nil {
+			fmt.Printf("Due: %v\n", task.Due.Format("02 Jan 2006, 15:04"))
+		}
+
+		if task.Priority != "" {
+			fmt.Printf("Priority: %v\n", task.Priority)
+		}
+
+		if task.Recurrence != "" {
+			fmt.Printf("Recurrence: %v\n", task.Recurrence)
+		}
+
+		if len(task.Remind) > 0 {
+			fmt.Printf("Reminders: %v\n", strings.Join(task.Remind, ", "))
+		}
+
+		if len(task.Tags) > 0 || len(task.Contexts) > 0 {
+			fmt.Printf("Labels: %v\n", labels(task))
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(task.Custom)) {
+			fmt.Printf("%v: %v\n", name, task.Custom[name])
+		}
+
+		if task.UID != "" {
+			fmt.Printf("UID: %v\n", task.UID)
+		}
+
+		if task.Description != "" {
+			fmt.Println()
+			fmt.Print(description(task.Description, raw))
+		}
+
+		if len(task.Notes) > 0 {
+			fmt.Println("\nNotes:")
+
+			for _, note := range task.Notes {
+				fmt.Printf("  %v\n", formatNote(note))
+			}
+		}
+	}
+
+	return nil
+}
+
+// status describes where a task stands, including when it went to trash.
+func status(task taski.Task) string {
+	switch {
+	case task.IsDeleted && task.DeletedAt != nil:
+		return "in trash since " + task.DeletedAt.Format("02 Jan 2006, 15:04")
+	case task.IsDeleted:
+		return "in trash"
+	default:
+		return task.StatusName()
+	}
+}
+
+// description renders Markdown for the terminal, or returns it as written
+// when raw is set.
+func description(text string, raw bool) string {
+	if raw {
+		return strings.TrimRight(text, "\n") + "\n"
+	}
+
+	return markdown.Render(text, terminalWidth(), useColor())
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tristnaja/taski/internal/caldav"
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/gitsync"
+)
+
+func RunSync(args []string, fileName string) error {
+	if subcommandHelp("sync", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) < 1 {
+		return Usagef("unfilled arguments: usage: taski sync <caldav|git> [options]")
+	}
+
+	switch args[0] {
+	case "caldav":
+		return runSyncCalDAV(args[1:], fileName)
+	case "git":
+		return runSyncGit(args[1:], fileName)
+	default:
+		return Usagef("unknown sync backend %q, usable: caldav, git", args[0])
+	}
+}
+
+func runSyncCalDAV(args []string, fileName string) error {
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	settings := cfg.CalDAV
+
+	if settings.Conflict == "" {
+		settings.Conflict = caldav.PolicyNewest
+	}
+
+	if password := os.Getenv("TASKI_CALDAV_PASSWORD"); password != "" {
+		settings.Password = password
+	}
+
+	cmd := newFlagSet("sync caldav")
+
+	cmd.StringVar(&settings.URL, "url", settings.URL, "CalDAV Task Collection URL")
+	cmd.StringVar(&settings.Username, "user", settings.Username, "CalDAV Username")
+	cmd.StringVar(&settings.Username, "u", settings.Username, "CalDAV Username (shorthand)")
+	cmd.StringVar(&settings.Password, "password", settings.Password, "CalDAV Password (or TASKI_CALDAV_PASSWORD)")
+	cmd.StringVar(&settings.Conflict, "conflict", settings.Conflict, "Conflict Policy (local, remote, newest)")
+	cmd.StringVar(&settings.Conflict, "c", settings.Conflict, "Conflict Policy (shorthand)")
+
+	err = cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	if settings.URL == "" {
+		cmd.Usage()
+		return Usagef("unfilled arguments: no CalDAV url in flags or config")
+	}
+
+	client, err := caldav.NewClient(settings.URL, settings.Username, settings.Password)
+
+	if err != nil {
+		return fmt.Errorf("configuring caldav: %w", err)
+	}
+
+	result, err := caldav.Sync(client, fileName, settings.Conflict)
+
+	if err != nil {
+		return fmt.Errorf("syncing caldav: %w", err)
+	}
+
+	fmt.Println("Synced with CalDAV:")
+	fmt.Printf("Pushed: %d\n", result.Pushed)
+	fmt.Printf("Pulled: %d\n", result.Pulled)
+	fmt.Printf("Deleted Remotely: %d\n", result.DeletedRemote)
+	fmt.Printf("Conflicts (%v wins): %d\n", settings.Conflict, result.Conflicts)
+
+	return nil
+}
+
+func runSyncGit(args []string, fileName string) error {
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	settings := cfg.Git
+	cmd := newFlagSet("sync git")
+
+	cmd.StringVar(&settings.Repo, "repo", settings.Repo, "Local Repository Holding The Tasks")
+	cmd.StringVar(&settings.Repo, "r", settings.Repo, "Local Repository (shorthand)")
+	cmd.StringVar(&settings.Remote, "remote", settings.Remote, "Remote Name or URL to Pull From and Push To")
+	cmd.StringVar(&settings.Branch, "branch", settings.Branch, "Branch (default: main)")
+	cmd.StringVar(&settings.Branch, "b", settings.Branch, "Branch (shorthand)")
+
+	err = cmd.Parse(args)
+
+	if err != nil {
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	if settings.Repo == "" || settings.Remote == "" {
+		cmd.Usage()
+		return Usagef("unfilled arguments: no git repo or remote in flags or config")
+	}
+
+	result, err := gitsync.Sync(settings, fileName)
+
+	if err != nil {
+		return fmt.Errorf("syncing git: %w", err)
+	}
+
+	fmt.Println("Synced with Git:")
+	fmt.Printf("Committed Local Changes: %v\n", result.Committed)
+	fmt.Printf("Fast-Forwarded: %v\n", result.FastForward)
+	fmt.Printf("Merged: %v\n", result.Merged)
+	fmt.Printf("Conflicts: %d\n", result.Conflicts)
+
+	return nil
+}
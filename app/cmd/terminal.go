@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+)
+
+// defaultWidth is used when stdout is not a terminal and COLUMNS is unset.
+const defaultWidth = 80
+
+// terminalWidth returns the width descriptions are wrapped to: COLUMNS when
+// set, otherwise the width of the terminal on stdout.
+func terminalWidth() int {
+	if width := tableWidth(); width > 0 {
+		return width
+	}
+
+	return defaultWidth
+}
+
+// tableWidth is the width tables are cut to. Unlike terminalWidth it is
+// unlimited (zero) when stdout is piped and COLUMNS is unset, so nothing is
+// lost in files and pipes.
+func tableWidth() int {
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+
+	if err == nil && columns > 0 {
+		return columns
+	}
+
+	return windowWidth(os.Stdout)
+}
+
+// useColor reports whether stdout is a terminal that wants ANSI styling;
+// NO_COLOR and TERM=dumb turn it off.
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cmd
+
+import "os"
+
+// windowWidth is unknown where TIOCGWINSZ is unavailable.
+func windowWidth(file *os.File) int {
+	return 0
+}
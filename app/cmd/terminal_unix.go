@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// windowWidth asks the terminal behind file for its number of columns and
+// returns zero when file is not a terminal.
+func windowWidth(file *os.File) int {
+	var size struct {
+		rows, columns, x, y uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+
+	if errno != 0 {
+		return 0
+	}
+
+	return int(size.columns)
+}
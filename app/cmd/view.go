@@ -1,39 +1,338 @@
 package cmd
 
 import (
-	"flag"
+	"cmp"
+	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/contexts"
+	"github.com/tristnaja/taski/internal/table"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func RunView(args []string, fileName string) error {
-	cmd := flag.NewFlagSet("view", flag.ContinueOnError)
+	cmd := newFlagSet("view")
+	var raw, long, compact bool
+	var where, sortBy, columns string
+
+	cmd.BoolVar(&long, "long", false, "Print Every Task With Its Description")
+	cmd.BoolVar(&long, "l", false, "Print Every Task With Its Description (shorthand)")
+	cmd.BoolVar(&compact, "compact", false, "Print One Short Line per Task, Without Header")
+	cmd.BoolVar(&compact, "c", false, "Print One Short Line per Task, Without Header (shorthand)")
+	cmd.BoolVar(&raw, "raw", false, "Print Descriptions as Written, Without Rendering (with --long)")
+	cmd.StringVar(&where, "where", "", "Only List Tasks Matching This Filter")
+	cmd.StringVar(&where, "w", "", "Filter Expression (shorthand)")
+	cmd.StringVar(&sortBy, "sort", "", "Sort by Comma Separated Keys, -key for Descending (id, title, due, priority, status, date or a custom field)")
+	cmd.StringVar(&sortBy, "s", "", "Sort Keys (shorthand)")
+	cmd.StringVar(&columns, "columns", "", "Custom Fields to Show as Columns, Comma Separated (default: fields with \"column\": true)")
+
 	err := cmd.Parse(args)
 
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return Usagef("parsing arguments: %w", err)
+	}
+
+	if long && compact {
+		return Usagef("give --long or --compact, not both")
+	}
+
+	fields, err := uda.Load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("viewing task: %w\n", err)
+	}
+
+	keys, err := parseSort(sortBy, fields)
+
+	if err != nil {
+		return Usagef("parsing --sort: %w", err)
 	}
 
-	db, err := io.ReadTask(fileName)
+	shown := fields.Columns()
+
+	if columns != "" {
+		shown = splitList(columns)
+
+		for _, name := range shown {
+			if _, found := fields[name]; !found {
+				return Usagef("parsing --columns: unknown field %q", name)
+			}
+		}
+	}
+
+	active, err := contexts.Active(fileName)
+
+	if err != nil {
+		return fmt.Errorf("viewing task: %w\n", err)
+	}
+
+	tasks, err := taski.Open(fileName).List(context.Background(), taski.ListOptions{Where: contexts.Where(where, active)})
 
 	if err != nil {
-		return fmt.Errorf("viewing task: %v\n", err)
+		return fmt.Errorf("viewing task: %w\n", err)
+	}
+
+	sortTasks(tasks, keys, fields)
+
+	if len(tasks) == 0 && active != "" {
+		fmt.Printf("No tasks in context @%v. To list every task, type: taski context none\n", active)
+		return nil
 	}
 
+	if len(tasks) == 0 && where != "" {
+		fmt.Println("No tasks match the filter.")
+		return nil
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks yet. To add one, type: taski add \"<title>\"")
+		return nil
+	}
+
+	if long {
+		printLong(tasks, raw)
+		return nil
+	}
+
+	return printTable(tasks, compact, shown, time.Now())
+}
+
+// printLong prints every task as a block with its rendered description.
+func printLong(tasks []taski.Task, raw bool) {
 	fmt.Println("Here is your Tasks:")
-	for index, task := range db.Tasks {
+	for index, task := range tasks {
 		fmt.Printf("%d. %v\n", (index + 1), task.Title)
 		fmt.Printf("index to target: %d\n", task.ID)
 		fmt.Printf("Date: %v\n", task.Date.Format("02 Jan 2006, 15:04"))
-		fmt.Printf("%v\n\n", task.Description)
+
+		if len(task.Tags) > 0 || len(task.Contexts) > 0 {
+			fmt.Printf("Labels: %v\n", labels(task))
+		}
+
+		if task.Status != "" {
+			fmt.Printf("Status: %v\n", task.Status)
+		}
+
+		if len(task.Custom) > 0 {
+			fmt.Printf("Fields: %v\n", customFields(task.Custom))
+		}
+
+		fmt.Print(description(task.Description, raw))
+		fmt.Println()
 	}
-	fmt.Println("\nYou can Interact with your Tasks with:")
-	fmt.Println("1. Adding new Task: \ntaski add --title <title> -desc <description>")
-	fmt.Println("\n2. Changing Task: \ntaski change --index <index> --title <title> -desc <description>")
-	fmt.Println("\n3. Deleting Task: \ntaski delete --index <index>")
-	fmt.Println("\n4. Restoring Tasks: \ntaski delete --mode <mode> --index <index>")
-	fmt.Println("\n5. Viewing Tasks: \ntaski view")
+}
+
+// printTable prints one aligned row per task, colored by priority and due
+// state and cut to the terminal width. The custom fields in columns get a
+// column each before the title.
+func printTable(tasks []taski.Task, compact bool, columns []string, now time.Time) error {
+	t := table.Table{Columns: []table.Column{
+		{Header: "ID"},
+		{Header: "STATUS"},
+		{Header: "PRI", Optional: true},
+		{Header: "DUE", Optional: true},
+		{Header: "TAGS", Flex: true, Optional: true},
+	}}
+
+	for _, name := range columns {
+		t.Columns = append(t.Columns, table.Column{Header: strings.ToUpper(name), Flex: true, Optional: true})
+	}
+
+	t.Columns = append(t.Columns, table.Column{Header: "TITLE", Flex: true})
+
+	if compact {
+		t.Columns = []table.Column{{Header: "ID"}, {Header: "STATUS"}, {Header: "TITLE", Flex: true}}
+	}
+
+	for _, task := range tasks {
+		id := table.Cell{Text: strconv.Itoa(task.ID)}
+		mark := table.Cell{Text: "[ ]"}
+		title := table.Cell{Text: task.Title}
+		due := table.Cell{}
+
+		switch {
+		case task.IsDone():
+			mark = table.Cell{Text: "[x]", Style: "32"}
+			title.Style = "2"
+		case task.Status != "":
+			mark = table.Cell{Text: task.Status, Style: "35"}
+		}
+
+		if task.Due != nil {
+			due = table.Cell{Text: formatDue(*task.Due), Style: dueStyle(task, now)}
+		}
+
+		if !compact {
+			priority := table.Cell{Text: task.Priority, Style: priorityStyle(task.Priority)}
+			cells := []table.Cell{id, mark, priority, due, {Text: labels(task), Style: "36"}}
+
+			for _, name := range columns {
+				cells = append(cells, table.Cell{Text: task.Custom[name]})
+			}
+
+			t.Append(append(cells, title)...)
+			continue
+		}
+
+		if label := labels(task); label != "" {
+			title.Text += " " + label
+		}
+
+		if due.Text != "" {
+			title.Text += " due:" + due.Text
+		}
+
+		if due.Style != "" {
+			title.Style = due.Style
+		}
+
+		t.Append(id, mark, title)
+	}
+
+	return t.Render(os.Stdout, tableWidth(), useColor(), !compact)
+}
+
+func formatDue(due time.Time) string {
+	if due.Hour() == 0 && due.Minute() == 0 {
+		return due.Format("2006-01-02")
+	}
+
+	return due.Format("2006-01-02 15:04")
+}
+
+// dueStyle marks open tasks red once their due day has passed and yellow
+// on the day itself.
+func dueStyle(task taski.Task, now time.Time) string {
+	if task.Due == nil || task.IsDone() {
+		return ""
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case task.Due.Before(today):
+		return "1;31"
+	case task.Due.Before(today.AddDate(0, 0, 1)):
+		return "33"
+	default:
+		return ""
+	}
+}
+
+func priorityStyle(priority string) string {
+	switch priority {
+	case taski.PriorityHigh:
+		return "1;31"
+	case taski.PriorityMedium:
+		return "33"
+	case taski.PriorityLow:
+		return "34"
+	default:
+		return ""
+	}
+}
+
+// sortKey is one key of view --sort.
+type sortKey struct {
+	name string
+	desc bool
+}
+
+// sortKeys are the built-in keys of view --sort; custom fields can be used
+// as well.
+var sortKeys = []string{"id", "title", "due", "priority", "status", "date"}
+
+func parseSort(value string, fields uda.Fields) ([]sortKey, error) {
+	var keys []sortKey
+
+	for _, name := range splitList(value) {
+		name, desc := strings.CutPrefix(name, "-")
+		_, custom := fields[name]
+
+		if !custom && !slices.Contains(sortKeys, name) {
+			return nil, fmt.Errorf("unknown sort key %q, usable: %s", name, strings.Join(slices.Concat(sortKeys, fields.Names()), ", "))
+		}
+
+		keys = append(keys, sortKey{name: name, desc: desc})
+	}
+
+	return keys, nil
+}
+
+// sortTasks orders tasks by keys, keeping the stored order for ties. Tasks
+// without a value for a key come last in either direction.
+func sortTasks(tasks []taski.Task, keys []sortKey, fields uda.Fields) {
+	if len(keys) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(tasks, func(a, b taski.Task) int {
+		for _, key := range keys {
+			aMissing, bMissing := missing(key.name, a), missing(key.name, b)
+
+			switch {
+			case aMissing && bMissing:
+				continue
+			case aMissing:
+				return 1
+			case bMissing:
+				return -1
+			}
+
+			c := compareBy(key.name, a, b, fields)
+
+			if key.desc {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+}
+
+func missing(key string, task taski.Task) bool {
+	switch key {
+	case "id", "title", "status", "date":
+		return false
+	case "due":
+		return task.Due == nil
+	case "priority":
+		return task.Priority == ""
+	default:
+		return task.Custom[key] == ""
+	}
+}
+
+func compareBy(key string, a taski.Task, b taski.Task, fields uda.Fields) int {
+	switch key {
+	case "id":
+		return cmp.Compare(a.ID, b.ID)
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "due":
+		return a.Due.Compare(*b.Due)
+	case "priority":
+		return cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority))
+	case "status":
+		return strings.Compare(a.Status, b.Status)
+	case "date":
+		return a.Date.Compare(b.Date)
+	default:
+		return fields.Compare(key, a.Custom[key], b.Custom[key])
+	}
+}
 
-	return nil
+// priorityRank orders priorities from high to low.
+func priorityRank(priority string) int {
+	return slices.Index([]string{taski.PriorityHigh, taski.PriorityMedium, taski.PriorityLow}, priority)
 }
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/webhook"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+// webhookClient posts webhook deliveries; its timeout keeps a command from
+// hanging on an endpoint that is down.
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+func init() {
+	// Every write made by a command puts its events in the outbox.
+	taski.Subscribe(func(fileName string, events []taski.Event) {
+		if err := webhook.Enqueue(fileName, events); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: queueing webhook events: %v\n", err)
+		}
+	})
+}
+
+func RunWebhook(args []string, fileName string) error {
+	if subcommandHelp("webhook", args) {
+		return flag.ErrHelp
+	}
+
+	if len(args) < 1 {
+		return Usagef("unfilled arguments: usage: taski webhook <list|flush>")
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return runWebhookList(args[1:], fileName)
+	case "flush":
+		return runWebhookFlush(args[1:], fileName)
+	default:
+		return Usagef("unknown webhook subcommand %q, usable: list, flush", args[0])
+	}
+}
+
+func runWebhookList(args []string, fileName string) error {
+	cmd := newFlagSet("webhook list")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return fmt.Errorf("listing webhooks: %w\n", err)
+	}
+
+	outbox, err := webhook.Load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("listing webhooks: %w\n", err)
+	}
+
+	if len(cfg.Webhooks) == 0 {
+		fmt.Printf("No webhooks configured. To add one, put a \"webhooks\" list in %v\n", config.PathFor(fileName))
+	} else {
+		fmt.Println("Webhooks:")
+	}
+
+	for _, hook := range cfg.Webhooks {
+		events := "all events"
+
+		if len(hook.Events) > 0 {
+			events = strings.Join(hook.Events, ", ")
+		}
+
+		signed := "unsigned"
+
+		if hook.Secret != "" {
+			signed = "signed"
+		}
+
+		fmt.Printf("  %v  (%v, %v)\n", hook.URL, events, signed)
+	}
+
+	if len(outbox.Deliveries) == 0 {
+		fmt.Println("\nOutbox is empty.")
+		return nil
+	}
+
+	fmt.Printf("\nOutbox: %d pending\n", len(outbox.Deliveries))
+
+	for _, delivery := range outbox.Deliveries {
+		fmt.Printf("  %v #%d %v -> %v", delivery.Event.Type, delivery.Event.Task.ID, delivery.Event.Task.Title, delivery.URL)
+
+		if delivery.Attempts > 0 {
+			fmt.Printf(" (%d failed, next try %v: %v)", delivery.Attempts, delivery.NextAttempt.Local().Format("02 Jan 15:04"), delivery.LastError)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runWebhookFlush(args []string, fileName string) error {
+	cmd := newFlagSet("webhook flush")
+
+	_, err := parseArgs(cmd, args)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := webhook.Flush(context.Background(), fileName, webhookClient, time.Now(), true)
+
+	if err != nil {
+		return fmt.Errorf("flushing webhooks: %w\n", err)
+	}
+
+	for _, err := range result.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	fmt.Printf("Delivered: %d, Pending: %d\n", result.Delivered, result.Pending)
+
+	return nil
+}
+
+// FlushWebhooks delivers the due events of the outbox, warning about
+// failures, which are retried later.
+func FlushWebhooks(fileName string) {
+	result, err := webhook.Flush(context.Background(), fileName, webhookClient, time.Now(), false)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: flushing webhooks: %v\n", err)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %v (%d pending in the outbox)\n", result.Errors[0], result.Pending)
+	}
+}
@@ -1,58 +1,113 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/tristnaja/taski/app/cmd"
-	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/gitsync"
+	"github.com/tristnaja/taski/pkg/taski"
 )
 
 func main() {
 	log.SetPrefix("taski: ")
 	log.SetFlags(0)
-	exe, err := os.Executable()
+
+	globals := flag.NewFlagSet("taski", flag.ContinueOnError)
+	globals.SetOutput(io.Discard)
+	jsonErrors := globals.Bool("json", false, "Print errors as JSON objects")
+	dbFile := globals.String("db", os.Getenv("TASKI_DB"), "Database file")
+	err := globals.Parse(os.Args[1:])
+
+	if errors.Is(err, flag.ErrHelp) {
+		cmd.RunHelp(nil, "")
+		return
+	}
 
 	if err != nil {
-		errLog := fmt.Errorf("locating executables: %w", err)
-		log.Fatal(errLog)
+		fail(cmd.Usagef("parsing global flags: %w", err), *jsonErrors)
 	}
 
-	fileDir := filepath.Dir(exe)
-	fileName := filepath.Join(fileDir, "data.json")
-	trashDue := 30 * 24 * time.Hour
+	args := globals.Args()
+	fileName := *dbFile
+
+	if fileName == "" {
+		exe, err := os.Executable()
+
+		if err != nil {
+			fail(fmt.Errorf("locating executables: %w", err), *jsonErrors)
+		}
+
+		fileName = filepath.Join(filepath.Dir(exe), "data.json")
+	}
+
+	// A hook runs while the command that started it holds the database
+	// lock, so taski run from a hook can only read.
+	if hook := os.Getenv("TASKI_HOOK"); hook != "" {
+		if len(args) > 0 {
+			if command := cmd.Lookup(args[0]); command != nil && command.Mutates {
+				fail(fmt.Errorf("running %v from the %v hook: %w", args[0], hook, taski.ErrLocked), *jsonErrors)
+			}
+		}
+	} else {
+		trashDue := 30 * 24 * time.Hour
+
+		_, err = taski.Open(fileName).Purge(context.Background(), trashDue)
+
+		if err != nil {
+			log.Printf("cleanup failed: %v", err)
+		}
+	}
 
-	err = io.CleanUp(fileName, trashDue)
+	err = cmd.Execute(args, fileName)
 
 	if err != nil {
-		log.Printf("cleanup failed: %v", err)
+		fail(err, *jsonErrors)
 	}
 
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "usage: taski <cmd> <args>")
-		errLog := fmt.Errorf("parsing args: arguments not enough")
-		log.Fatal(errLog)
+	if command := cmd.Lookup(args[0]); command.Mutates {
+		commitToGit(fileName)
+		cmd.FlushWebhooks(fileName)
 	}
+}
+
+// fail reports err on stderr, as JSON when asked to, and exits with the
+// code documented in cmd.ExitCode.
+func fail(err error, jsonErrors bool) {
+	code := cmd.ExitCode(err)
 
-	switch os.Args[1] {
-	case "add":
-		err = cmd.RunAdd(os.Args[2:], fileName)
-	case "change":
-		err = cmd.RunChange(os.Args[2:], fileName)
-	case "delete":
-		err = cmd.RunDelete(os.Args[2:], fileName)
-	case "restore":
-		err = cmd.RunRestore(os.Args[2:], fileName)
-	case "view":
-		err = cmd.RunView(os.Args[2:], fileName)
-	default:
-		log.Fatal("unknown command, usable: add, change, restore, delete, view")
+	if jsonErrors {
+		fmt.Fprintln(os.Stderr, string(cmd.ErrorJSON(err)))
+	} else if code != cmd.ExitOK {
+		log.Print(err)
 	}
 
+	os.Exit(code)
+}
+
+func commitToGit(fileName string) {
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		log.Printf("git commit skipped: %v", err)
+		return
+	}
+
+	if cfg.Git.Repo == "" {
+		return
+	}
+
+	_, err = gitsync.Commit(cfg.Git, fileName)
+
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("git commit failed: %v", err)
 	}
 }
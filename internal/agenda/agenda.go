@@ -0,0 +1,99 @@
+// Package agenda lays tasks out on the days they are due, projecting the
+// future occurrences of repeating tasks, for the agenda and calendar views.
+package agenda
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/recur"
+)
+
+// Entry is a task on a day. Projected entries are later occurrences of a
+// repeating task rather than its current due date.
+type Entry struct {
+	Task      io.Task
+	Due       time.Time
+	Projected bool
+}
+
+// Entries returns the open tasks due in [from, to), with the occurrences of
+// repeating tasks, sorted by due time and then id. Tasks with an invalid
+// recurrence only show up on their due date.
+func Entries(tasks []io.Task, from time.Time, to time.Time) []Entry {
+	var entries []Entry
+
+	for _, task := range tasks {
+		if task.Due == nil || task.IsDone() || task.IsDeleted {
+			continue
+		}
+
+		due := *task.Due
+
+		if !due.Before(from) && due.Before(to) {
+			entries = append(entries, Entry{Task: task, Due: due})
+		}
+
+		if task.Recurrence == "" {
+			continue
+		}
+
+		rule, err := recur.Parse(task.Recurrence)
+
+		if err != nil {
+			continue
+		}
+
+		for _, date := range rule.Between(due, from, to) {
+			if !date.Equal(due) {
+				entries = append(entries, Entry{Task: task, Due: date, Projected: true})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Due.Equal(entries[j].Due) {
+			return entries[i].Due.Before(entries[j].Due)
+		}
+
+		return entries[i].Task.ID < entries[j].Task.ID
+	})
+
+	return entries
+}
+
+// Overdue returns the open tasks whose due date is before now.
+func Overdue(tasks []io.Task, now time.Time) []Entry {
+	var entries []Entry
+
+	for _, task := range tasks {
+		if task.Due != nil && !task.IsDone() && !task.IsDeleted && task.Due.Before(now) {
+			entries = append(entries, Entry{Task: task, Due: *task.Due})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Due.Before(entries[j].Due)
+	})
+
+	return entries
+}
+
+// Day returns local midnight at the start of the day of t.
+func Day(t time.Time) time.Time {
+	t = t.Local()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// ByDay counts the entries per day.
+func ByDay(entries []Entry) map[time.Time]int {
+	counts := map[time.Time]int{}
+
+	for _, entry := range entries {
+		counts[Day(entry.Due)]++
+	}
+
+	return counts
+}
@@ -0,0 +1,357 @@
+// Package board groups tasks into the columns of a Kanban board, by status
+// or by another field, moves tasks between those columns and renders the
+// board side by side for a terminal.
+package board
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	taskio "github.com/tristnaja/taski/internal/io"
+)
+
+// None is the column of tasks without a value for the field, e.g. untagged
+// tasks on a tag board.
+const None = "none"
+
+// Fields are what a board can be grouped by.
+var Fields = []string{"status", "tag", "context", "priority"}
+
+// minColumnWidth is the narrowest a column is drawn, even when the terminal
+// is too small to fit every column.
+const minColumnWidth = 16
+
+type Column struct {
+	Name string
+	// Limit is the WIP limit; zero means unlimited.
+	Limit int
+	Tasks []taskio.Task
+}
+
+// Over reports whether the column holds more tasks than its WIP limit.
+func (c Column) Over() bool {
+	return c.Limit > 0 && len(c.Tasks) > c.Limit
+}
+
+// Board is a set of columns grouped by Field.
+type Board struct {
+	Field   string
+	Columns []Column
+}
+
+// New groups tasks by field into the named columns, or into the default
+// columns for the field when names is empty. Tasks whose value has no
+// column get one appended, so no task is left off the board.
+func New(tasks []taskio.Task, field string, names []string, limits map[string]int) (Board, error) {
+	if !slices.Contains(Fields, field) {
+		return Board{}, fmt.Errorf("unknown board field %q, usable: %s", field, strings.Join(Fields, ", "))
+	}
+
+	if len(names) == 0 {
+		names = DefaultColumns(tasks, field)
+	}
+
+	b := Board{Field: field}
+	index := map[string]int{}
+
+	for _, name := range names {
+		index[name] = len(b.Columns)
+		b.Columns = append(b.Columns, Column{Name: name, Limit: limits[name]})
+	}
+
+	for _, task := range tasks {
+		name := b.ColumnOf(task)
+		i, found := index[name]
+
+		if !found {
+			i = len(b.Columns)
+			index[name] = i
+			b.Columns = append(b.Columns, Column{Name: name, Limit: limits[name]})
+		}
+
+		b.Columns[i].Tasks = append(b.Columns[i].Tasks, task)
+	}
+
+	return b, nil
+}
+
+// DefaultColumns are the columns used when none are configured: pending,
+// doing and done for status, the levels for priority and every label in
+// use for tags and contexts.
+func DefaultColumns(tasks []taskio.Task, field string) []string {
+	switch field {
+	case "status":
+		return []string{taskio.StatusPending, taskio.StatusDoing, taskio.StatusDone}
+	case "priority":
+		return []string{taskio.PriorityHigh, taskio.PriorityMedium, taskio.PriorityLow, None}
+	}
+
+	seen := map[string]bool{}
+
+	for _, task := range tasks {
+		for _, label := range labels(task, field) {
+			seen[label] = true
+		}
+	}
+
+	var names []string
+
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return append(names, None)
+}
+
+// Column returns the column called name.
+func (b Board) Column(name string) (Column, bool) {
+	for _, column := range b.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// ColumnOf returns the column task belongs in. A task with several labels
+// goes in the first column matching one of them.
+func (b Board) ColumnOf(task taskio.Task) string {
+	switch b.Field {
+	case "status":
+		return task.StatusName()
+	case "priority":
+		if task.Priority == "" {
+			return None
+		}
+
+		return task.Priority
+	}
+
+	values := labels(task, b.Field)
+
+	for _, column := range b.Columns {
+		if slices.Contains(values, column.Name) {
+			return column.Name
+		}
+	}
+
+	if len(values) > 0 {
+		return values[0]
+	}
+
+	return None
+}
+
+// Move changes task so that it belongs in column. On tag and context
+// boards only the label of the column the task is leaving is replaced.
+func (b Board) Move(task *taskio.Task, column string) error {
+	switch b.Field {
+	case "status":
+		if !taskio.ValidStatus(column) {
+			return fmt.Errorf("%w: status %q, expected one lowercase word", taskio.ErrInvalid, column)
+		}
+
+		task.Status = column
+
+		if column == taskio.StatusPending {
+			task.Status = ""
+		}
+	case "priority":
+		if column == None {
+			column = ""
+		}
+
+		if !taskio.ValidPriority(column) {
+			return fmt.Errorf("%w: priority %q, usable: high, medium, low, none", taskio.ErrInvalid, column)
+		}
+
+		task.Priority = column
+	default:
+		if strings.ContainsAny(column, " \t") {
+			return fmt.Errorf("%w: %s %q contains spaces", taskio.ErrInvalid, b.Field, column)
+		}
+
+		from := b.ColumnOf(*task)
+		values := slices.DeleteFunc(slices.Clone(labels(*task, b.Field)), func(label string) bool {
+			return label == from
+		})
+
+		if column != None && !slices.Contains(values, column) {
+			values = append(values, column)
+		}
+
+		if len(values) == 0 {
+			values = nil
+		}
+
+		if b.Field == "tag" {
+			task.Tags = values
+		} else {
+			task.Contexts = values
+		}
+	}
+
+	return nil
+}
+
+func labels(task taskio.Task, field string) []string {
+	if field == "context" {
+		return task.Contexts
+	}
+
+	return task.Tags
+}
+
+// Render draws the columns side by side, each fitted into an equal share of
+// width, with cards wrapped to the column. Columns over their WIP limit get
+// a red header when color is set and a "!" either way.
+func (b Board) Render(w io.Writer, width int, color bool) error {
+	if len(b.Columns) == 0 {
+		_, err := io.WriteString(w, "No columns.\n")
+		return err
+	}
+
+	columnWidth := max(minColumnWidth, (width-2*(len(b.Columns)-1))/len(b.Columns))
+	cells := make([][]string, len(b.Columns))
+	rows := 0
+
+	for i, column := range b.Columns {
+		header := strings.ToUpper(column.Name) + " (" + strconv.Itoa(len(column.Tasks))
+
+		if column.Limit > 0 {
+			header += "/" + strconv.Itoa(column.Limit)
+		}
+
+		header += ")"
+		style := "1"
+
+		if column.Over() {
+			header += " !"
+			style = "1;31"
+		}
+
+		cells[i] = []string{
+			paint(truncate(header, columnWidth), style, color),
+			paint(strings.Repeat("─", columnWidth), "2", color),
+		}
+
+		for _, task := range column.Tasks {
+			cells[i] = append(cells[i], card(task, columnWidth, color)...)
+		}
+
+		rows = max(rows, len(cells[i]))
+	}
+
+	var out strings.Builder
+
+	for row := 0; row < rows; row++ {
+		var line strings.Builder
+
+		for i := range cells {
+			cell := ""
+
+			if row < len(cells[i]) {
+				cell = cells[i][row]
+			}
+
+			line.WriteString(cell)
+
+			if i < len(cells)-1 {
+				line.WriteString(strings.Repeat(" ", columnWidth-visibleLen(cell)+2))
+			}
+		}
+
+		out.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+
+	_, err := io.WriteString(w, out.String())
+
+	if err != nil {
+		return fmt.Errorf("writing board: %w", err)
+	}
+
+	return nil
+}
+
+// card renders a task as its id and wrapped title, with the due date and
+// priority underneath.
+func card(task taskio.Task, width int, color bool) []string {
+	id := "#" + strconv.Itoa(task.ID) + " "
+	lines := wrap(id+task.Title, width, strings.Repeat(" ", len(id)))
+
+	if task.IsDone() {
+		for i := range lines {
+			lines[i] = paint(lines[i], "2", color)
+		}
+	}
+
+	var details []string
+
+	if task.Priority != "" {
+		details = append(details, "!"+task.Priority)
+	}
+
+	if task.Due != nil {
+		details = append(details, "due "+task.Due.Format("2006-01-02"))
+	}
+
+	if len(details) > 0 {
+		detail := truncate(strings.Repeat(" ", len(id))+strings.Join(details, " "), width)
+		lines = append(lines, paint(detail, "2", color))
+	}
+
+	return lines
+}
+
+// wrap splits text into lines of at most width characters; following lines
+// start with indent and words longer than a line are cut.
+func wrap(text string, width int, indent string) []string {
+	var lines []string
+	line := ""
+
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line+" "+word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, truncate(line, width))
+			line = indent + word
+		}
+	}
+
+	return append(lines, truncate(line, width))
+}
+
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	return string([]rune(text)[:width-1]) + "…"
+}
+
+func paint(text string, style string, color bool) string {
+	if !color || text == "" {
+		return text
+	}
+
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
+
+func visibleLen(text string) int {
+	if strings.Contains(text, "\x1b[") {
+		text = text[strings.IndexByte(text, 'm')+1 : strings.LastIndex(text, "\x1b[")]
+	}
+
+	return utf8.RuneCountInString(text)
+}
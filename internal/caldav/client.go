@@ -0,0 +1,247 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrPrecondition = errors.New("resource changed on the server")
+
+type Client struct {
+	BaseURL    *url.URL
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+// Resource is a calendar object as listed by the server.
+type Resource struct {
+	Href string
+	ETag string
+}
+
+func NewClient(collection string, username string, password string) (*Client, error) {
+	if !strings.HasSuffix(collection, "/") {
+		collection += "/"
+	}
+
+	base, err := url.Parse(collection)
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing collection url: %w", err)
+	}
+
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported collection url %q", collection)
+	}
+
+	return &Client{
+		BaseURL:    base,
+		Username:   username,
+		Password:   password,
+		HTTPClient: http.DefaultClient,
+	}, nil
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/><d:resourcetype/></d:prop></d:propfind>`
+
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ETag         string `xml:"getetag"`
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List returns every calendar object in the collection with its ETag.
+func (c *Client) List() ([]Resource, error) {
+	req, err := c.newRequest("PROPFIND", c.BaseURL.String(), strings.NewReader(propfindBody))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return nil, fmt.Errorf("listing collection: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("listing collection: unexpected status %s", resp.Status)
+	}
+
+	var result multistatus
+
+	err = xml.NewDecoder(resp.Body).Decode(&result)
+
+	if err != nil {
+		return nil, fmt.Errorf("decoding multistatus: %w", err)
+	}
+
+	var resources []Resource
+
+	for _, response := range result.Responses {
+		href, err := c.resolve(response.Href)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, propstat := range response.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") || propstat.Prop.ResourceType.Collection != nil {
+				continue
+			}
+
+			if !strings.HasSuffix(href, ".ics") {
+				continue
+			}
+
+			resources = append(resources, Resource{Href: href, ETag: propstat.Prop.ETag})
+		}
+	}
+
+	return resources, nil
+}
+
+func (c *Client) Get(href string) ([]byte, string, error) {
+	req, err := c.newRequest(http.MethodGet, href, nil)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching %s: %w", href, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetching %s: unexpected status %s", href, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", href, err)
+	}
+
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// Put uploads a calendar object. An empty etag creates the resource and
+// fails if it already exists; otherwise the upload only succeeds while the
+// server still holds that version.
+func (c *Client) Put(href string, body []byte, etag string) (string, error) {
+	req, err := c.newRequest(http.MethodPut, href, bytes.NewReader(body))
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+
+	if etag == "" {
+		req.Header.Set("If-None-Match", "*")
+	} else {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return "", fmt.Errorf("uploading %s: %w", href, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return "", fmt.Errorf("uploading %s: %w", href, ErrPrecondition)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("uploading %s: unexpected status %s", href, resp.Status)
+	}
+
+	return resp.Header.Get("ETag"), nil
+}
+
+func (c *Client) Delete(href string, etag string) error {
+	req, err := c.newRequest(http.MethodDelete, href, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", href, err)
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	case http.StatusPreconditionFailed:
+		return fmt.Errorf("deleting %s: %w", href, ErrPrecondition)
+	default:
+		return fmt.Errorf("deleting %s: unexpected status %s", href, resp.Status)
+	}
+}
+
+// HrefFor returns the resource path taski uses for a task UID.
+func (c *Client) HrefFor(uid string) string {
+	return c.BaseURL.ResolveReference(&url.URL{Path: url.PathEscape(uid) + ".ics"}).String()
+}
+
+func (c *Client) newRequest(method string, target string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, target, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	return req, nil
+}
+
+func (c *Client) resolve(href string) (string, error) {
+	ref, err := url.Parse(href)
+
+	if err != nil {
+		return "", fmt.Errorf("parsing href %q: %w", href, err)
+	}
+
+	return c.BaseURL.ResolveReference(ref).String(), nil
+}
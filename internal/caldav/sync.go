@@ -0,0 +1,421 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tristnaja/taski/internal/ical"
+	taskio "github.com/tristnaja/taski/internal/io"
+)
+
+const (
+	PolicyLocal  = "local"
+	PolicyRemote = "remote"
+	PolicyNewest = "newest"
+)
+
+type Result struct {
+	Pushed        int
+	Pulled        int
+	DeletedRemote int
+	Conflicts     int
+}
+
+// state remembers, per task UID, which remote version and which local
+// content were last seen in sync. Comparing against it tells which side
+// changed since the previous run.
+type state struct {
+	Entries map[string]entry `json:"entries"`
+}
+
+type entry struct {
+	Href string `json:"href"`
+	ETag string `json:"etag"`
+	Hash string `json:"hash"`
+}
+
+type remote struct {
+	href string
+	etag string
+	task *taskio.Task
+}
+
+func StatePath(fileName string) string {
+	return filepath.Join(filepath.Dir(fileName), "caldav-state.json")
+}
+
+func ValidPolicy(policy string) bool {
+	return policy == PolicyLocal || policy == PolicyRemote || policy == PolicyNewest
+}
+
+// Sync reconciles the database with the CalDAV collection. Tasks in trash
+// are kept remotely as CANCELLED; once CleanUp purges them the remote
+// object is deleted as well.
+func Sync(client *Client, fileName string, policy string) (Result, error) {
+	var result Result
+
+	if !ValidPolicy(policy) {
+		return result, fmt.Errorf("unknown conflict policy %q, usable: local, remote, newest", policy)
+	}
+
+	st, err := loadState(StatePath(fileName))
+
+	if err != nil {
+		return result, fmt.Errorf("loading sync state: %w", err)
+	}
+
+	db, err := taskio.ReadAll(fileName)
+
+	if err != nil {
+		return result, fmt.Errorf("reading tasks: %w", err)
+	}
+
+	locals := make(map[string]taskio.Task, len(db.Tasks))
+
+	for _, task := range db.Tasks {
+		locals[task.StableUID()] = task
+	}
+
+	remotes, err := fetchRemotes(client, st)
+
+	if err != nil {
+		return result, err
+	}
+
+	uids := make(map[string]bool)
+
+	for uid := range locals {
+		uids[uid] = true
+	}
+
+	for uid := range remotes {
+		uids[uid] = true
+	}
+
+	for uid := range st.Entries {
+		uids[uid] = true
+	}
+
+	sorted := make([]string, 0, len(uids))
+
+	for uid := range uids {
+		sorted = append(sorted, uid)
+	}
+
+	sort.Strings(sorted)
+
+	var pulled []taskio.Task
+
+	for _, uid := range sorted {
+		local, hasLocal := locals[uid]
+		rem, hasRemote := remotes[uid]
+		last, hasState := st.Entries[uid]
+
+		localChanged := hasLocal && (!hasState || hashTask(local) != last.Hash)
+		remoteChanged := hasRemote && (!hasState || rem.etag != last.ETag || last.ETag == "")
+
+		switch {
+		case hasLocal && hasRemote:
+			if !localChanged && !remoteChanged {
+				continue
+			}
+
+			if rem.task == nil {
+				err = fetchTask(client, &rem)
+
+				if err != nil {
+					return result, err
+				}
+			}
+
+			if hashTask(local) == hashTask(*rem.task) {
+				st.Entries[uid] = entry{Href: rem.href, ETag: rem.etag}
+				continue
+			}
+
+			pushLocal := localChanged && !remoteChanged
+
+			if localChanged && remoteChanged {
+				result.Conflicts++
+				pushLocal = resolve(policy, local, *rem.task)
+			}
+
+			if pushLocal {
+				etag, err := push(client, rem.href, local, rem.etag)
+
+				if err != nil {
+					return result, err
+				}
+
+				st.Entries[uid] = entry{Href: rem.href, ETag: etag}
+				result.Pushed++
+			} else {
+				pulled = append(pulled, *rem.task)
+				st.Entries[uid] = entry{Href: rem.href, ETag: rem.etag}
+				result.Pulled++
+			}
+		case hasLocal && !hasRemote:
+			if hasState && last.Href == "" && !localChanged {
+				continue
+			}
+
+			if hasState && last.Href != "" && !localChanged {
+				// Deleted on the server and untouched here: move it to trash.
+				trashed := local
+				trashed.IsDeleted = true
+				pulled = append(pulled, trashed)
+				st.Entries[uid] = entry{}
+				result.Pulled++
+				continue
+			}
+
+			href := client.HrefFor(uid)
+			etag, err := push(client, href, local, "")
+
+			if err != nil {
+				return result, err
+			}
+
+			st.Entries[uid] = entry{Href: href, ETag: etag}
+			result.Pushed++
+		case !hasLocal && hasRemote:
+			if hasState {
+				// Purged locally after the trash retention expired.
+				if remoteChanged && policy == PolicyRemote {
+					err = fetchTask(client, &rem)
+
+					if err != nil {
+						return result, err
+					}
+
+					result.Conflicts++
+					pulled = append(pulled, *rem.task)
+					st.Entries[uid] = entry{Href: rem.href, ETag: rem.etag}
+					result.Pulled++
+					continue
+				}
+
+				err = client.Delete(rem.href, rem.etag)
+
+				if err != nil {
+					return result, err
+				}
+
+				delete(st.Entries, uid)
+				result.DeletedRemote++
+				continue
+			}
+
+			if rem.task == nil {
+				err = fetchTask(client, &rem)
+
+				if err != nil {
+					return result, err
+				}
+			}
+
+			pulled = append(pulled, *rem.task)
+			st.Entries[uid] = entry{Href: rem.href, ETag: rem.etag}
+			result.Pulled++
+		default:
+			delete(st.Entries, uid)
+		}
+	}
+
+	if len(pulled) > 0 {
+		_, _, err = taskio.ImportTasks(fileName, pulled)
+
+		if err != nil {
+			return result, fmt.Errorf("applying remote changes: %w", err)
+		}
+	}
+
+	db, err = taskio.ReadAll(fileName)
+
+	if err != nil {
+		return result, fmt.Errorf("reading tasks: %w", err)
+	}
+
+	for _, task := range db.Tasks {
+		uid := task.StableUID()
+
+		if current, found := st.Entries[uid]; found {
+			current.Hash = hashTask(task)
+			st.Entries[uid] = current
+		}
+	}
+
+	err = saveState(StatePath(fileName), st)
+
+	if err != nil {
+		return result, fmt.Errorf("saving sync state: %w", err)
+	}
+
+	return result, nil
+}
+
+func fetchRemotes(client *Client, st state) (map[string]remote, error) {
+	resources, err := client.List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]string, len(st.Entries))
+
+	for uid, e := range st.Entries {
+		known[e.Href] = uid
+	}
+
+	remotes := make(map[string]remote, len(resources))
+
+	for _, resource := range resources {
+		rem := remote{href: resource.Href, etag: resource.ETag}
+
+		if uid, found := known[resource.Href]; found && resource.ETag != "" && st.Entries[uid].ETag == resource.ETag {
+			remotes[uid] = rem
+			continue
+		}
+
+		err = fetchTask(client, &rem)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rem.task == nil {
+			continue
+		}
+
+		remotes[rem.task.StableUID()] = rem
+	}
+
+	return remotes, nil
+}
+
+func fetchTask(client *Client, rem *remote) error {
+	body, etag, err := client.Get(rem.href)
+
+	if err != nil {
+		return err
+	}
+
+	tasks, err := ical.Decode(bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", rem.href, err)
+	}
+
+	if etag != "" {
+		rem.etag = etag
+	}
+
+	if len(tasks) > 0 {
+		rem.task = &tasks[0]
+	}
+
+	return nil
+}
+
+func push(client *Client, href string, task taskio.Task, etag string) (string, error) {
+	var body bytes.Buffer
+
+	err := ical.Encode(&body, []taskio.Task{task})
+
+	if err != nil {
+		return "", fmt.Errorf("encoding task: %w", err)
+	}
+
+	return client.Put(href, body.Bytes(), etag)
+}
+
+// resolve reports whether the local version wins a conflict.
+func resolve(policy string, local taskio.Task, rem taskio.Task) bool {
+	switch policy {
+	case PolicyLocal:
+		return true
+	case PolicyRemote:
+		return false
+	default:
+		return !rem.Date.After(local.Date)
+	}
+}
+
+// hashTask fingerprints the fields that are synced, normalised the way
+// they survive an iCalendar round trip.
+func hashTask(task taskio.Task) string {
+	var due string
+
+	if task.Due != nil {
+		due = task.Due.UTC().Truncate(time.Second).Format(time.RFC3339)
+	}
+
+	// Notes come back from the server in UTC and to the second.
+	var notes [][]string
+
+	for _, note := range task.Notes {
+		notes = append(notes, []string{note.Key(), note.Text})
+	}
+
+	payload, _ := json.Marshal([]any{
+		task.Title,
+		task.Description,
+		due,
+		task.Priority,
+		task.Recurrence,
+		task.Remind,
+		task.Tags,
+		task.Contexts,
+		task.Custom,
+		notes,
+		task.Status,
+		task.IsDeleted,
+	})
+	sum := sha256.Sum256(payload)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func loadState(fileName string) (state, error) {
+	st := state{Entries: make(map[string]entry)}
+
+	content, err := os.ReadFile(fileName)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+
+		return state{}, err
+	}
+
+	err = json.Unmarshal(content, &st)
+
+	if err != nil {
+		return state{}, err
+	}
+
+	if st.Entries == nil {
+		st.Entries = make(map[string]entry)
+	}
+
+	return st, nil
+}
+
+func saveState(fileName string, st state) error {
+	content, err := json.MarshalIndent(st, "", "\t")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, content, 0644)
+}
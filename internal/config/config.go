@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type Config struct {
+	CalDAV    CalDAV           `json:"caldav"`
+	Git       Git              `json:"git"`
+	Board     Board            `json:"board"`
+	Reminders Reminders        `json:"reminders"`
+	Webhooks  []Webhook        `json:"webhooks"`
+	Hooks     Hooks            `json:"hooks"`
+	Fields    map[string]Field `json:"fields"`
+}
+
+type CalDAV struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Conflict string `json:"conflict"`
+}
+
+type Git struct {
+	Repo   string `json:"repo"`
+	Remote string `json:"remote"`
+	Branch string `json:"branch"`
+}
+
+// Board sets up taski board. Columns are used when the board is grouped by
+// Field (status when empty); WIP limits the number of tasks per column.
+type Board struct {
+	Field   string         `json:"field"`
+	Columns []string       `json:"columns"`
+	WIP     map[string]int `json:"wip"`
+}
+
+// Reminders sets up taski daemon. Default reminders apply to tasks with a
+// due date that set none of their own; Notifiers lists where they go.
+type Reminders struct {
+	Default   []string `json:"default"`
+	Notifiers []string `json:"notifiers"`
+	Webhook   string   `json:"webhook"`
+	Email     Email    `json:"email"`
+}
+
+// Email is the SMTP server reminder mails are sent through.
+type Email struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// Webhook receives the task events of the database. Events limits it to
+// some event types, such as task.added; Secret signs the payloads.
+type Webhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// Hooks sets up the hook scripts checking changes to tasks. Dir replaces
+// the hooks directory in the user config directory; Timeout is how long a
+// hook may run, such as "10s".
+type Hooks struct {
+	Dir     string `json:"dir"`
+	Timeout string `json:"timeout"`
+}
+
+// Field declares a custom field of the tasks. Type is string, number,
+// date, enum or duration; Values lists the choices of an enum. Column
+// shows the field in the table of taski view.
+type Field struct {
+	Type   string   `json:"type"`
+	Values []string `json:"values"`
+	Column bool     `json:"column"`
+}
+
+// PathFor returns the config file that belongs to a database; it lives next
+// to data.json so that every database can carry its own settings.
+func PathFor(fileName string) string {
+	return filepath.Join(filepath.Dir(fileName), "config.json")
+}
+
+// Load reads the config file. A missing file is not an error and yields
+// the zero Config.
+func Load(fileName string) (Config, error) {
+	var result Config
+
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{}, nil
+		}
+
+		return Config{}, fmt.Errorf("opening config: %w", err)
+	}
+
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&result)
+
+	if err != nil {
+		return Config{}, fmt.Errorf("decoding config: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,126 @@
+// Package contexts keeps the active context of a database: the GTD context,
+// such as office or phone, that taski context set narrows views and new
+// tasks to. It is stored in a state file next to the database, so that it
+// lasts until taski context none clears it.
+package contexts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// None clears the active context.
+const None = "none"
+
+// State is the content of the state file.
+type State struct {
+	Active string `json:"active"`
+}
+
+// StatePath returns the context state file that belongs to a database.
+func StatePath(fileName string) string {
+	return filepath.Join(filepath.Dir(fileName), "context.json")
+}
+
+// Normalize checks a context name as given to taski context set, with or
+// without the leading @, and returns it without.
+func Normalize(name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+
+	switch {
+	case name == "":
+		return "", fmt.Errorf("empty context name")
+	case strings.EqualFold(name, None):
+		return "", fmt.Errorf("%q clears the context and cannot be a context name", None)
+	case strings.ContainsAny(name, " \t\r\n@+"):
+		return "", fmt.Errorf("invalid context %q, expected one word such as office or phone", name)
+	}
+
+	return name, nil
+}
+
+// Active returns the active context of a database, or "" when there is
+// none.
+func Active(fileName string) (string, error) {
+	content, err := os.ReadFile(StatePath(fileName))
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("reading context state: %w", err)
+	}
+
+	var state State
+
+	err = json.Unmarshal(content, &state)
+
+	if err != nil {
+		return "", fmt.Errorf("decoding context state: %w", err)
+	}
+
+	return state.Active, nil
+}
+
+// Set makes name the active context of a database; an empty name clears
+// it.
+func Set(fileName string, name string) error {
+	path := StatePath(fileName)
+
+	if name == "" {
+		err := os.Remove(path)
+
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clearing context: %w", err)
+		}
+
+		return nil
+	}
+
+	content, err := json.MarshalIndent(State{Active: name}, "", "\t")
+
+	if err != nil {
+		return fmt.Errorf("encoding context state: %w", err)
+	}
+
+	err = os.WriteFile(path, content, 0644)
+
+	if err != nil {
+		return fmt.Errorf("writing context state: %w", err)
+	}
+
+	return nil
+}
+
+// Where narrows a filter expression to the context, which matches tasks
+// with the label @active.
+func Where(where string, active string) string {
+	if active == "" {
+		return where
+	}
+
+	return strings.TrimSpace("@" + active + " " + where)
+}
+
+// Apply adds the active context to the contexts of a new task, unless it is
+// already there.
+func Apply(task *io.Task, active string) {
+	if active == "" {
+		return
+	}
+
+	for _, name := range task.Contexts {
+		if strings.EqualFold(name, active) {
+			return
+		}
+	}
+
+	task.Contexts = append(task.Contexts, active)
+}
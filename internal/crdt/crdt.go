@@ -0,0 +1,359 @@
+package crdt
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/hlc"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+type Stats struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// Merge combines two replicas of the database. Tasks form an add-wins set
+// keyed by UID, every replicated field is a last-writer-wins register
+// ordered by its hybrid logical clock, and soft delete is just another
+// register. Notes are a set keyed by the second they were written, with a
+// register per note, so notes added on both replicas are all kept. Purged
+// tasks leave tombstones which only remove a task from the other replica
+// when nothing was written to it after the purge.
+//
+// The result keeps the local replica identity and task order; tasks only
+// known remotely are appended in remote order.
+func Merge(local io.Database, remote io.Database) (io.Database, Stats) {
+	var stats Stats
+
+	result := Replica(local, remote)
+
+	remoteTasks := make(map[string]io.Task, len(remote.Tasks))
+
+	for _, task := range remote.Tasks {
+		remoteTasks[task.StableUID()] = task
+	}
+
+	seen := make(map[string]bool, len(local.Tasks))
+
+	for _, task := range local.Tasks {
+		uid := task.StableUID()
+		seen[uid] = true
+		other, found := remoteTasks[uid]
+
+		if found {
+			merged, changed := mergeTask(task, other)
+
+			if changed {
+				stats.Updated++
+			}
+
+			result.Tasks = append(result.Tasks, merged)
+			continue
+		}
+
+		if Buried(remote.Tombstones, task) {
+			stats.Removed++
+			continue
+		}
+
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	for _, task := range remote.Tasks {
+		if seen[task.StableUID()] || Buried(local.Tombstones, task) {
+			continue
+		}
+
+		result.Tasks = append(result.Tasks, task)
+		stats.Added++
+	}
+
+	for i := range result.Tasks {
+		result.Tasks[i].ID = i
+
+		if !result.Tasks[i].IsDeleted {
+			result.Size++
+		}
+	}
+
+	return result, stats
+}
+
+// Replica returns the replica state of a merge of local and remote, without
+// tasks: the local identity, a clock that has seen both sides and the
+// tombstones of both.
+func Replica(local io.Database, remote io.Database) io.Database {
+	result := io.Database{
+		Node:       local.Node,
+		Clock:      local.Clock,
+		Tombstones: mergeTombstones(local.Tombstones, remote.Tombstones),
+	}
+
+	if !remote.Clock.IsZero() {
+		if result.Node == "" {
+			result.Node = io.NewUID()[:8]
+		}
+
+		result.Clock = hlc.Receive(local.Clock, remote.Clock, result.Node)
+	}
+
+	return result
+}
+
+// Restamp sets the field clocks of merged, a task that another merge, such
+// as the three-way merge of git sync, built from local and remote. A field
+// or note that kept the value of one side keeps its clock, and one that
+// combines both sides is a new write to db.
+func Restamp(db *io.Database, local io.Task, remote io.Task, merged *io.Task) {
+	fromLocal := io.ChangedFields(local, *merged)
+	fromRemote := io.ChangedFields(remote, *merged)
+	clocks := make(map[string]hlc.Timestamp, len(io.Fields))
+
+	pick := func(field string, sameAsLocal bool, sameAsRemote bool) {
+		var stamp hlc.Timestamp
+
+		switch {
+		case sameAsLocal && sameAsRemote:
+			stamp = hlc.Max(local.Clocks[field], remote.Clocks[field])
+		case sameAsLocal:
+			stamp = local.Clocks[field]
+		case sameAsRemote:
+			stamp = remote.Clocks[field]
+		default:
+			if db.Node == "" {
+				db.Node = io.NewUID()[:8]
+			}
+
+			db.Clock = hlc.Next(db.Clock, db.Node)
+			stamp = db.Clock
+		}
+
+		if !stamp.IsZero() {
+			clocks[field] = stamp
+		}
+	}
+
+	for _, field := range io.Fields {
+		pick(field, !slices.Contains(fromLocal, field), !slices.Contains(fromRemote, field))
+	}
+
+	localNotes, remoteNotes, mergedNotes := byKey(local.Notes), byKey(remote.Notes), byKey(merged.Notes)
+
+	for key := range noteKeys(local, remote, *merged) {
+		note, found := mergedNotes[key]
+		pick(io.NoteField(key), sameNote(localNotes, key, note, found), sameNote(remoteNotes, key, note, found))
+	}
+
+	if len(clocks) == 0 {
+		clocks = nil
+	}
+
+	merged.Clocks = clocks
+}
+
+// sameNote reports whether notes has the note key as note, or lacks it
+// like the other version when found is false.
+func sameNote(notes map[string]io.Note, key string, note io.Note, found bool) bool {
+	other, has := notes[key]
+	return has == found && (!found || other.Text == note.Text)
+}
+
+func mergeTask(local io.Task, remote io.Task) (io.Task, bool) {
+	result := local
+	result.Clocks = make(map[string]hlc.Timestamp, len(io.Fields))
+	changed := false
+
+	for field, stamp := range local.Clocks {
+		result.Clocks[field] = stamp
+	}
+
+	for _, field := range io.Fields {
+		remoteStamp := remote.Clocks[field]
+
+		if field == io.FieldNotes || !remoteStamp.After(local.Clocks[field]) {
+			continue
+		}
+
+		result.Clocks[field] = remoteStamp
+
+		if copyField(&result, remote, field) {
+			changed = true
+		}
+	}
+
+	if mergeNotes(&result, local, remote) {
+		changed = true
+	}
+
+	if remote.Date.After(result.Date) {
+		result.Date = remote.Date
+	}
+
+	if len(result.Clocks) == 0 {
+		result.Clocks = nil
+	}
+
+	return result, changed
+}
+
+// mergeNotes merges the notes of local and remote into result note by
+// note: the later write to a note wins, and a removed note leaves its clock
+// behind as a tombstone. Notes without a clock of their own, written before
+// notes were merged this way, are kept from both sides.
+func mergeNotes(result *io.Task, local io.Task, remote io.Task) bool {
+	notes := byKey(local.Notes)
+	remoteNotes := byKey(remote.Notes)
+
+	for key := range noteKeys(local, remote) {
+		field := io.NoteField(key)
+		localStamp, remoteStamp := local.Clocks[field], remote.Clocks[field]
+		note, found := remoteNotes[key]
+
+		switch {
+		case remoteStamp.After(localStamp):
+			result.Clocks[field] = remoteStamp
+		case localStamp.IsZero() && remoteStamp.IsZero():
+			if _, kept := notes[key]; kept {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if found {
+			notes[key] = note
+		} else {
+			delete(notes, key)
+		}
+	}
+
+	if stamp := hlc.Max(local.Clocks[io.FieldNotes], remote.Clocks[io.FieldNotes]); !stamp.IsZero() {
+		result.Clocks[io.FieldNotes] = stamp
+	}
+
+	merged := slices.SortedFunc(maps.Values(notes), func(a, b io.Note) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	if len(merged) == 0 {
+		merged = nil
+	}
+
+	result.Notes = merged
+
+	return !slices.EqualFunc(local.Notes, merged, io.Note.Equal)
+}
+
+func byKey(notes []io.Note) map[string]io.Note {
+	result := make(map[string]io.Note, len(notes))
+
+	for _, note := range notes {
+		result[note.Key()] = note
+	}
+
+	return result
+}
+
+// noteKeys returns the keys of the notes of both tasks, including removed
+// notes that still have a clock.
+func noteKeys(tasks ...io.Task) map[string]bool {
+	keys := make(map[string]bool)
+
+	for _, task := range tasks {
+		for _, note := range task.Notes {
+			keys[note.Key()] = true
+		}
+
+		for field := range task.Clocks {
+			if key, found := strings.CutPrefix(field, io.NoteField("")); found {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// copyField sets one register of dst from src and reports whether the
+// value actually changed.
+func copyField(dst *io.Task, src io.Task, field string) bool {
+	before := *dst
+
+	switch field {
+	case io.FieldTitle:
+		dst.Title = src.Title
+		return before.Title != dst.Title
+	case io.FieldDescription:
+		dst.Description = src.Description
+		return before.Description != dst.Description
+	case io.FieldDue:
+		dst.Due = src.Due
+		return (before.Due == nil) != (dst.Due == nil) || before.Due != nil && !before.Due.Equal(*dst.Due)
+	case io.FieldPriority:
+		dst.Priority = src.Priority
+		return before.Priority != dst.Priority
+	case io.FieldRecurrence:
+		dst.Recurrence = src.Recurrence
+		return before.Recurrence != dst.Recurrence
+	case io.FieldRemind:
+		dst.Remind = slices.Clone(src.Remind)
+		return !slices.Equal(before.Remind, dst.Remind)
+	case io.FieldCustom:
+		dst.Custom = maps.Clone(src.Custom)
+		return !maps.Equal(before.Custom, dst.Custom)
+	case io.FieldStatus:
+		dst.Status = src.Status
+		return before.Status != dst.Status
+	case io.FieldTags:
+		dst.Tags = slices.Clone(src.Tags)
+		return !slices.Equal(before.Tags, dst.Tags)
+	case io.FieldContexts:
+		dst.Contexts = slices.Clone(src.Contexts)
+		return !slices.Equal(before.Contexts, dst.Contexts)
+	case io.FieldDeleted:
+		dst.IsDeleted = src.IsDeleted
+		dst.DeletedAt = src.DeletedAt
+		return before.IsDeleted != dst.IsDeleted
+	default:
+		return false
+	}
+}
+
+// Buried reports whether a tombstone was written after the last write to
+// any field of the task. Later writes win, which keeps the set add-wins.
+func Buried(tombstones map[string]hlc.Timestamp, task io.Task) bool {
+	tombstone, found := tombstones[task.StableUID()]
+
+	if !found {
+		return false
+	}
+
+	var latest hlc.Timestamp
+
+	for _, stamp := range task.Clocks {
+		latest = hlc.Max(latest, stamp)
+	}
+
+	return tombstone.After(latest)
+}
+
+func mergeTombstones(a map[string]hlc.Timestamp, b map[string]hlc.Timestamp) map[string]hlc.Timestamp {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	result := make(map[string]hlc.Timestamp, len(a)+len(b))
+
+	for uid, stamp := range a {
+		result[uid] = stamp
+	}
+
+	for uid, stamp := range b {
+		result[uid] = hlc.Max(result[uid], stamp)
+	}
+
+	return result
+}
@@ -0,0 +1,397 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	taskio "github.com/tristnaja/taski/internal/io"
+)
+
+const dateLayout = "2006-01-02"
+
+var Formats = []string{"todotxt", "markdown", "csv", "html"}
+
+func Write(w io.Writer, format string, tasks []taskio.Task) error {
+	write, err := writer(format)
+
+	if err != nil {
+		return err
+	}
+
+	return write(w, tasks)
+}
+
+// Check returns the error Write would give for an unknown format, so that a
+// caller can reject it before creating the output file.
+func Check(format string) error {
+	_, err := writer(format)
+	return err
+}
+
+func writer(format string) (func(io.Writer, []taskio.Task) error, error) {
+	switch strings.ToLower(format) {
+	case "todotxt", "todo.txt", "txt":
+		return writeTodoTxt, nil
+	case "markdown", "md":
+		return writeMarkdown, nil
+	case "csv":
+		return writeCSV, nil
+	case "html":
+		return writeHTML, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, usable: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeTodoTxt(w io.Writer, tasks []taskio.Task) error {
+	for _, task := range tasks {
+		var line strings.Builder
+
+		if task.IsDone() {
+			line.WriteString("x ")
+		}
+
+		if letter := todoPriority(task.Priority); letter != "" {
+			line.WriteString("(" + letter + ") ")
+		}
+
+		if !task.Date.IsZero() {
+			line.WriteString(task.Date.Format(dateLayout) + " ")
+		}
+
+		line.WriteString(oneLine(task.Title))
+
+		for _, tag := range task.Tags {
+			line.WriteString(" +" + tag)
+		}
+
+		for _, context := range task.Contexts {
+			line.WriteString(" @" + context)
+		}
+
+		if task.Due != nil {
+			line.WriteString(" due:" + task.Due.Format(dateLayout))
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(task.Custom)) {
+			line.WriteString(" " + name + ":" + strings.Join(strings.Fields(task.Custom[name]), "_"))
+		}
+
+		line.WriteString(" id:" + strconv.Itoa(task.ID))
+
+		if task.IsDeleted && task.DeletedAt != nil {
+			line.WriteString(" deleted:" + task.DeletedAt.Format(dateLayout))
+		}
+
+		_, err := fmt.Fprintln(w, line.String())
+
+		if err != nil {
+			return fmt.Errorf("writing todo.txt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeMarkdown(w io.Writer, tasks []taskio.Task) error {
+	var active, trashed []taskio.Task
+
+	for _, task := range tasks {
+		if task.IsDeleted {
+			trashed = append(trashed, task)
+		} else {
+			active = append(active, task)
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# Tasks\n\n")
+
+	if groups, untagged := byTag(active); len(groups) > 0 {
+		for _, group := range groups {
+			fmt.Fprintf(&b, "## +%s\n\n", group.tag)
+			writeChecklist(&b, group.tasks, false)
+			b.WriteString("\n")
+		}
+
+		if len(untagged) > 0 {
+			b.WriteString("## Untagged\n\n")
+			writeChecklist(&b, untagged, false)
+		}
+	} else {
+		writeChecklist(&b, active, false)
+	}
+
+	if len(trashed) > 0 {
+		b.WriteString("\n## Trash\n\n")
+		writeChecklist(&b, trashed, true)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	if err != nil {
+		return fmt.Errorf("writing markdown: %w", err)
+	}
+
+	return nil
+}
+
+func writeChecklist(b *strings.Builder, tasks []taskio.Task, struck bool) {
+	if len(tasks) == 0 {
+		b.WriteString("_No tasks._\n")
+		return
+	}
+
+	for _, task := range tasks {
+		title := "**" + oneLine(task.Title) + "**"
+
+		if struck {
+			title = "~~" + oneLine(task.Title) + "~~"
+		}
+
+		box := " "
+
+		if task.IsDone() {
+			box = "x"
+		}
+
+		fmt.Fprintf(b, "- [%s] %s (#%d)%s\n", box, title, task.ID, labels(task))
+
+		if task.Description != "" {
+			for _, line := range strings.Split(task.Description, "\n") {
+				fmt.Fprintf(b, "  %s\n", line)
+			}
+		}
+
+		for _, note := range task.Notes {
+			fmt.Fprintf(b, "  - _%s_ %s\n", note.Time.Format("2006-01-02 15:04"), oneLine(note.Text))
+		}
+	}
+}
+
+func writeCSV(w io.Writer, tasks []taskio.Task) error {
+	writer := csv.NewWriter(w)
+
+	// Every custom field used by any task gets a column after the built-in
+	// ones.
+	var custom []string
+
+	for _, task := range tasks {
+		for name := range task.Custom {
+			if !slices.Contains(custom, name) {
+				custom = append(custom, name)
+			}
+		}
+	}
+
+	slices.Sort(custom)
+
+	err := writer.Write(append([]string{"id", "title", "description", "date", "is_deleted", "deleted_at", "due", "priority", "tags", "contexts", "status", "notes"}, custom...))
+
+	if err != nil {
+		return fmt.Errorf("writing csv header: %w", err)
+	}
+
+	for _, task := range tasks {
+		deletedAt := ""
+		due := ""
+
+		if task.DeletedAt != nil {
+			deletedAt = task.DeletedAt.Format(time.RFC3339)
+		}
+
+		if task.Due != nil {
+			due = task.Due.Format(time.RFC3339)
+		}
+
+		row := []string{
+			strconv.Itoa(task.ID),
+			task.Title,
+			task.Description,
+			task.Date.Format(time.RFC3339),
+			strconv.FormatBool(task.IsDeleted),
+			deletedAt,
+			due,
+			task.Priority,
+			strings.Join(task.Tags, " "),
+			strings.Join(task.Contexts, " "),
+			task.StatusName(),
+			notes(task),
+		}
+
+		for _, name := range custom {
+			row = append(row, task.Custom[name])
+		}
+
+		err = writer.Write(row)
+
+		if err != nil {
+			return fmt.Errorf("writing csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+
+	if err != nil {
+		return fmt.Errorf("flushing csv: %w", err)
+	}
+
+	return nil
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("02 Jan 2006, 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Taski Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { border-bottom: 2px solid #00add8; padding-bottom: .3rem; }
+.task { border: 1px solid #ddd; border-radius: 6px; padding: .75rem 1rem; margin: .75rem 0; }
+.task.deleted { opacity: .6; }
+.task.deleted h2, .task.done h2 { text-decoration: line-through; }
+.task h2 { font-size: 1.1rem; margin: 0 0 .3rem; }
+.meta { color: #777; font-size: .85rem; }
+.desc { white-space: pre-wrap; margin-top: .5rem; }
+.field { margin-right: .75rem; }
+.notes { margin: .5rem 0 0; padding-left: 1.2rem; white-space: pre-wrap; }
+.label { display: inline-block; background: #e6f7fb; color: #00758f; border-radius: 3px; padding: 0 .35rem; margin-right: .25rem; font-size: .8rem; }
+</style>
+</head>
+<body>
+<h1>Taski Report</h1>
+<p class="meta">Generated {{date .Generated}} &middot; {{len .Tasks}} task(s)</p>
+{{range .Tasks}}<div class="task{{if .IsDeleted}} deleted{{end}}{{if .IsDone}} done{{end}}">
+<h2>{{.Title}}</h2>
+<div class="meta">#{{.ID}} &middot; {{date .Date}}{{if .Due}} &middot; due {{date .Due}}{{end}}{{if .Priority}} &middot; {{.Priority}} priority{{end}}{{if .IsDone}} &middot; done{{end}}{{if .IsDeleted}} &middot; in trash{{end}}</div>
+{{if or .Tags .Contexts}}<div>{{range .Tags}}<span class="label">+{{.}}</span>{{end}}{{range .Contexts}}<span class="label">@{{.}}</span>{{end}}</div>{{end}}
+{{if .Custom}}<div class="meta">{{range $name, $value := .Custom}}<span class="field">{{$name}}: {{$value}}</span> {{end}}</div>{{end}}
+{{if .Description}}<div class="desc">{{.Description}}</div>{{end}}
+{{if .Notes}}<ul class="notes">{{range .Notes}}<li><span class="meta">{{date .Time}}</span> {{.Text}}</li>{{end}}</ul>{{end}}
+</div>
+{{else}}<p>No tasks.</p>
+{{end}}</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, tasks []taskio.Task) error {
+	data := struct {
+		Generated time.Time
+		Tasks     []taskio.Task
+	}{
+		Generated: time.Now(),
+		Tasks:     tasks,
+	}
+
+	err := reportTemplate.Execute(w, data)
+
+	if err != nil {
+		return fmt.Errorf("rendering html: %w", err)
+	}
+
+	return nil
+}
+
+type tagGroup struct {
+	tag   string
+	tasks []taskio.Task
+}
+
+// byTag groups tasks under each of their tags, sorted by tag. A task with
+// several tags is listed in every group.
+func byTag(tasks []taskio.Task) ([]tagGroup, []taskio.Task) {
+	var untagged []taskio.Task
+	grouped := make(map[string][]taskio.Task)
+
+	for _, task := range tasks {
+		if len(task.Tags) == 0 {
+			untagged = append(untagged, task)
+		}
+
+		for _, tag := range task.Tags {
+			grouped[tag] = append(grouped[tag], task)
+		}
+	}
+
+	var groups []tagGroup
+
+	for tag, tasks := range grouped {
+		groups = append(groups, tagGroup{tag: tag, tasks: tasks})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].tag < groups[j].tag
+	})
+
+	return groups, untagged
+}
+
+// labels renders the contexts, priority, due date and custom fields of a
+// task as a checklist suffix.
+func labels(task taskio.Task) string {
+	var parts []string
+
+	for _, context := range task.Contexts {
+		parts = append(parts, "@"+context)
+	}
+
+	if task.Priority != "" {
+		parts = append(parts, "!"+task.Priority)
+	}
+
+	if task.Due != nil {
+		parts = append(parts, "due "+task.Due.Format(dateLayout))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(task.Custom)) {
+		parts = append(parts, name+":"+oneLine(task.Custom[name]))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(parts, " ")
+}
+
+// notes renders the notes of a task for a CSV cell, one per line with the
+// time it was written.
+func notes(task taskio.Task) string {
+	lines := make([]string, len(task.Notes))
+
+	for i, note := range task.Notes {
+		lines[i] = note.Time.Format(time.RFC3339) + " " + note.Text
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func todoPriority(priority string) string {
+	switch priority {
+	case taskio.PriorityHigh:
+		return "A"
+	case taskio.PriorityMedium:
+		return "B"
+	case taskio.PriorityLow:
+		return "C"
+	default:
+		return ""
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,227 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+)
+
+// Filter is a parsed --where expression. Every term has to match for a
+// task to be selected.
+type Filter struct {
+	terms []term
+}
+
+type term struct {
+	key   string
+	value string
+	// op compares a custom field that is not a string: <, <=, > or >=,
+	// or empty for equality.
+	op     string
+	fields uda.Fields
+}
+
+var keys = map[string]bool{
+	"id":      true,
+	"title":   true,
+	"desc":    true,
+	"note":    true,
+	"tag":     true,
+	"context": true,
+	"status":  true,
+}
+
+// Parse reads space separated terms. A term is either key:value, +tag,
+// @context or a bare word, which is matched against the title, the
+// description and the notes.
+func Parse(expr string) (Filter, error) {
+	return ParseFields(expr, nil)
+}
+
+// ParseFields is Parse with the custom fields of the database as further
+// keys. A string field matches values containing the text; other fields
+// match equal values, or compare with <, <=, > or >= in front of the
+// value, e.g. estimate:<2h or deadline:>=2026-03-01.
+func ParseFields(expr string, fields uda.Fields) (Filter, error) {
+	var f Filter
+
+	for _, field := range strings.Fields(expr) {
+		key, value, found := strings.Cut(field, ":")
+
+		if len(field) > 1 && (field[0] == '+' || field[0] == '@') {
+			key, value, found = "tag", field[1:], true
+
+			if field[0] == '@' {
+				key = "context"
+			}
+		}
+
+		if !found {
+			f.terms = append(f.terms, term{value: strings.ToLower(field)})
+			continue
+		}
+
+		key = strings.ToLower(key)
+
+		if _, custom := fields[key]; custom && !keys[key] {
+			t, err := customTerm(key, value, fields)
+
+			if err != nil {
+				return Filter{}, err
+			}
+
+			f.terms = append(f.terms, t)
+			continue
+		}
+
+		if !keys[key] {
+			return Filter{}, fmt.Errorf("unknown filter key %q", key)
+		}
+
+		if value == "" {
+			return Filter{}, fmt.Errorf("empty value for filter key %q", key)
+		}
+
+		if key == "id" {
+			_, err := strconv.Atoi(value)
+
+			if err != nil {
+				return Filter{}, fmt.Errorf("invalid id %q: %w", value, err)
+			}
+		}
+
+		if key == "status" && !io.ValidStatus(strings.ToLower(value)) {
+			return Filter{}, fmt.Errorf("invalid status %q, expected one word such as %s, %s or %s", value, io.StatusPending, io.StatusDoing, io.StatusDone)
+		}
+
+		f.terms = append(f.terms, term{key: key, value: strings.ToLower(value)})
+	}
+
+	return f, nil
+}
+
+func (f Filter) Match(task io.Task) bool {
+	for _, t := range f.terms {
+		if !t.match(task) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f Filter) Apply(tasks []io.Task) []io.Task {
+	var result []io.Task
+
+	for _, task := range tasks {
+		if f.Match(task) {
+			result = append(result, task)
+		}
+	}
+
+	return result
+}
+
+func customTerm(key string, value string, fields uda.Fields) (term, error) {
+	t := term{key: key, fields: fields}
+
+	if fields.Ordered(key) {
+		for _, op := range []string{"<=", ">=", "<", ">"} {
+			if rest, found := strings.CutPrefix(value, op); found {
+				t.op, value = op, rest
+				break
+			}
+		}
+	}
+
+	if value == "" {
+		return term{}, fmt.Errorf("empty value for filter key %q", key)
+	}
+
+	normalized, err := fields.Normalize(key, value, time.Now())
+
+	if err != nil {
+		return term{}, fmt.Errorf("invalid filter term %s: %w", key, err)
+	}
+
+	t.value = normalized
+
+	return t, nil
+}
+
+func (t term) match(task io.Task) bool {
+	if t.fields != nil {
+		return t.matchCustom(task.Custom[t.key])
+	}
+
+	title := strings.ToLower(task.Title)
+	description := strings.ToLower(task.Description)
+
+	switch t.key {
+	case "id":
+		return strconv.Itoa(task.ID) == t.value
+	case "title":
+		return strings.Contains(title, t.value)
+	case "desc":
+		return strings.Contains(description, t.value)
+	case "note":
+		return hasNote(task.Notes, t.value)
+	case "tag":
+		return hasLabel(task.Tags, t.value)
+	case "context":
+		return hasLabel(task.Contexts, t.value)
+	case "status":
+		return task.StatusName() == t.value
+	default:
+		return strings.Contains(title, t.value) || strings.Contains(description, t.value) || hasNote(task.Notes, t.value)
+	}
+}
+
+func (t term) matchCustom(stored string) bool {
+	if stored == "" {
+		return false
+	}
+
+	if !t.fields.Ordered(t.key) {
+		return strings.Contains(strings.ToLower(stored), strings.ToLower(t.value))
+	}
+
+	c := t.fields.Compare(t.key, stored, t.value)
+
+	switch t.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return c == 0
+	}
+}
+
+func hasNote(notes []io.Note, value string) bool {
+	for _, note := range notes {
+		if strings.Contains(strings.ToLower(note.Text), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasLabel(labels []string, value string) bool {
+	for _, label := range labels {
+		if strings.ToLower(label) == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,373 @@
+// Package frontmatter renders tasks as Markdown documents with a front
+// matter header, so they can be edited as text, and parses them back:
+//
+//	---
+//	title: Buy milk
+//	tags: errands, shop
+//	due: 2026-03-01
+//	ticket: OPS-12
+//	---
+//	The description, as Markdown.
+//
+// Custom fields declared in config.json follow the built-in keys.
+package frontmatter
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/quickadd"
+	"github.com/tristnaja/taski/internal/remind"
+	"github.com/tristnaja/taski/internal/uda"
+)
+
+const delimiter = "---"
+
+// keys are the header keys in the order they are written.
+var keys = []string{"id", "title", "tags", "contexts", "due", "priority", "recurrence", "remind", "status"}
+
+// Document is one task read back from text. ID is -1 for a document without
+// an id key, i.e. a task that does not exist yet.
+type Document struct {
+	ID   int
+	Line int
+	Task io.Task
+}
+
+// Encode writes the editable fields of task as a document. The id key is
+// only written when withID is set, which is what EncodeAll uses to tell the
+// tasks of a list apart. Every custom field gets a key, so empty ones can
+// be filled in.
+func Encode(task io.Task, withID bool, fields uda.Fields) string {
+	var b strings.Builder
+
+	b.WriteString(delimiter + "\n")
+
+	if withID {
+		fmt.Fprintf(&b, "id: %d\n", task.ID)
+	}
+
+	due := ""
+
+	if task.Due != nil {
+		due = formatDate(*task.Due)
+	}
+
+	fmt.Fprintf(&b, "title: %s\n", task.Title)
+	fmt.Fprintf(&b, "tags: %s\n", strings.Join(task.Tags, ", "))
+	fmt.Fprintf(&b, "contexts: %s\n", strings.Join(task.Contexts, ", "))
+	fmt.Fprintf(&b, "due: %s\n", due)
+	fmt.Fprintf(&b, "priority: %s\n", task.Priority)
+	fmt.Fprintf(&b, "recurrence: %s\n", task.Recurrence)
+	fmt.Fprintf(&b, "remind: %s\n", strings.Join(task.Remind, ", "))
+	fmt.Fprintf(&b, "status: %s\n", task.StatusName())
+
+	for _, name := range fields.Names() {
+		fmt.Fprintf(&b, "%s: %s\n", name, task.Custom[name])
+	}
+
+	b.WriteString(delimiter + "\n")
+
+	if task.Description != "" {
+		b.WriteString(task.Description + "\n")
+	}
+
+	return b.String()
+}
+
+// EncodeAll writes every task as a document with its id, separated by a
+// blank line.
+func EncodeAll(tasks []io.Task, fields uda.Fields) string {
+	documents := make([]string, len(tasks))
+
+	for i, task := range tasks {
+		documents[i] = Encode(task, true, fields)
+	}
+
+	return strings.Join(documents, "\n")
+}
+
+// Decode parses text written by Encode or EncodeAll. A line holding only
+// --- starts a new document when the line after it is a header key, so
+// Markdown rules inside descriptions are kept. Custom fields are checked
+// against their type; an empty one is kept as "" in Task.Custom, which
+// Apply takes as removing it.
+func Decode(text string, now time.Time, fields uda.Fields) ([]Document, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var documents []Document
+	i := 0
+
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) != delimiter {
+			return nil, fmt.Errorf("line %d: expected %q to start a task", i+1, delimiter)
+		}
+
+		document := Document{ID: -1, Line: i + 1}
+		seen := map[string]bool{}
+		i++
+
+		for ; i < len(lines) && strings.TrimSpace(lines[i]) != delimiter; i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				continue
+			}
+
+			key, value, found := strings.Cut(lines[i], ":")
+			key = strings.ToLower(strings.TrimSpace(key))
+
+			if !found || !isKey(key) && fields[key].Type == "" {
+				return nil, fmt.Errorf("line %d: unknown key %q, usable: %s", i+1, key, strings.Join(append(keys, fields.Names()...), ", "))
+			}
+
+			if seen[key] {
+				return nil, fmt.Errorf("line %d: %s given twice", i+1, key)
+			}
+
+			seen[key] = true
+			err := set(&document, key, strings.TrimSpace(value), now, fields)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+
+		if i == len(lines) {
+			return nil, fmt.Errorf("line %d: header is not closed with %q", document.Line, delimiter)
+		}
+
+		i++
+		start := i
+
+		for i < len(lines) && !startsDocument(lines, i) {
+			i++
+		}
+
+		document.Task.Description = strings.TrimSpace(strings.Join(lines[start:i], "\n"))
+
+		if document.Task.Title == "" {
+			return nil, fmt.Errorf("line %d: task has no title", document.Line)
+		}
+
+		documents = append(documents, document)
+	}
+
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no task found")
+	}
+
+	return documents, nil
+}
+
+// Apply copies the edited fields onto task. A due date that is written the
+// same as before is left as is, so it does not move between time zones.
+// Only the custom fields edited holds are set, or removed when empty, so
+// values of fields no longer declared are kept.
+func Apply(task *io.Task, edited io.Task) {
+	task.Title = edited.Title
+	task.Description = edited.Description
+	task.Tags = edited.Tags
+	task.Contexts = edited.Contexts
+	task.Priority = edited.Priority
+	task.Recurrence = edited.Recurrence
+	task.Remind = edited.Remind
+	task.Status = edited.Status
+
+	if len(edited.Custom) > 0 {
+		task.Custom = maps.Clone(task.Custom)
+
+		for name, value := range edited.Custom {
+			if value == "" {
+				delete(task.Custom, name)
+			} else {
+				if task.Custom == nil {
+					task.Custom = make(map[string]string)
+				}
+
+				task.Custom[name] = value
+			}
+		}
+
+		if len(task.Custom) == 0 {
+			task.Custom = nil
+		}
+	}
+
+	if edited.Due == nil || task.Due == nil || formatDate(*edited.Due) != formatDate(*task.Due) {
+		task.Due = edited.Due
+	}
+}
+
+func set(document *Document, key string, value string, now time.Time, fields uda.Fields) error {
+	task := &document.Task
+
+	switch key {
+	case "id":
+		id, err := strconv.Atoi(value)
+
+		if err != nil || id < 0 {
+			return fmt.Errorf("invalid id %q", value)
+		}
+
+		document.ID = id
+	case "title":
+		task.Title = value
+	case "tags":
+		task.Tags = splitList(value)
+	case "contexts":
+		task.Contexts = splitList(value)
+	case "due":
+		if value == "" {
+			return nil
+		}
+
+		due, err := quickadd.ParseDate(value, now)
+
+		if err != nil {
+			return err
+		}
+
+		task.Due = &due
+	case "priority":
+		value = strings.ToLower(value)
+
+		if !io.ValidPriority(value) {
+			return fmt.Errorf("invalid priority %q, usable: high, medium, low", value)
+		}
+
+		task.Priority = value
+	case "recurrence":
+		task.Recurrence = value
+	case "remind":
+		settings, err := remind.Normalize(value)
+
+		if err != nil {
+			return err
+		}
+
+		task.Remind = settings
+	case "status":
+		value = strings.ToLower(value)
+
+		if !io.ValidStatus(value) {
+			return fmt.Errorf("invalid status %q, expected one word such as pending, doing or done", value)
+		}
+
+		if value != io.StatusPending {
+			task.Status = value
+		}
+	default:
+		if value != "" {
+			normalized, err := fields.Normalize(key, value, now)
+
+			if err != nil {
+				return err
+			}
+
+			value = normalized
+		}
+
+		if task.Custom == nil {
+			task.Custom = make(map[string]string)
+		}
+
+		task.Custom[key] = value
+	}
+
+	return nil
+}
+
+// startsDocument reports whether line i opens the header of a new document.
+func startsDocument(lines []string, i int) bool {
+	if strings.TrimSpace(lines[i]) != delimiter || i+1 >= len(lines) {
+		return false
+	}
+
+	key, _, found := strings.Cut(lines[i+1], ":")
+
+	return found && isKey(strings.ToLower(strings.TrimSpace(key)))
+}
+
+func isKey(key string) bool {
+	for _, known := range keys {
+		if key == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList reads a comma or space separated list, dropping a leading + or
+// @ so labels can be typed the way quick-add takes them.
+func splitList(value string) []string {
+	var list []string
+
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		item = strings.TrimLeft(item, "+@")
+
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+func formatDate(date time.Time) string {
+	if date.Hour() == 0 && date.Minute() == 0 {
+		return date.Format("2006-01-02")
+	}
+
+	return date.Format("2006-01-02T15:04")
+}
+
+// Diff compares two texts line by line and returns the lines only in old
+// prefixed with "- " and the lines only in new with "+ ", in order.
+func Diff(old string, new string) string {
+	a := strings.Split(strings.TrimRight(old, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(new, "\n"), "\n")
+
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			diff.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,71 @@
+package gitsync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+var pastTense = map[string]string{
+	"Add":     "added",
+	"Change":  "changed",
+	"Delete":  "deleted",
+	"Restore": "restored",
+	"Purge":   "purged",
+}
+
+// Describe summarises the difference between two databases as a commit
+// message: a subject line and one body line per touched task.
+func Describe(old io.Database, current io.Database) string {
+	before := index(old)
+	after := make(map[string]bool, len(current.Tasks))
+	counts := make(map[string]int)
+
+	var lines []string
+
+	note := func(verb string, task io.Task) {
+		counts[verb]++
+		lines = append(lines, fmt.Sprintf("%s task %d: %s", verb, task.ID, task.Title))
+	}
+
+	for _, task := range current.Tasks {
+		uid := task.StableUID()
+		after[uid] = true
+		previous, existed := before[uid]
+
+		switch {
+		case !existed:
+			note("Add", task)
+		case !previous.IsDeleted && task.IsDeleted:
+			note("Delete", task)
+		case previous.IsDeleted && !task.IsDeleted:
+			note("Restore", task)
+		case !sameTask(previous, task):
+			note("Change", task)
+		}
+	}
+
+	for _, task := range old.Tasks {
+		if !after[task.StableUID()] {
+			note("Purge", task)
+		}
+	}
+
+	switch len(lines) {
+	case 0:
+		return "Update tasks"
+	case 1:
+		return lines[0]
+	}
+
+	var parts []string
+
+	for _, verb := range []string{"Add", "Change", "Delete", "Restore", "Purge"} {
+		if counts[verb] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[verb], pastTense[verb]))
+		}
+	}
+
+	return "Update tasks: " + strings.Join(parts, ", ") + "\n\n" + strings.Join(lines, "\n")
+}
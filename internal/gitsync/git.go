@@ -0,0 +1,62 @@
+package gitsync
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type repo struct {
+	dir string
+}
+
+func (r repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// succeeds runs a git command whose exit status is the answer, such as
+// merge-base --is-ancestor.
+func (r repo) succeeds(args ...string) bool {
+	_, err := r.run(args...)
+
+	return err == nil
+}
+
+func (r repo) commit(message string) error {
+	_, err := r.run("commit", "--quiet", "-m", message)
+
+	return err
+}
+
+// ensureIdentity gives the sync repository a committer identity when the
+// user has none configured, so commits and merges never stop to ask.
+func (r repo) ensureIdentity() error {
+	if r.succeeds("config", "user.email") {
+		return nil
+	}
+
+	_, err := r.run("config", "user.name", "taski")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = r.run("config", "user.email", "taski@localhost")
+
+	return err
+}
@@ -0,0 +1,270 @@
+package gitsync
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/crdt"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// Merge performs a three-way merge of task records matched by UID. Fields
+// changed on only one side are taken from that side; when both sides changed
+// the same field differently the more recently modified task wins and the
+// clash is counted as a conflict. The CRDT state of both sides, the field
+// clocks and tombstones, is carried over so that a later taski merge still
+// orders every write and purge.
+func Merge(base io.Database, ours io.Database, theirs io.Database) (io.Database, int) {
+	baseTasks := index(base)
+	theirTasks := index(theirs)
+	seen := make(map[string]bool)
+	conflicts := 0
+	result := crdt.Replica(ours, theirs)
+
+	for _, our := range ours.Tasks {
+		uid := our.StableUID()
+		seen[uid] = true
+		old, inBase := baseTasks[uid]
+		their, inTheirs := theirTasks[uid]
+
+		switch {
+		case inTheirs:
+			// Without a base it was added on both sides with the same UID,
+			// and old is empty.
+			task, clashed := mergeTask(old, our, their)
+
+			if clashed {
+				conflicts++
+			}
+
+			crdt.Restamp(&result, our, their, &task)
+			result.Tasks = append(result.Tasks, task)
+		case inBase:
+			// Purged on their side; keep it only if we changed it since.
+			if !sameTask(old, our) {
+				result.Tasks = append(result.Tasks, our)
+			}
+		case !crdt.Buried(theirs.Tombstones, our):
+			result.Tasks = append(result.Tasks, our)
+		}
+	}
+
+	for _, their := range theirs.Tasks {
+		uid := their.StableUID()
+
+		if seen[uid] {
+			continue
+		}
+
+		old, inBase := baseTasks[uid]
+
+		// Purged on our side and untouched on theirs.
+		if inBase && sameTask(old, their) || !inBase && crdt.Buried(ours.Tombstones, their) {
+			continue
+		}
+
+		result.Tasks = append(result.Tasks, their)
+	}
+
+	for i := range result.Tasks {
+		result.Tasks[i].ID = i
+
+		if !result.Tasks[i].IsDeleted {
+			result.Size++
+		}
+	}
+
+	return result, conflicts
+}
+
+func index(db io.Database) map[string]io.Task {
+	tasks := make(map[string]io.Task, len(db.Tasks))
+
+	for _, task := range db.Tasks {
+		tasks[task.StableUID()] = task
+	}
+
+	return tasks
+}
+
+func mergeTask(base io.Task, ours io.Task, theirs io.Task) (io.Task, bool) {
+	result := ours
+	clashed := false
+	theirsNewer := theirs.Date.After(ours.Date)
+
+	// takeTheirs decides a single field from its three versions.
+	takeTheirs := func(b string, o string, t string) bool {
+		switch {
+		case o == t, t == b:
+			return false
+		case o == b:
+			return true
+		default:
+			clashed = true
+			return theirsNewer
+		}
+	}
+
+	if takeTheirs(base.Title, ours.Title, theirs.Title) {
+		result.Title = theirs.Title
+	}
+
+	if takeTheirs(base.Description, ours.Description, theirs.Description) {
+		result.Description = theirs.Description
+	}
+
+	if takeTheirs(timeKey(base.Due), timeKey(ours.Due), timeKey(theirs.Due)) {
+		result.Due = theirs.Due
+	}
+
+	if takeTheirs(base.Priority, ours.Priority, theirs.Priority) {
+		result.Priority = theirs.Priority
+	}
+
+	if takeTheirs(base.Recurrence, ours.Recurrence, theirs.Recurrence) {
+		result.Recurrence = theirs.Recurrence
+	}
+
+	if takeTheirs(strings.Join(base.Remind, " "), strings.Join(ours.Remind, " "), strings.Join(theirs.Remind, " ")) {
+		result.Remind = theirs.Remind
+	}
+
+	if takeTheirs(strings.Join(base.Tags, " "), strings.Join(ours.Tags, " "), strings.Join(theirs.Tags, " ")) {
+		result.Tags = theirs.Tags
+	}
+
+	if takeTheirs(strings.Join(base.Contexts, " "), strings.Join(ours.Contexts, " "), strings.Join(theirs.Contexts, " ")) {
+		result.Contexts = theirs.Contexts
+	}
+
+	// Custom fields are merged one by one, so two teams filling in
+	// different fields of a task do not clash.
+	for name := range keys(base.Custom, ours.Custom, theirs.Custom) {
+		if takeTheirs(base.Custom[name], ours.Custom[name], theirs.Custom[name]) {
+			result.Custom = setKey(result.Custom, name, theirs.Custom[name])
+		}
+	}
+
+	// Notes are merged one by one too, by the time they were written, so
+	// notes added on both sides are all kept.
+	baseNotes, ourNotes, theirNotes := noteTexts(base.Notes), noteTexts(ours.Notes), noteTexts(theirs.Notes)
+	notes := ourNotes
+
+	for key := range keys(baseNotes, ourNotes, theirNotes) {
+		if takeTheirs(baseNotes[key], ourNotes[key], theirNotes[key]) {
+			notes = setKey(notes, key, theirNotes[key])
+		}
+	}
+
+	if !maps.Equal(notes, ourNotes) {
+		result.Notes = fromNoteTexts(notes)
+	}
+
+	if takeTheirs(base.Status, ours.Status, theirs.Status) {
+		result.Status = theirs.Status
+	}
+
+	if takeTheirs(strconv.FormatBool(base.IsDeleted), strconv.FormatBool(ours.IsDeleted), strconv.FormatBool(theirs.IsDeleted)) {
+		result.IsDeleted = theirs.IsDeleted
+		result.DeletedAt = theirs.DeletedAt
+	}
+
+	if theirsNewer {
+		result.Date = theirs.Date
+	}
+
+	return result, clashed
+}
+
+// keys returns the union of the keys of maps.
+func keys(all ...map[string]string) map[string]bool {
+	union := make(map[string]bool)
+
+	for _, m := range all {
+		for key := range m {
+			union[key] = true
+		}
+	}
+
+	return union
+}
+
+// setKey sets or, for an empty value, removes one key on a copy of m,
+// which may still be shared with another version of the task.
+func setKey(m map[string]string, key string, value string) map[string]string {
+	result := maps.Clone(m)
+
+	if result == nil {
+		result = make(map[string]string)
+	}
+
+	if value == "" {
+		delete(result, key)
+	} else {
+		result[key] = value
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// noteTexts maps the notes of a task by the time they were written.
+func noteTexts(notes []io.Note) map[string]string {
+	texts := make(map[string]string, len(notes))
+
+	for _, note := range notes {
+		texts[note.Key()] = note.Text
+	}
+
+	return texts
+}
+
+// fromNoteTexts turns the result of noteTexts back into notes, oldest
+// first.
+func fromNoteTexts(texts map[string]string) []io.Note {
+	var notes []io.Note
+
+	for key, text := range texts {
+		written, err := time.Parse(time.RFC3339, key)
+
+		if err == nil {
+			notes = append(notes, io.Note{Time: written.Local(), Text: text})
+		}
+	}
+
+	slices.SortFunc(notes, func(a, b io.Note) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return notes
+}
+
+func timeKey(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func sameTask(a io.Task, b io.Task) bool {
+	return a.Title == b.Title &&
+		a.Description == b.Description &&
+		timeKey(a.Due) == timeKey(b.Due) &&
+		a.Priority == b.Priority &&
+		a.Recurrence == b.Recurrence &&
+		slices.Equal(a.Remind, b.Remind) &&
+		slices.Equal(a.Tags, b.Tags) &&
+		slices.Equal(a.Contexts, b.Contexts) &&
+		maps.Equal(a.Custom, b.Custom) &&
+		slices.EqualFunc(a.Notes, b.Notes, io.Note.Equal) &&
+		a.Status == b.Status &&
+		a.IsDeleted == b.IsDeleted &&
+		a.Date.Equal(b.Date)
+}
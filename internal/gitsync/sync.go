@@ -0,0 +1,330 @@
+package gitsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// DataFile is the name of the database inside the sync repository.
+const DataFile = "tasks.json"
+
+const trackingPrefix = "refs/remotes/taski/"
+
+type Result struct {
+	Committed   bool
+	FastForward bool
+	Merged      bool
+	Conflicts   int
+}
+
+// Commit records the current database in the sync repository, describing
+// what changed since the previous commit. It reports whether a commit was
+// made.
+func Commit(settings config.Git, fileName string) (bool, error) {
+	r, err := open(settings)
+
+	if err != nil {
+		return false, err
+	}
+
+	previous, err := readDatabase(filepath.Join(r.dir, DataFile))
+
+	if err != nil {
+		return false, err
+	}
+
+	current, err := io.ReadAll(fileName)
+
+	if err != nil {
+		return false, fmt.Errorf("reading tasks: %w", err)
+	}
+
+	err = writeDatabase(filepath.Join(r.dir, DataFile), current)
+
+	if err != nil {
+		return false, err
+	}
+
+	_, err = r.run("add", DataFile)
+
+	if err != nil {
+		return false, err
+	}
+
+	if r.succeeds("diff", "--cached", "--quiet") && r.succeeds("rev-parse", "--verify", "HEAD") {
+		return false, nil
+	}
+
+	err = r.commit(Describe(previous, current))
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Sync commits local changes, merges the remote branch semantically and
+// pushes the result, then loads the merged database back.
+func Sync(settings config.Git, fileName string) (Result, error) {
+	var result Result
+
+	if settings.Remote == "" {
+		return result, errors.New("no git remote configured")
+	}
+
+	committed, err := Commit(settings, fileName)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Committed = committed
+	r := repo{dir: settings.Repo}
+
+	// The committed snapshot is the base of the final merge with the tasks
+	// changed while the network round trip ran.
+	snapshot, err := readDatabase(filepath.Join(r.dir, DataFile))
+
+	if err != nil {
+		return result, err
+	}
+
+	branch := branchOf(settings)
+	tracking := trackingPrefix + branch
+
+	_, err = r.run("fetch", "--quiet", settings.Remote, "+refs/heads/"+branch+":"+tracking)
+
+	if err != nil && !strings.Contains(err.Error(), "couldn't find remote ref") {
+		return result, err
+	}
+
+	if err == nil {
+		err = integrate(r, tracking, settings.Remote, &result)
+
+		if err != nil {
+			return result, err
+		}
+	}
+
+	_, err = r.run("push", "--quiet", settings.Remote, "HEAD:refs/heads/"+branch)
+
+	if err != nil {
+		return result, err
+	}
+
+	merged, err := readDatabase(filepath.Join(r.dir, DataFile))
+
+	if err != nil {
+		return result, err
+	}
+
+	err = io.Rewrite(fileName, func(local io.Database) io.Database {
+		db, _ := Merge(snapshot, local, merged)
+		return db
+	})
+
+	if err != nil {
+		return result, fmt.Errorf("loading merged tasks: %w", err)
+	}
+
+	return result, nil
+}
+
+func integrate(r repo, tracking string, remote string, result *Result) error {
+	if r.succeeds("merge-base", "--is-ancestor", tracking, "HEAD") {
+		return nil
+	}
+
+	if r.succeeds("merge-base", "--is-ancestor", "HEAD", tracking) {
+		_, err := r.run("merge", "--quiet", "--ff-only", tracking)
+
+		if err != nil {
+			return err
+		}
+
+		result.FastForward = true
+		return nil
+	}
+
+	var base io.Database
+	mergeBase, err := r.run("merge-base", "HEAD", tracking)
+
+	if err == nil {
+		base, err = showDatabase(r, mergeBase)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	ours, err := showDatabase(r, "HEAD")
+
+	if err != nil {
+		return err
+	}
+
+	theirs, err := showDatabase(r, tracking)
+
+	if err != nil {
+		return err
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+
+	// Record the merge with our tree first so git never attempts a textual
+	// merge of the JSON, then replace the tree with the semantic result.
+	_, err = r.run("merge", "--quiet", "--no-commit", "--allow-unrelated-histories", "-s", "ours", tracking)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeDatabase(filepath.Join(r.dir, DataFile), merged)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = r.run("add", DataFile)
+
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Merge tasks from %s", remote)
+
+	if conflicts > 0 {
+		message += fmt.Sprintf("\n\nResolved %d conflicting task(s) in favour of the most recent change.", conflicts)
+	}
+
+	err = r.commit(message)
+
+	if err != nil {
+		return err
+	}
+
+	result.Merged = true
+	result.Conflicts = conflicts
+
+	return nil
+}
+
+func open(settings config.Git) (repo, error) {
+	if settings.Repo == "" {
+		return repo{}, errors.New("no git repository configured")
+	}
+
+	r := repo{dir: settings.Repo}
+
+	_, err := os.Stat(filepath.Join(settings.Repo, ".git"))
+
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.MkdirAll(settings.Repo, 0755)
+
+		if err != nil {
+			return repo{}, fmt.Errorf("creating repository: %w", err)
+		}
+
+		_, err = r.run("init", "--quiet")
+
+		if err != nil {
+			return repo{}, err
+		}
+	} else if err != nil {
+		return repo{}, fmt.Errorf("opening repository: %w", err)
+	}
+
+	if !r.succeeds("rev-parse", "--verify", "HEAD") {
+		_, err = r.run("symbolic-ref", "HEAD", "refs/heads/"+branchOf(settings))
+
+		if err != nil {
+			return repo{}, err
+		}
+	}
+
+	err = r.ensureIdentity()
+
+	if err != nil {
+		return repo{}, err
+	}
+
+	return r, nil
+}
+
+func branchOf(settings config.Git) string {
+	if settings.Branch == "" {
+		return "main"
+	}
+
+	return settings.Branch
+}
+
+func showDatabase(r repo, rev string) (io.Database, error) {
+	content, err := r.run("show", rev+":"+DataFile)
+
+	if err != nil {
+		// The database did not exist yet at that revision.
+		return io.Database{}, nil
+	}
+
+	var db io.Database
+
+	err = json.Unmarshal([]byte(content), &db)
+
+	if err != nil {
+		return io.Database{}, fmt.Errorf("decoding %s at %s: %w", DataFile, rev, err)
+	}
+
+	return db, nil
+}
+
+func readDatabase(fileName string) (io.Database, error) {
+	var db io.Database
+
+	content, err := os.ReadFile(fileName)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return db, nil
+	}
+
+	if err != nil {
+		return db, fmt.Errorf("reading %s: %w", fileName, err)
+	}
+
+	err = json.Unmarshal(content, &db)
+
+	if err != nil {
+		return db, fmt.Errorf("decoding %s: %w", fileName, err)
+	}
+
+	return db, nil
+}
+
+func writeDatabase(fileName string, db io.Database) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "\t")
+
+	err := encoder.Encode(db)
+
+	if err != nil {
+		return fmt.Errorf("encoding tasks: %w", err)
+	}
+
+	err = os.WriteFile(fileName, buf.Bytes(), 0644)
+
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", fileName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package hlc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is a hybrid logical clock reading: wall time in nanoseconds, a
+// logical counter to order events within the same nanosecond, and the
+// replica that issued it to break remaining ties deterministically.
+type Timestamp struct {
+	Wall    int64
+	Logical uint32
+	Node    string
+}
+
+var now = func() int64 {
+	return time.Now().UnixNano()
+}
+
+// Next issues the timestamp for a local event after last.
+func Next(last Timestamp, node string) Timestamp {
+	wall := now()
+
+	if wall > last.Wall {
+		return Timestamp{Wall: wall, Node: node}
+	}
+
+	return Timestamp{Wall: last.Wall, Logical: last.Logical + 1, Node: node}
+}
+
+// Receive advances the local clock past a timestamp seen from another
+// replica, so later local events order after everything merged in.
+func Receive(local Timestamp, remote Timestamp, node string) Timestamp {
+	wall := now()
+
+	switch {
+	case wall > local.Wall && wall > remote.Wall:
+		return Timestamp{Wall: wall, Node: node}
+	case local.Wall == remote.Wall:
+		return Timestamp{Wall: local.Wall, Logical: max(local.Logical, remote.Logical) + 1, Node: node}
+	case local.Wall > remote.Wall:
+		return Timestamp{Wall: local.Wall, Logical: local.Logical + 1, Node: node}
+	default:
+		return Timestamp{Wall: remote.Wall, Logical: remote.Logical + 1, Node: node}
+	}
+}
+
+func (t Timestamp) IsZero() bool {
+	return t.Wall == 0 && t.Logical == 0 && t.Node == ""
+}
+
+// Compare returns -1, 0 or +1 depending on whether t happened before, at
+// the same time as, or after other.
+func (t Timestamp) Compare(other Timestamp) int {
+	switch {
+	case t.Wall != other.Wall:
+		return compare(t.Wall, other.Wall)
+	case t.Logical != other.Logical:
+		return compare(t.Logical, other.Logical)
+	default:
+		return strings.Compare(t.Node, other.Node)
+	}
+}
+
+func (t Timestamp) After(other Timestamp) bool {
+	return t.Compare(other) > 0
+}
+
+func Max(a Timestamp, b Timestamp) Timestamp {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
+
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%d.%d@%s", t.Wall, t.Logical, t.Node)
+}
+
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	stamp, node, found := strings.Cut(string(text), "@")
+
+	if !found {
+		return fmt.Errorf("invalid timestamp %q", text)
+	}
+
+	wall, logical, found := strings.Cut(stamp, ".")
+
+	if !found {
+		return fmt.Errorf("invalid timestamp %q", text)
+	}
+
+	parsedWall, err := strconv.ParseInt(wall, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", text, err)
+	}
+
+	parsedLogical, err := strconv.ParseUint(logical, 10, 32)
+
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", text, err)
+	}
+
+	*t = Timestamp{Wall: parsedWall, Logical: uint32(parsedLogical), Node: node}
+
+	return nil
+}
+
+func compare[T int64 | uint32](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,232 @@
+// Package hooks runs the executables in the hooks directory before a change
+// to a task is stored, like git hooks. A hook gets the task as JSON on
+// stdin; it accepts the change by exiting with 0, optionally printing the
+// task with fields changed on stdout, and rejects it with any other exit
+// code, printing the reason on stderr.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// Hook names, one per kind of change.
+const (
+	OnAdd     = "on-add"
+	OnModify  = "on-modify"
+	OnDelete  = "on-delete"
+	OnRestore = "on-restore"
+)
+
+// DefaultTimeout is how long a hook may run unless hooks.timeout is set.
+const DefaultTimeout = 5 * time.Second
+
+// names maps the event types that hooks can stop to their hook.
+var names = map[string]string{
+	io.EventAdded:    OnAdd,
+	io.EventChanged:  OnModify,
+	io.EventDeleted:  OnDelete,
+	io.EventRestored: OnRestore,
+}
+
+// Error is a change rejected by a hook. It matches io.ErrInvalid.
+type Error struct {
+	Hook    string
+	Task    io.Task
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v hook rejected task %d: %v", e.Hook, e.Task.ID, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return io.ErrInvalid
+}
+
+// Dir returns the hooks directory of a database: hooks.dir from its config,
+// relative to the config file, or taski/hooks in the user config directory.
+func Dir(fileName string, cfg config.Hooks) (string, error) {
+	if cfg.Dir != "" {
+		if filepath.IsAbs(cfg.Dir) {
+			return cfg.Dir, nil
+		}
+
+		return filepath.Join(filepath.Dir(config.PathFor(fileName)), cfg.Dir), nil
+	}
+
+	dir, err := os.UserConfigDir()
+
+	if err != nil {
+		return "", fmt.Errorf("locating hooks: %w", err)
+	}
+
+	return filepath.Join(dir, "taski", "hooks"), nil
+}
+
+// Find returns the path of the executable hook called name in dir, or ""
+// when there is none. Files that are not executable are skipped, so a
+// hook can be turned off with chmod -x.
+func Find(dir string, name string) string {
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+
+	if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return ""
+	}
+
+	return path
+}
+
+// Check runs the hook for event, if there is one, and returns the task to
+// store. It is meant for io.Guard.
+func Check(fileName string, event io.Event) (io.Task, error) {
+	name, found := names[event.Type]
+
+	if !found {
+		return event.Task, nil
+	}
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return io.Task{}, err
+	}
+
+	dir, err := Dir(fileName, cfg.Hooks)
+
+	if err != nil {
+		return io.Task{}, err
+	}
+
+	path := Find(dir, name)
+
+	if path == "" {
+		return event.Task, nil
+	}
+
+	timeout := DefaultTimeout
+
+	if cfg.Hooks.Timeout != "" {
+		timeout, err = time.ParseDuration(cfg.Hooks.Timeout)
+
+		if err != nil || timeout <= 0 {
+			return io.Task{}, fmt.Errorf("invalid hooks.timeout %q in %v", cfg.Hooks.Timeout, config.PathFor(fileName))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return Run(ctx, path, fileName, event)
+}
+
+// Run runs the hook at path for event. The hook also finds the event in the
+// environment: TASKI_HOOK is its name, TASKI_EVENT the event type,
+// TASKI_FIELDS the changed fields of a task.changed event and TASKI_DB the
+// database.
+func Run(ctx context.Context, path string, fileName string, event io.Event) (io.Task, error) {
+	name := filepath.Base(path)
+
+	input, err := json.Marshal(event.Task)
+
+	if err != nil {
+		return io.Task{}, fmt.Errorf("encoding task for %v hook: %w", name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"TASKI_HOOK="+name,
+		"TASKI_EVENT="+event.Type,
+		"TASKI_FIELDS="+strings.Join(event.Fields, ","),
+		"TASKI_DB="+fileName,
+	)
+	// Do not wait for children of the hook that keep its output open.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+
+	if ctx.Err() != nil {
+		return io.Task{}, fmt.Errorf("%v hook did not finish within the timeout", name)
+	}
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		message := strings.TrimSpace(stderr.String())
+
+		if message == "" {
+			message = exitErr.Error()
+		}
+
+		return io.Task{}, &Error{Hook: name, Task: event.Task, Message: message}
+	}
+
+	if err != nil {
+		return io.Task{}, fmt.Errorf("running %v hook: %w", name, err)
+	}
+
+	if stderr.Len() > 0 {
+		fmt.Fprint(os.Stderr, stderr.String())
+	}
+
+	return decode(name, event.Task, stdout.Bytes())
+}
+
+// decode applies the fields a hook printed to task. The fields are decoded
+// into a copy, so the stored task only changes once the guard replaces it,
+// and printed custom fields replace the old ones instead of adding to them.
+func decode(name string, task io.Task, output []byte) (io.Task, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return task, nil
+	}
+
+	var printed struct {
+		Custom json.RawMessage `json:"custom"`
+	}
+
+	err := json.Unmarshal(output, &printed)
+
+	if err != nil {
+		return io.Task{}, fmt.Errorf("decoding the output of %v hook: %w", name, err)
+	}
+
+	task = task.Clone()
+
+	if printed.Custom != nil {
+		task.Custom = nil
+	}
+
+	err = json.Unmarshal(output, &task)
+
+	if err != nil {
+		return io.Task{}, fmt.Errorf("decoding the output of %v hook: %w", name, err)
+	}
+
+	switch {
+	case strings.TrimSpace(task.Title) == "":
+		return io.Task{}, fmt.Errorf("%v hook left task %d without a title: %w", name, task.ID, io.ErrInvalid)
+	case !io.ValidPriority(task.Priority):
+		return io.Task{}, fmt.Errorf("%v hook set invalid priority %q: %w", name, task.Priority, io.ErrInvalid)
+	case task.Status != "" && !io.ValidStatus(task.Status):
+		return io.Task{}, fmt.Errorf("%v hook set invalid status %q: %w", name, task.Status, io.ErrInvalid)
+	}
+
+	return task, nil
+}
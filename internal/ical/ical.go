@@ -0,0 +1,462 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	taskio "github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/remind"
+)
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	dateLayout     = "20060102"
+	floatingLayout = "20060102T150405"
+	lineLimit      = 75
+)
+
+// Encode writes the tasks as an RFC 5545 calendar of VTODO components.
+func Encode(w io.Writer, tasks []taskio.Task) error {
+	now := time.Now().UTC().Format(dateTimeLayout)
+	writer := bufio.NewWriter(w)
+
+	writeLine(writer, "BEGIN:VCALENDAR")
+	writeLine(writer, "VERSION:2.0")
+	writeLine(writer, "PRODID:-//tristnaja//taski//EN")
+
+	for _, task := range tasks {
+		writeLine(writer, "BEGIN:VTODO")
+		writeLine(writer, "UID:"+task.StableUID())
+		writeLine(writer, "DTSTAMP:"+now)
+
+		if !task.Date.IsZero() {
+			writeLine(writer, "CREATED:"+task.Date.UTC().Format(dateTimeLayout))
+			writeLine(writer, "LAST-MODIFIED:"+task.Date.UTC().Format(dateTimeLayout))
+		}
+
+		writeLine(writer, "SUMMARY:"+escape(task.Title))
+
+		if task.Description != "" {
+			writeLine(writer, "DESCRIPTION:"+escape(task.Description))
+		}
+
+		if task.Due != nil {
+			writeLine(writer, "DUE:"+task.Due.UTC().Format(dateTimeLayout))
+		}
+
+		if priority := toPriority(task.Priority); priority != 0 {
+			writeLine(writer, "PRIORITY:"+strconv.Itoa(priority))
+		}
+
+		if task.Recurrence != "" {
+			writeLine(writer, "RRULE:"+task.Recurrence)
+		}
+
+		if categories := toCategories(task); len(categories) > 0 {
+			writeLine(writer, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+
+		if task.IsDeleted {
+			writeLine(writer, "STATUS:CANCELLED")
+		} else if task.IsDone() {
+			writeLine(writer, "STATUS:COMPLETED")
+		} else if task.Status != "" {
+			writeLine(writer, "STATUS:IN-PROCESS")
+			writeLine(writer, "X-TASKI-STATUS:"+task.Status)
+		} else {
+			writeLine(writer, "STATUS:NEEDS-ACTION")
+		}
+
+		// Custom fields have no standard property; X-TASKI-FIELD keeps them
+		// for taski on the other side.
+		for _, name := range slices.Sorted(maps.Keys(task.Custom)) {
+			writeLine(writer, "X-TASKI-FIELD;NAME="+name+":"+escape(task.Custom[name]))
+		}
+
+		for _, note := range task.Notes {
+			writeLine(writer, "X-TASKI-NOTE;TIME="+note.Time.UTC().Format(dateTimeLayout)+":"+escape(note.Text))
+		}
+
+		for _, setting := range task.Remind {
+			offset, err := remind.ParseOffset(setting)
+
+			if err != nil {
+				continue
+			}
+
+			writeLine(writer, "BEGIN:VALARM")
+			writeLine(writer, "ACTION:DISPLAY")
+			writeLine(writer, "DESCRIPTION:"+escape(task.Title))
+			writeLine(writer, "TRIGGER;RELATED=END:"+toTrigger(offset))
+			writeLine(writer, "END:VALARM")
+		}
+
+		writeLine(writer, "END:VTODO")
+	}
+
+	writeLine(writer, "END:VCALENDAR")
+
+	err := writer.Flush()
+
+	if err != nil {
+		return fmt.Errorf("writing calendar: %w", err)
+	}
+
+	return nil
+}
+
+// Decode reads every VTODO component of a calendar back into tasks. Other
+// components such as VEVENT are skipped.
+func Decode(r io.Reader) ([]taskio.Task, error) {
+	var tasks []taskio.Task
+	var current *taskio.Task
+	alarm := false
+
+	lines, err := unfold(r)
+
+	if err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	for number, line := range lines {
+		name, params, value, err := parseLine(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VTODO":
+			current = &taskio.Task{}
+		case name == "END" && value == "VTODO":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VTODO without BEGIN", number+1)
+			}
+
+			tasks = append(tasks, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "BEGIN" && value == "VALARM":
+			alarm = true
+		case name == "END" && value == "VALARM":
+			alarm = false
+		case alarm:
+			// Only triggers relative to the due date map onto taski reminders.
+			if name == "TRIGGER" && params["VALUE"] != "DATE-TIME" {
+				if offset, ok := fromTrigger(value); ok {
+					current.Remind = append(current.Remind, remind.FormatOffset(offset))
+				}
+			}
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Title = unescape(value)
+		case name == "DESCRIPTION":
+			current.Description = unescape(value)
+		case name == "CREATED" && current.Date.IsZero(), name == "LAST-MODIFIED":
+			// taski bumps Date on every change, so LAST-MODIFIED wins over CREATED.
+			date, err := parseTime(value, params)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+
+			current.Date = date
+		case name == "DUE":
+			due, err := parseTime(value, params)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+
+			current.Due = &due
+		case name == "PRIORITY":
+			priority, err := strconv.Atoi(value)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid priority %q", number+1, value)
+			}
+
+			current.Priority = fromPriority(priority)
+		case name == "RRULE":
+			current.Recurrence = value
+		case name == "CATEGORIES":
+			fromCategories(current, value)
+		case name == "STATUS":
+			current.IsDeleted = value == "CANCELLED"
+
+			switch value {
+			case "COMPLETED":
+				current.Status = taskio.StatusDone
+			case "IN-PROCESS":
+				if current.Status == "" {
+					current.Status = taskio.StatusDoing
+				}
+			}
+		case name == "X-TASKI-FIELD" && params["NAME"] != "":
+			if current.Custom == nil {
+				current.Custom = make(map[string]string)
+			}
+
+			current.Custom[strings.ToLower(params["NAME"])] = unescape(value)
+		case name == "X-TASKI-NOTE":
+			written, err := parseTime(params["TIME"], nil)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+
+			current.Notes = append(current.Notes, taskio.Note{Time: written, Text: unescape(value)})
+		case name == "X-TASKI-STATUS":
+			if taskio.ValidStatus(value) {
+				current.Status = value
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated VTODO")
+	}
+
+	return tasks, nil
+}
+
+func writeLine(w *bufio.Writer, line string) {
+	// Content lines are folded at 75 octets without splitting a UTF-8 sequence.
+	for len(line) > lineLimit {
+		cut := lineLimit
+
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+
+	w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	err := scanner.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func parseLine(line string) (name string, params map[string]string, value string, err error) {
+	head, value, found := strings.Cut(line, ":")
+
+	if !found {
+		return "", nil, "", fmt.Errorf("malformed content line %q", line)
+	}
+
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	params = make(map[string]string)
+
+	for _, part := range parts[1:] {
+		key, val, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return name, params, value, nil
+}
+
+func parseTime(value string, params map[string]string) (time.Time, error) {
+	if params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		return time.ParseInLocation(dateLayout, value, time.Local)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(dateTimeLayout, value)
+	}
+
+	location := time.Local
+
+	if tzid := params["TZID"]; tzid != "" {
+		loaded, err := time.LoadLocation(tzid)
+
+		if err == nil {
+			location = loaded
+		}
+	}
+
+	return time.ParseInLocation(floatingLayout, value, location)
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
+
+// toCategories encodes tags as plain categories and contexts as categories
+// starting with "@", which is how they come back in fromCategories.
+func toCategories(task taskio.Task) []string {
+	var categories []string
+
+	for _, tag := range task.Tags {
+		categories = append(categories, escape(tag))
+	}
+
+	for _, context := range task.Contexts {
+		categories = append(categories, escape("@"+context))
+	}
+
+	return categories
+}
+
+func fromCategories(task *taskio.Task, value string) {
+	var category strings.Builder
+	flush := func() {
+		name := strings.TrimSpace(unescape(category.String()))
+		category.Reset()
+
+		switch {
+		case len(name) > 1 && name[0] == '@':
+			task.Contexts = append(task.Contexts, name[1:])
+		case name != "":
+			task.Tags = append(task.Tags, name)
+		}
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			category.WriteByte(value[i])
+			category.WriteByte(value[i+1])
+			i++
+		case value[i] == ',':
+			flush()
+		default:
+			category.WriteByte(value[i])
+		}
+	}
+
+	flush()
+}
+
+func toPriority(priority string) int {
+	switch priority {
+	case taskio.PriorityHigh:
+		return 1
+	case taskio.PriorityMedium:
+		return 5
+	case taskio.PriorityLow:
+		return 9
+	default:
+		return 0
+	}
+}
+
+func fromPriority(priority int) string {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return taskio.PriorityHigh
+	case priority == 5:
+		return taskio.PriorityMedium
+	case priority >= 6 && priority <= 9:
+		return taskio.PriorityLow
+	default:
+		return ""
+	}
+}
+
+// toTrigger writes how long before the due date an alarm goes off as an
+// RFC 5545 duration, such as -PT30M or -P1DT2H.
+func toTrigger(offset time.Duration) string {
+	if offset <= 0 {
+		return "PT0S"
+	}
+
+	minutes := int(offset.Round(time.Minute) / time.Minute)
+	days, hours, minutes := minutes/(24*60), minutes/60%24, minutes%60
+	trigger := "-P"
+
+	if days > 0 {
+		trigger += strconv.Itoa(days) + "D"
+	}
+
+	if hours > 0 || minutes > 0 {
+		trigger += "T"
+	}
+
+	if hours > 0 {
+		trigger += strconv.Itoa(hours) + "H"
+	}
+
+	if minutes > 0 {
+		trigger += strconv.Itoa(minutes) + "M"
+	}
+
+	return trigger
+}
+
+// fromTrigger reads an RFC 5545 duration back into an offset before the
+// due date. Triggers after the due date are not supported.
+func fromTrigger(value string) (time.Duration, bool) {
+	before := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+
+	if !strings.HasPrefix(value, "P") {
+		return 0, false
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	var offset time.Duration
+	number := 0
+
+	for i := 1; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == 'T':
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+		case units[c] != 0:
+			offset += time.Duration(number) * units[c]
+			number = 0
+		default:
+			return 0, false
+		}
+	}
+
+	if !before && offset != 0 {
+		return 0, false
+	}
+
+	return offset, true
+}
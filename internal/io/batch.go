@@ -0,0 +1,197 @@
+package io
+
+import (
+	"fmt"
+	"time"
+)
+
+// A task is pending until it is done. Other statuses, such as doing, are
+// the in-between columns of a board.
+const (
+	StatusPending = "pending"
+	StatusDoing   = "doing"
+	StatusDone    = "done"
+)
+
+// Transact runs fn on an in-memory copy of the database and writes the
+// result back in a single write. When fn returns an error nothing is
+// written, so a batch of changes is applied completely or not at all.
+func Transact(fileName string, fn func(db *Database) error) error {
+	unlock, err := lock(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	db, err := load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	err = fn(&db)
+
+	if err != nil {
+		return err
+	}
+
+	err = check(fileName, &db)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeJSON(fileName, db)
+
+	if err != nil {
+		return fmt.Errorf("writing into file: %w", err)
+	}
+
+	return nil
+}
+
+// LockFile takes the lock the database uses on another file, such as a
+// state file kept next to it, and returns the function releasing it.
+func LockFile(fileName string) (func(), error) {
+	return lock(fileName)
+}
+
+// Get returns the task at index.
+func (db *Database) Get(index int) (Task, error) {
+	if index < 0 || index >= len(db.Tasks) {
+		return Task{}, &IndexError{Index: index}
+	}
+
+	return db.Tasks[index], nil
+}
+
+// Add appends a task, assigning its ID and UID, and returns it.
+func (db *Database) Add(task Task) Task {
+	task.ID = len(db.Tasks)
+
+	if task.UID == "" {
+		task.UID = NewUID()
+	}
+
+	db.stamp(&task, newFields(task)...)
+	db.Tasks = append(db.Tasks, task)
+	db.Size++
+
+	return task
+}
+
+// Update applies fn to the task at index. The task is only replaced when fn
+// succeeds; changed fields are stamped and the modification date updated.
+func (db *Database) Update(index int, fn func(task *Task) error) error {
+	old, err := db.Get(index)
+
+	if err != nil {
+		return err
+	}
+
+	task := old
+	err = fn(&task)
+
+	if err != nil {
+		return err
+	}
+
+	fields := clockFields(old, task)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	task.Date = time.Now()
+	db.stamp(&task, fields...)
+	db.Tasks[index] = task
+
+	return nil
+}
+
+// Delete moves the task at index to trash. Deleting a task already in
+// trash changes nothing.
+func (db *Database) Delete(index int) error {
+	task, err := db.Get(index)
+
+	if err != nil {
+		return err
+	}
+
+	if task.IsDeleted {
+		return nil
+	}
+
+	now := time.Now()
+	db.Tasks[index].IsDeleted = true
+	db.Tasks[index].DeletedAt = &now
+	db.stamp(&db.Tasks[index], FieldDeleted)
+	db.Size--
+
+	return nil
+}
+
+// Restore takes the task at index out of trash.
+func (db *Database) Restore(index int) error {
+	task, err := db.Get(index)
+
+	if err != nil {
+		return err
+	}
+
+	if !task.IsDeleted {
+		return nil
+	}
+
+	db.Tasks[index].IsDeleted = false
+	db.Tasks[index].DeletedAt = nil
+	db.stamp(&db.Tasks[index], FieldDeleted)
+	db.Size++
+
+	return nil
+}
+
+// SetStatus sets the status of the task at index, e.g. pending or done.
+func (db *Database) SetStatus(index int, status string) error {
+	if !ValidStatus(status) {
+		return fmt.Errorf("%w: status %q, expected one lowercase word such as pending, doing or done", ErrInvalid, status)
+	}
+
+	return db.Update(index, func(task *Task) error {
+		if status == StatusPending {
+			status = ""
+		}
+
+		task.Status = status
+
+		return nil
+	})
+}
+
+// IsDone reports whether the task has been completed.
+func (t Task) IsDone() bool {
+	return t.Status == StatusDone
+}
+
+// StatusName is the status of the task, with pending for the empty one.
+func (t Task) StatusName() string {
+	if t.Status == "" {
+		return StatusPending
+	}
+
+	return t.Status
+}
+
+// ValidStatus accepts the empty status and any single lowercase word made
+// of letters, digits, - and _.
+func ValidStatus(status string) bool {
+	for _, r := range status {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,175 @@
+package io
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/tristnaja/taski/internal/hlc"
+)
+
+// Field names used as keys of Task.Clocks. Each replicated field carries
+// the hybrid logical clock of its last write so that two copies of the
+// database can be merged field by field.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldDue         = "due"
+	FieldPriority    = "priority"
+	FieldRecurrence  = "recurrence"
+	FieldRemind      = "remind"
+	FieldTags        = "tags"
+	FieldContexts    = "contexts"
+	FieldCustom      = "custom"
+	FieldNotes       = "notes"
+	FieldStatus      = "status"
+	FieldDeleted     = "deleted"
+)
+
+var Fields = []string{FieldTitle, FieldDescription, FieldDue, FieldPriority, FieldRecurrence, FieldRemind, FieldTags, FieldContexts, FieldCustom, FieldNotes, FieldStatus, FieldDeleted}
+
+// NoteField returns the key of Task.Clocks that orders the writes to the
+// note with the given Key, so notes merge one by one. It outlives the note
+// as its tombstone, which keeps a merge from bringing a removed note back.
+func NoteField(key string) string {
+	return FieldNotes + "/" + key
+}
+
+// stamp records a local write of the given fields of task.
+func (db *Database) stamp(task *Task, fields ...string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if db.Node == "" {
+		db.Node = NewUID()[:8]
+	}
+
+	db.Clock = hlc.Next(db.Clock, db.Node)
+
+	if task.Clocks == nil {
+		task.Clocks = make(map[string]hlc.Timestamp)
+	}
+
+	for _, field := range fields {
+		task.Clocks[field] = db.Clock
+	}
+}
+
+// bury records that a task was purged from the database for good, so a
+// merge with an older copy does not bring it back.
+func (db *Database) bury(task Task) {
+	if db.Node == "" {
+		db.Node = NewUID()[:8]
+	}
+
+	db.Clock = hlc.Next(db.Clock, db.Node)
+
+	if db.Tombstones == nil {
+		db.Tombstones = make(map[string]hlc.Timestamp)
+	}
+
+	db.Tombstones[task.StableUID()] = db.Clock
+}
+
+// newFields lists the keys of Task.Clocks stamped for a task that is new to
+// the database.
+func newFields(task Task) []string {
+	return append(slices.Clone(Fields), noteFields(nil, task.Notes)...)
+}
+
+// clockFields lists the keys of Task.Clocks that a change from old to
+// current writes: the changed fields and every note added, edited or
+// removed.
+func clockFields(old Task, current Task) []string {
+	return append(ChangedFields(old, current), noteFields(old.Notes, current.Notes)...)
+}
+
+func noteFields(old []Note, current []Note) []string {
+	var fields []string
+	before := make(map[string]string, len(old))
+
+	for _, note := range old {
+		before[note.Key()] = note.Text
+	}
+
+	for _, note := range current {
+		text, found := before[note.Key()]
+
+		if !found || text != note.Text {
+			fields = append(fields, NoteField(note.Key()))
+		}
+
+		delete(before, note.Key())
+	}
+
+	for key := range before {
+		fields = append(fields, NoteField(key))
+	}
+
+	return fields
+}
+
+// ChangedFields lists the replicated fields that differ between two
+// versions of a task.
+func ChangedFields(old Task, current Task) []string {
+	var fields []string
+
+	if old.Title != current.Title {
+		fields = append(fields, FieldTitle)
+	}
+
+	if old.Description != current.Description {
+		fields = append(fields, FieldDescription)
+	}
+
+	if !sameTime(old.Due, current.Due) {
+		fields = append(fields, FieldDue)
+	}
+
+	if old.Priority != current.Priority {
+		fields = append(fields, FieldPriority)
+	}
+
+	if old.Recurrence != current.Recurrence {
+		fields = append(fields, FieldRecurrence)
+	}
+
+	if !slices.Equal(old.Remind, current.Remind) {
+		fields = append(fields, FieldRemind)
+	}
+
+	if !slices.Equal(old.Tags, current.Tags) {
+		fields = append(fields, FieldTags)
+	}
+
+	if !slices.Equal(old.Contexts, current.Contexts) {
+		fields = append(fields, FieldContexts)
+	}
+
+	if !maps.Equal(old.Custom, current.Custom) {
+		fields = append(fields, FieldCustom)
+	}
+
+	if !slices.EqualFunc(old.Notes, current.Notes, Note.Equal) {
+		fields = append(fields, FieldNotes)
+	}
+
+	if old.Status != current.Status {
+		fields = append(fields, FieldStatus)
+	}
+
+	if old.IsDeleted != current.IsDeleted {
+		fields = append(fields, FieldDeleted)
+	}
+
+	return fields
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
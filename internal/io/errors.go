@@ -0,0 +1,29 @@
+package io
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrOutOfBounds = errors.New("out of bounds")
+	ErrNotFound    = errors.New("task not found")
+	ErrNoChange    = errors.New("No value is changed")
+	ErrInvalid     = errors.New("invalid task")
+	ErrLocked      = errors.New("database is locked by another process")
+	ErrCorrupt     = errors.New("database is corrupt")
+)
+
+// IndexError reports a task index that does not exist in the database. It
+// matches ErrOutOfBounds with errors.Is.
+type IndexError struct {
+	Index int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("invalid index %d: out of bounds", e.Index)
+}
+
+func (e *IndexError) Unwrap() error {
+	return ErrOutOfBounds
+}
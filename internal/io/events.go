@@ -0,0 +1,130 @@
+package io
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event types, one per kind of change a write can make to a task.
+const (
+	EventAdded    = "task.added"
+	EventChanged  = "task.changed"
+	EventDeleted  = "task.deleted"
+	EventRestored = "task.restored"
+	EventPurged   = "task.purged"
+)
+
+var EventTypes = []string{EventAdded, EventChanged, EventDeleted, EventRestored, EventPurged}
+
+// Event describes one change to a task. Task is the task after the change,
+// or as it was last stored for task.purged; Fields lists what a
+// task.changed event changed.
+type Event struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	Task   Task      `json:"task"`
+	Fields []string  `json:"fields,omitempty"`
+}
+
+var (
+	subscribersMu sync.RWMutex
+	subscribers   []func(fileName string, events []Event)
+)
+
+// Subscribe registers fn to receive the events of every write to any
+// database. fn runs after the file is written and before it is unlocked,
+// so the events of concurrent writers arrive in the order of the writes.
+func Subscribe(fn func(fileName string, events []Event)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	subscribers = append(subscribers, fn)
+}
+
+func subscribed() bool {
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+
+	return len(subscribers) > 0
+}
+
+func publish(fileName string, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(fileName, events)
+	}
+}
+
+// Diff returns the events that turn old into current. Tasks are matched on
+// their UID, or on their ID for tasks stored before UIDs were.
+func Diff(old Database, current Database, now time.Time) []Event {
+	var events []Event
+
+	add := func(eventType string, task Task, fields []string) {
+		task.Clocks = nil
+		events = append(events, Event{ID: NewUID(), Type: eventType, Time: now, Task: task, Fields: fields})
+	}
+
+	before := make(map[string]Task, len(old.Tasks))
+
+	for _, task := range old.Tasks {
+		before[eventKey(task)] = task
+	}
+
+	for _, task := range current.Tasks {
+		previous, found := before[eventKey(task)]
+		delete(before, eventKey(task))
+
+		if !found {
+			add(EventAdded, task, nil)
+			continue
+		}
+
+		fields := ChangedFields(previous, task)
+
+		if slices.Contains(fields, FieldDeleted) {
+			fields = slices.DeleteFunc(fields, func(field string) bool { return field == FieldDeleted })
+
+			if len(fields) > 0 {
+				add(EventChanged, task, fields)
+			}
+
+			if task.IsDeleted {
+				add(EventDeleted, task, nil)
+			} else {
+				add(EventRestored, task, nil)
+			}
+
+			continue
+		}
+
+		if len(fields) > 0 {
+			add(EventChanged, task, fields)
+		}
+	}
+
+	for _, task := range old.Tasks {
+		if _, purged := before[eventKey(task)]; purged {
+			add(EventPurged, task, nil)
+		}
+	}
+
+	return events
+}
+
+func eventKey(task Task) string {
+	if task.UID != "" {
+		return task.UID
+	}
+
+	return "#" + strconv.Itoa(task.ID)
+}
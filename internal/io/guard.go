@@ -0,0 +1,77 @@
+package io
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	guardsMu sync.RWMutex
+	guards   []func(fileName string, event Event) (Task, error)
+)
+
+// Guard registers fn to check every task a write adds, changes, deletes or
+// restores before it is stored. fn returns the task to store, which may
+// differ from event.Task, or an error that cancels the whole write. The ID,
+// UID and trash state of the task stay as they were. Merges, imports and
+// purges are not guarded: they apply changes that were already made.
+func Guard(fn func(fileName string, event Event) (Task, error)) {
+	guardsMu.Lock()
+	defer guardsMu.Unlock()
+
+	guards = append(guards, fn)
+}
+
+// check runs the guards over the changes db makes to the stored database.
+// The caller must hold the lock.
+func check(fileName string, db *Database) error {
+	guardsMu.RLock()
+	defer guardsMu.RUnlock()
+
+	if len(guards) == 0 {
+		return nil
+	}
+
+	old, _ := readJSON(fileName)
+
+	for _, event := range Diff(old, *db, time.Now()) {
+		if event.Type == EventPurged {
+			continue
+		}
+
+		index := indexOf(*db, eventKey(event.Task))
+
+		for _, fn := range guards {
+			current := db.Tasks[index]
+			event.Task = current
+			event.Task.Clocks = nil
+
+			task, err := fn(fileName, event)
+
+			if err != nil {
+				return err
+			}
+
+			task.ID = current.ID
+			task.UID = current.UID
+			task.IsDeleted = current.IsDeleted
+			task.DeletedAt = current.DeletedAt
+			task.Clocks = current.Clocks
+
+			db.stamp(&task, clockFields(current, task)...)
+			db.Tasks[index] = task
+		}
+	}
+
+	return nil
+}
+
+func indexOf(db Database, key string) int {
+	for index, task := range db.Tasks {
+		if eventKey(task) == key {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -1,53 +1,93 @@
 package io
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"time"
+
+	"github.com/tristnaja/taski/internal/hlc"
+)
+
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
 )
 
 type Task struct {
 	ID          int        `json:"id"`
+	UID         string     `json:"uid,omitempty"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	Date        time.Time  `json:"date"`
+	Due         *time.Time `json:"due,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	Remind      []string   `json:"remind,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Contexts    []string   `json:"contexts,omitempty"`
+	Status      string     `json:"status,omitempty"`
 	IsDeleted   bool       `json:"is_deleted"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
-}
 
-type Database struct {
-	Size  int    `json:"size"`
-	Tasks []Task `json:"tasks"`
+	// Custom holds the values of the custom fields declared in config.json.
+	Custom map[string]string `json:"custom,omitempty"`
+	// Notes are the annotations added with taski note, oldest first.
+	Notes []Note `json:"notes,omitempty"`
+
+	Clocks map[string]hlc.Timestamp `json:"clocks,omitempty"`
 }
 
-func AddTask(task Task, fileName string) error {
-	db, err := readJSON(fileName)
+// Note is a timestamped annotation of a task, kept apart from its
+// description so that adding one never overwrites anything.
+type Note struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
 
-	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
-	}
+// Equal reports whether two notes were written in the same second with the
+// same text. Notes are written and exported to the second, so a note read
+// back from iCalendar or CalDAV equals the one it was made from.
+func (n Note) Equal(other Note) bool {
+	return n.Key() == other.Key() && n.Text == other.Text
+}
 
-	task.ID = len(db.Tasks)
+// Key identifies a note by the second it was written, in UTC.
+func (n Note) Key() string {
+	return n.Time.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
 
-	db.Tasks = append(db.Tasks, task)
-	db.Size++
+// LockTimeout is how long a write waits for another process holding the
+// database before giving up with ErrLocked.
+var LockTimeout = 2 * time.Second
 
-	err = writeJSON(fileName, db)
+type Database struct {
+	Size  int    `json:"size"`
+	Tasks []Task `json:"tasks"`
 
-	if err != nil {
-		return fmt.Errorf("writing into file: %w", err)
-	}
+	Node       string                   `json:"node,omitempty"`
+	Clock      hlc.Timestamp            `json:"clock,omitzero"`
+	Tombstones map[string]hlc.Timestamp `json:"tombstones,omitempty"`
+}
 
-	return nil
+func AddTask(task Task, fileName string) error {
+	return Transact(fileName, func(db *Database) error {
+		db.Add(task)
+		return nil
+	})
 }
 
 func ReadTask(fileName string) (Database, error) {
 	var filteredDB Database
 
-	db, err := readJSON(fileName)
+	db, err := read(fileName)
 
 	if err != nil {
 		return Database{}, fmt.Errorf("reading file: %w", err)
@@ -64,25 +104,43 @@ func ReadTask(fileName string) (Database, error) {
 	return filteredDB, nil
 }
 
+func ReadAll(fileName string) (Database, error) {
+	db, err := read(fileName)
+
+	if err != nil {
+		return Database{}, fmt.Errorf("reading file: %w", err)
+	}
+
+	return db, nil
+}
+
 func ChangeTask(fileName string, taskIndex int, newTitle string, newDescription string) error {
 	if taskIndex < 0 {
-		return errors.New("Index cannot be < 0")
+		return &IndexError{Index: taskIndex}
 	}
 
 	if newTitle == "" && newDescription == "" {
-		return errors.New("No value is changed")
+		return ErrNoChange
 	}
 
-	db, err := readJSON(fileName)
+	unlock, err := lock(fileName)
 
-	if taskIndex >= len(db.Tasks) {
-		return fmt.Errorf("invalid index %d: out of bounds", taskIndex)
+	if err != nil {
+		return err
 	}
 
+	defer unlock()
+
+	db, err := load(fileName)
+
 	if err != nil {
 		return fmt.Errorf("reading file: %w", err)
 	}
 
+	if taskIndex >= len(db.Tasks) {
+		return &IndexError{Index: taskIndex}
+	}
+
 	if newTitle == "" {
 		newTitle = db.Tasks[taskIndex].Title
 	}
@@ -91,9 +149,17 @@ func ChangeTask(fileName string, taskIndex int, newTitle string, newDescription
 		newDescription = db.Tasks[taskIndex].Description
 	}
 
+	old := db.Tasks[taskIndex]
 	db.Tasks[taskIndex].Title = newTitle
 	db.Tasks[taskIndex].Description = newDescription
 	db.Tasks[taskIndex].Date = time.Now()
+	db.stamp(&db.Tasks[taskIndex], clockFields(old, db.Tasks[taskIndex])...)
+
+	err = check(fileName, &db)
+
+	if err != nil {
+		return err
+	}
 
 	err = writeJSON(fileName, db)
 
@@ -124,18 +190,28 @@ func RestoreTask(fileName string, taskIndex int) error {
 	return nil
 }
 
-func CleanUp(fileName string, retention time.Duration) error {
+// CleanUp purges the tasks that have been in trash for longer than
+// retention and returns how many it removed.
+func CleanUp(fileName string, retention time.Duration) (int, error) {
 	now := time.Now()
 	var keptTasks []Task
 
-	db, err := readJSON(fileName)
+	unlock, err := lock(fileName)
 
 	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
+		return 0, err
+	}
+
+	defer unlock()
+
+	db, err := load(fileName)
+
+	if err != nil {
+		return 0, fmt.Errorf("reading file: %w", err)
 	}
 
 	if db.Tasks == nil {
-		return nil
+		return 0, nil
 	}
 
 	for _, task := range db.Tasks {
@@ -146,24 +222,34 @@ func CleanUp(fileName string, retention time.Duration) error {
 		} else if task.DeletedAt == nil {
 			keptTasks = append(keptTasks, task)
 		} else {
+			db.bury(task)
 			continue
 			// NOTE: deleted task will not be kept into the db
 		}
 	}
 
+	removed := len(db.Tasks) - len(keptTasks)
 	db.Tasks = keptTasks
 
 	err = writeJSON(fileName, db)
 
 	if err != nil {
-		return fmt.Errorf("writing file: %w", err)
+		return 0, fmt.Errorf("writing file: %w", err)
 	}
 
-	return nil
+	return removed, nil
 }
 
 func RestoreAll(fileName string) error {
-	db, err := readJSON(fileName)
+	unlock, err := lock(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	db, err := load(fileName)
 
 	if err != nil {
 		return fmt.Errorf("reading file: %w", err)
@@ -173,11 +259,18 @@ func RestoreAll(fileName string) error {
 		if db.Tasks[index].IsDeleted {
 			db.Tasks[index].IsDeleted = false
 			db.Tasks[index].DeletedAt = nil
+			db.stamp(&db.Tasks[index], FieldDeleted)
 		}
 	}
 
 	db.Size = len(db.Tasks)
 
+	err = check(fileName, &db)
+
+	if err != nil {
+		return err
+	}
+
 	err = writeJSON(fileName, db)
 
 	if err != nil {
@@ -187,7 +280,198 @@ func RestoreAll(fileName string) error {
 	return nil
 }
 
+// Rewrite replaces the whole database with what fn makes of it, e.g. a
+// merge with another copy. The lock is held from the read to the write, so
+// no write made in between is lost.
+func Rewrite(fileName string, fn func(db Database) Database) error {
+	unlock, err := lock(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	db, err := load(fileName)
+
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	err = writeJSON(fileName, fn(db))
+
+	if err != nil {
+		return fmt.Errorf("writing into file: %w", err)
+	}
+
+	return nil
+}
+
+// ImportTasks merges tasks coming from another source into the database,
+// matching them on their stable UID so a repeated import does not
+// duplicate anything.
+func ImportTasks(fileName string, tasks []Task) (added int, updated int, err error) {
+	unlock, err := lock(fileName)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer unlock()
+
+	db, err := load(fileName)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading file: %w", err)
+	}
+
+	byUID := make(map[string]int, len(db.Tasks))
+
+	for index, task := range db.Tasks {
+		byUID[task.StableUID()] = index
+	}
+
+	for _, task := range tasks {
+		index, found := byUID[task.StableUID()]
+
+		if !found {
+			if task.UID == "" {
+				task.UID = NewUID()
+			}
+
+			task.ID = len(db.Tasks)
+
+			if task.Date.IsZero() {
+				task.Date = time.Now()
+			}
+
+			if task.IsDeleted && task.DeletedAt == nil {
+				now := time.Now()
+				task.DeletedAt = &now
+			}
+
+			if !task.IsDeleted {
+				db.Size++
+			}
+
+			task.Clocks = nil
+			db.stamp(&task, newFields(task)...)
+			byUID[task.UID] = task.ID
+			db.Tasks = append(db.Tasks, task)
+			added++
+			continue
+		}
+
+		existing := &db.Tasks[index]
+		old := *existing
+		existing.Title = task.Title
+		existing.Description = task.Description
+		existing.Due = task.Due
+		existing.Priority = task.Priority
+		existing.Recurrence = task.Recurrence
+		existing.Remind = task.Remind
+		existing.Tags = task.Tags
+		existing.Contexts = task.Contexts
+		existing.Custom = task.Custom
+		existing.Notes = task.Notes
+		existing.Status = task.Status
+
+		if task.IsDeleted && !existing.IsDeleted {
+			now := time.Now()
+			existing.IsDeleted = true
+			existing.DeletedAt = &now
+			db.Size--
+		} else if !task.IsDeleted && existing.IsDeleted {
+			existing.IsDeleted = false
+			existing.DeletedAt = nil
+			db.Size++
+		}
+
+		db.stamp(existing, clockFields(old, *existing)...)
+		updated++
+	}
+
+	err = writeJSON(fileName, db)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("writing into file: %w", err)
+	}
+
+	return added, updated, nil
+}
+
+// Clone returns a copy of the task that shares no slice, map or pointer
+// with it, so it can be changed without changing the original.
+func (t Task) Clone() Task {
+	t.Remind = slices.Clone(t.Remind)
+	t.Tags = slices.Clone(t.Tags)
+	t.Contexts = slices.Clone(t.Contexts)
+	t.Notes = slices.Clone(t.Notes)
+	t.Custom = maps.Clone(t.Custom)
+	t.Clocks = maps.Clone(t.Clocks)
+
+	if t.Due != nil {
+		due := *t.Due
+		t.Due = &due
+	}
+
+	if t.DeletedAt != nil {
+		deletedAt := *t.DeletedAt
+		t.DeletedAt = &deletedAt
+	}
+
+	return t
+}
+
+// StableUID returns the task UID. Reading a database saves a UID for every
+// task without one, so only copies that never went through it, such as old
+// revisions in git history, fall back to one derived from the ID.
+func (t Task) StableUID() string {
+	if t.UID != "" {
+		return t.UID
+	}
+
+	return fmt.Sprintf("taski-%d", t.ID)
+}
+
+func NewUID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+func ValidPriority(priority string) bool {
+	switch priority {
+	case "", PriorityHigh, PriorityMedium, PriorityLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeJSON stores the database and publishes what the write changed. The
+// caller must hold the lock.
 func writeJSON(fileName string, db Database) error {
+	var old Database
+
+	if subscribed() {
+		// A file that cannot be read counts as empty; its tasks show up as added.
+		old, _ = readJSON(fileName)
+	}
+
+	err := encodeJSON(fileName, db)
+
+	if err != nil {
+		return err
+	}
+
+	publish(fileName, Diff(old, db, time.Now()))
+
+	return nil
+}
+
+func encodeJSON(fileName string, db Database) error {
 	file, err := os.OpenFile(fileName, os.O_TRUNC|os.O_RDWR, 0644)
 
 	if err != nil {
@@ -227,61 +511,76 @@ func readJSON(fileName string) (Database, error) {
 		if errors.Is(err, io.EOF) {
 			result = Database{}
 		} else {
-			return Database{}, fmt.Errorf("decoding file: %w", err)
+			return Database{}, fmt.Errorf("decoding file: %w: %w", ErrCorrupt, err)
 		}
 	}
 
 	return result, nil
 }
 
-func softDelete(fileName string, taskIndex int) error {
-	now := time.Now()
-
+// load reads the database for a write, saving a UID for every task stored
+// without one first, so that the write and the events it publishes already
+// see them. The caller must hold the lock.
+func load(fileName string) (Database, error) {
 	db, err := readJSON(fileName)
 
-	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
-	}
-
-	if taskIndex < 0 || taskIndex >= len(db.Tasks) {
-		return fmt.Errorf("invalid index %d: out of bounds", taskIndex)
+	if err != nil || !assignUIDs(&db) {
+		return db, err
 	}
 
-	db.Tasks[taskIndex].IsDeleted = true
-	db.Tasks[taskIndex].DeletedAt = &now
-	db.Size--
-
-	err = writeJSON(fileName, db)
+	err = encodeJSON(fileName, db)
 
 	if err != nil {
-		return fmt.Errorf("writing into file: %w", err)
+		return Database{}, fmt.Errorf("saving task UIDs: %w", err)
 	}
 
-	return nil
+	return db, nil
 }
 
-func restoreTask(fileName string, taskIndex int) error {
+// read reads the database outside the lock. Tasks stored before UIDs were
+// saved get theirs under the lock first, so every reader sees the same
+// ones.
+func read(fileName string) (Database, error) {
 	db, err := readJSON(fileName)
 
-	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
+	if err != nil || !slices.ContainsFunc(db.Tasks, func(task Task) bool { return task.UID == "" }) {
+		return db, err
 	}
 
-	if taskIndex < 0 || taskIndex >= len(db.Tasks) {
-		return fmt.Errorf("invalid index %d: out of bounds", taskIndex)
+	unlock, err := lock(fileName)
+
+	if err != nil {
+		return Database{}, err
 	}
 
-	if db.Tasks[taskIndex].IsDeleted {
-		db.Tasks[taskIndex].IsDeleted = false
-		db.Tasks[taskIndex].DeletedAt = nil
-		db.Size++
+	defer unlock()
+
+	return load(fileName)
+}
 
-		err = writeJSON(fileName, db)
+// assignUIDs gives every task without a UID a new one and reports whether
+// there was any.
+func assignUIDs(db *Database) bool {
+	assigned := false
 
-		if err != nil {
-			return fmt.Errorf("writing into file: %w", err)
+	for i := range db.Tasks {
+		if db.Tasks[i].UID == "" {
+			db.Tasks[i].UID = NewUID()
+			assigned = true
 		}
 	}
 
-	return nil
+	return assigned
+}
+
+func softDelete(fileName string, taskIndex int) error {
+	return Transact(fileName, func(db *Database) error {
+		return db.Delete(taskIndex)
+	})
+}
+
+func restoreTask(fileName string, taskIndex int) error {
+	return Transact(fileName, func(db *Database) error {
+		return db.Restore(taskIndex)
+	})
 }
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package io
+
+// lock is a no-op where flock is unavailable.
+func lock(fileName string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package io
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lock takes an exclusive advisory lock on fileName for a read-modify-write
+// cycle, waiting up to LockTimeout for another process to finish.
+func lock(fileName string) (func(), error) {
+	file, err := os.OpenFile(fileName+".lock", os.O_CREATE|os.O_RDWR, 0644)
+
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+
+	for {
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			file.Close()
+			return nil, fmt.Errorf("locking file: %w", err)
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, ErrLocked
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
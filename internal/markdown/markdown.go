@@ -0,0 +1,274 @@
+// Package markdown renders the Markdown used in task descriptions for a
+// terminal: headings, lists with checkboxes, block quotes, code blocks,
+// rules and inline emphasis, code and links, word-wrapped to a width and
+// styled with ANSI escape codes.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	bold      = "1"
+	dim       = "2"
+	italic    = "3"
+	underline = "4"
+	cyan      = "36"
+	reset     = "\x1b[0m"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listPattern    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	ansiPattern    = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// Render formats source for a terminal width columns wide; a width of zero
+// or less disables wrapping. Without color the markup is still turned into
+// plain text, e.g. bullets and link targets.
+func Render(source string, width int, color bool) string {
+	r := renderer{width: width, color: color}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			r.blank()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			i++
+
+			for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				r.write("    " + r.style(lines[i], cyan))
+			}
+		case rulePattern.MatchString(line):
+			r.write(r.style(strings.Repeat("─", r.ruleWidth()), dim))
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			codes := []string{bold}
+
+			if len(match[1]) == 1 {
+				codes = append(codes, underline)
+			}
+
+			r.wrap(r.style(r.inline(match[2]), codes...), "", "")
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+
+			i--
+			bar := r.style("│ ", dim)
+			r.wrap(r.style(r.inline(strings.Join(quote, " ")), italic), bar, bar)
+		case listPattern.MatchString(line):
+			match := listPattern.FindStringSubmatch(line)
+			indent := strings.Repeat("  ", len(strings.ReplaceAll(match[1], "\t", "  "))/2)
+			marker, text := listMarker(match[2], match[3], r.color)
+			r.wrap(r.inline(text), indent+marker+" ", indent+strings.Repeat(" ", visibleLen(marker)+1))
+		default:
+			paragraph := []string{trimmed}
+
+			for i+1 < len(lines) && isParagraphLine(lines[i+1]) {
+				i++
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+
+			r.wrap(r.inline(strings.Join(paragraph, " ")), "", "")
+		}
+	}
+
+	return strings.TrimRight(r.out.String(), "\n") + "\n"
+}
+
+type renderer struct {
+	width int
+	color bool
+	out   strings.Builder
+	// gap is set after a blank line so that blocks stay apart without
+	// stacking empty lines.
+	gap bool
+}
+
+func (r *renderer) write(line string) {
+	if r.gap && r.out.Len() > 0 {
+		r.out.WriteString("\n")
+	}
+
+	r.gap = false
+	r.out.WriteString(line + "\n")
+}
+
+func (r *renderer) blank() {
+	r.gap = true
+}
+
+func (r *renderer) ruleWidth() int {
+	if r.width <= 0 || r.width > 40 {
+		return 40
+	}
+
+	return r.width
+}
+
+// wrap writes text split into lines of at most r.width visible characters.
+// The first line starts with first, every following one with rest.
+func (r *renderer) wrap(text string, first string, rest string) {
+	prefix := first
+	line := ""
+
+	for _, word := range strings.Fields(text) {
+		if line != "" && r.width > 0 && visibleLen(prefix+line+" "+word) > r.width {
+			r.write(prefix + line)
+			prefix = rest
+			line = ""
+		}
+
+		if line != "" {
+			line += " "
+		}
+
+		line += word
+	}
+
+	r.write(prefix + line)
+}
+
+func (r *renderer) style(text string, codes ...string) string {
+	if !r.color || text == "" {
+		return text
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + reset
+}
+
+// inline renders emphasis, code spans and links. Markers without a closing
+// partner are kept as typed.
+func (r *renderer) inline(text string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text):
+			i++
+			b.WriteByte(text[i])
+		case c == '`':
+			end := strings.IndexByte(text[i+1:], '`')
+
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(r.style(text[i+1:i+1+end], cyan))
+			i += end + 1
+		case strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "__"):
+			marker := text[i : i+2]
+			end := strings.Index(text[i+2:], marker)
+
+			if end <= 0 {
+				b.WriteString(marker)
+				i++
+				continue
+			}
+
+			b.WriteString(r.style(r.inline(text[i+2:i+2+end]), bold))
+			i += end + 3
+		case (c == '*' || c == '_') && (i == 0 || text[i-1] == ' '):
+			end := strings.IndexByte(text[i+1:], c)
+
+			if end <= 0 {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(r.style(r.inline(text[i+1:i+1+end]), italic))
+			i += end + 1
+		case c == '[':
+			label, url, length, found := link(text[i:])
+
+			if !found {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(r.style(r.inline(label), underline))
+
+			if label != url {
+				b.WriteString(" " + r.style("("+url+")", dim))
+			}
+
+			i += length - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// link parses [label](url) at the start of text and returns its parts and
+// the length of the markup.
+func link(text string) (string, string, int, bool) {
+	closing := strings.Index(text, "](")
+
+	if closing < 0 {
+		return "", "", 0, false
+	}
+
+	end := strings.IndexByte(text[closing:], ')')
+
+	if end < 0 {
+		return "", "", 0, false
+	}
+
+	return text[1:closing], text[closing+2 : closing+end], closing + end + 1, true
+}
+
+// listMarker turns a list marker and a leading [ ] or [x] checkbox into
+// what is printed in front of the item.
+func listMarker(marker string, text string, color bool) (string, string) {
+	lower := strings.ToLower(text)
+
+	switch {
+	case strings.HasPrefix(lower, "[ ] "):
+		return "[ ]", text[4:]
+	case strings.HasPrefix(lower, "[x] "):
+		if color {
+			return "\x1b[32m[x]" + reset, text[4:]
+		}
+
+		return "[x]", text[4:]
+	case marker == "-" || marker == "*" || marker == "+":
+		return "•", text
+	default:
+		return marker, text
+	}
+}
+
+// isParagraphLine reports whether line continues a paragraph rather than
+// starting a new block.
+func isParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	return trimmed != "" &&
+		!strings.HasPrefix(trimmed, "```") &&
+		!strings.HasPrefix(trimmed, "~~~") &&
+		!strings.HasPrefix(trimmed, ">") &&
+		!headingPattern.MatchString(trimmed) &&
+		!listPattern.MatchString(line) &&
+		!rulePattern.MatchString(line)
+}
+
+func visibleLen(text string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,135 @@
+// Package quickadd parses single-line task entries such as
+// "Buy milk +errands @home due:tomorrow !high".
+package quickadd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// Parse splits input into a task. Words starting with + are tags, words
+// starting with @ are contexts, due:<date> sets the due date and
+// !high/!medium/!low (or !h/!m/!l) the priority. Everything else, in order,
+// is the title. A leading backslash keeps a word literal, e.g. \+1.
+func Parse(input string, now time.Time) (io.Task, error) {
+	var task io.Task
+	var title []string
+
+	for _, word := range strings.Fields(input) {
+		switch {
+		case strings.HasPrefix(word, `\`) && len(word) > 1:
+			title = append(title, word[1:])
+		case strings.HasPrefix(word, "+") && len(word) > 1:
+			task.Tags = appendUnique(task.Tags, word[1:])
+		case strings.HasPrefix(word, "@") && len(word) > 1:
+			task.Contexts = appendUnique(task.Contexts, word[1:])
+		case strings.HasPrefix(strings.ToLower(word), "due:"):
+			due, err := ParseDate(word[len("due:"):], now)
+
+			if err != nil {
+				return io.Task{}, err
+			}
+
+			task.Due = &due
+		case strings.HasPrefix(word, "!") && len(word) > 1:
+			priority, err := parsePriority(word[1:])
+
+			if err != nil {
+				return io.Task{}, err
+			}
+
+			task.Priority = priority
+		default:
+			title = append(title, word)
+		}
+	}
+
+	task.Title = strings.Join(title, " ")
+
+	if task.Title == "" {
+		return io.Task{}, fmt.Errorf("no title in %q", input)
+	}
+
+	return task, nil
+}
+
+// ParseDate reads an absolute date (YYYY-MM-DD, optionally followed by
+// THH:MM), a relative one (today, tomorrow, yesterday, 3d, 2w) or a weekday
+// name, which means its next occurrence after today.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	lower := strings.ToLower(value)
+
+	switch lower {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+		date, err := time.ParseInLocation(layout, value, now.Location())
+
+		if err == nil {
+			return date, nil
+		}
+	}
+
+	if len(lower) > 1 {
+		count, err := strconv.Atoi(lower[:len(lower)-1])
+
+		if err == nil {
+			switch lower[len(lower)-1] {
+			case 'd':
+				return today.AddDate(0, 0, count), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*count), nil
+			}
+		}
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+
+		if lower == name || lower == name[:3] {
+			ahead := (int(day) - int(today.Weekday()) + 7) % 7
+
+			if ahead == 0 {
+				ahead = 7
+			}
+
+			return today.AddDate(0, 0, ahead), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD, today, tomorrow, a weekday or e.g. 3d", value)
+}
+
+func parsePriority(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "h", "high":
+		return io.PriorityHigh, nil
+	case "m", "medium":
+		return io.PriorityMedium, nil
+	case "l", "low":
+		return io.PriorityLow, nil
+	default:
+		return "", fmt.Errorf("invalid priority %q, usable: high, medium, low", value)
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
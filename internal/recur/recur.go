@@ -0,0 +1,229 @@
+// Package recur reads the subset of RFC 5545 recurrence rules taski uses,
+// e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH, and projects the occurrences of a
+// repeating task.
+package recur
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods stops runaway projections of rules that never match, such as
+// a monthly rule on the 31st with COUNT set.
+const maxPeriods = 10000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// aliases are the short names accepted in place of a rule.
+var aliases = map[string]string{
+	"daily":    "FREQ=DAILY",
+	"weekly":   "FREQ=WEEKLY",
+	"weekdays": "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"monthly":  "FREQ=MONTHLY",
+	"yearly":   "FREQ=YEARLY",
+}
+
+type Rule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    *time.Time
+	ByDay    []time.Weekday
+}
+
+// Parse reads an RRULE value, with or without the "RRULE:" prefix, or one of
+// daily, weekly, weekdays, monthly and yearly.
+func Parse(value string) (Rule, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+
+	if alias, found := aliases[strings.ToLower(text)]; found {
+		text = alias
+	}
+
+	rule := Rule{Interval: 1}
+
+	for _, part := range strings.Split(text, ";") {
+		key, val, found := strings.Cut(part, "=")
+
+		if !found {
+			return Rule{}, fmt.Errorf("invalid recurrence %q, expected e.g. FREQ=WEEKLY or weekly", value)
+		}
+
+		val = strings.ToUpper(val)
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}, val) {
+				return Rule{}, fmt.Errorf("unsupported recurrence frequency %q, usable: DAILY, WEEKLY, MONTHLY, YEARLY", val)
+			}
+
+			rule.Freq = val
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(val)
+
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("invalid recurrence %s %q", strings.ToUpper(key), val)
+			}
+
+			if strings.ToUpper(key) == "INTERVAL" {
+				rule.Interval = n
+			} else {
+				rule.Count = n
+			}
+		case "UNTIL":
+			until, err := parseUntil(val)
+
+			if err != nil {
+				return Rule{}, err
+			}
+
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, found := weekdays[day]
+
+				if !found {
+					return Rule{}, fmt.Errorf("invalid recurrence BYDAY %q, expected days such as MO,WE", day)
+				}
+
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "WKST":
+		default:
+			return Rule{}, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("invalid recurrence %q: FREQ is missing", value)
+	}
+
+	return rule, nil
+}
+
+// String writes the rule back as an RRULE value.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	if len(r.ByDay) > 0 {
+		var days []string
+
+		for _, day := range r.ByDay {
+			days = append(days, strings.ToUpper(day.String()[:2]))
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of a series that starts at start and
+// fall in [from, to), in order. start is the first occurrence.
+func (r Rule) Between(start time.Time, from time.Time, to time.Time) []time.Time {
+	var result []time.Time
+	count := 0
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.period(start, period)
+
+		if len(candidates) > 0 && !candidates[0].Before(to) {
+			break
+		}
+
+		for _, date := range candidates {
+			if date.Before(start) {
+				continue
+			}
+
+			count++
+
+			if r.Count > 0 && count > r.Count || r.Until != nil && date.After(*r.Until) || !date.Before(to) {
+				return result
+			}
+
+			if !date.Before(from) {
+				result = append(result, date)
+			}
+		}
+	}
+
+	return result
+}
+
+// period returns the candidate dates of the nth period after start, keeping
+// the time of day of start. Dates that do not exist, such as 31 April, are
+// skipped as RFC 5545 requires.
+func (r Rule) period(start time.Time, n int) []time.Time {
+	year, month, day := start.Date()
+	hour, minute, second := start.Clock()
+	step := n * r.Interval
+
+	switch r.Freq {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, step)}
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*step)}
+		}
+
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*step)
+		var dates []time.Time
+
+		for offset := 0; offset < 7; offset++ {
+			date := monday.AddDate(0, 0, offset)
+
+			if slices.Contains(r.ByDay, date.Weekday()) {
+				dates = append(dates, date)
+			}
+		}
+
+		return dates
+	case "MONTHLY":
+		month += time.Month(step)
+	case "YEARLY":
+		year += step
+	}
+
+	date := time.Date(year, month, day, hour, minute, second, 0, start.Location())
+
+	if date.Day() != day {
+		return nil
+	}
+
+	return []time.Time{date}
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		until, err := time.ParseInLocation(layout, value, time.UTC)
+
+		if err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Second)
+			}
+
+			return until, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid recurrence UNTIL %q, expected YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
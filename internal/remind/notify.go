@@ -0,0 +1,184 @@
+package remind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+)
+
+// Notifiers lists the names accepted by NewNotifiers.
+var Notifiers = []string{"stdout", "desktop", "webhook", "email"}
+
+// Notifier delivers a reminder somewhere the user will see it.
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// NewNotifiers builds the named notifiers from the reminder settings of
+// config.json. No names means stdout.
+func NewNotifiers(names []string, cfg config.Reminders, stdout io.Writer) ([]Notifier, error) {
+	if len(names) == 0 {
+		names = []string{"stdout"}
+	}
+
+	var notifiers []Notifier
+
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "stdout":
+			notifiers = append(notifiers, Writer{W: stdout})
+		case "desktop":
+			notifiers = append(notifiers, Desktop{})
+		case "webhook":
+			if cfg.Webhook == "" {
+				return nil, fmt.Errorf("the webhook notifier needs reminders.webhook in config.json")
+			}
+
+			notifiers = append(notifiers, Webhook{URL: cfg.Webhook, Client: &http.Client{Timeout: 10 * time.Second}})
+		case "email":
+			if cfg.Email.Host == "" || cfg.Email.From == "" || len(cfg.Email.To) == 0 {
+				return nil, fmt.Errorf("the email notifier needs reminders.email host, from and to in config.json")
+			}
+
+			notifiers = append(notifiers, Email(cfg.Email))
+		default:
+			return nil, fmt.Errorf("unknown notifier %q, usable: %s", name, strings.Join(Notifiers, ", "))
+		}
+	}
+
+	return notifiers, nil
+}
+
+// Writer prints reminders as lines of text.
+type Writer struct {
+	W io.Writer
+}
+
+func (n Writer) Notify(ctx context.Context, reminder Reminder) error {
+	now := time.Now()
+	_, err := fmt.Fprintf(n.W, "%v Reminder: #%d %v, %v\n", now.Format("15:04"), reminder.Task.ID, reminder.Task.Title, reminder.Body(now))
+
+	return err
+}
+
+// Desktop shows reminders through the org.freedesktop.Notifications D-Bus
+// service, talking to the session bus with gdbus.
+type Desktop struct{}
+
+func (Desktop) Notify(ctx context.Context, reminder Reminder) error {
+	cmd := exec.CommandContext(ctx, "gdbus", "call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.Notifications.Notify",
+		// app name, replaces id, icon, summary, body, actions, hints, timeout
+		`"taski"`, "0", `""`, strconv.Quote(reminder.Task.Title), strconv.Quote(reminder.Body(time.Now())), "[]", "{}", "-1")
+
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		return fmt.Errorf("sending desktop notification: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// Webhook posts reminders as JSON to a URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// webhookPayload is the body Webhook posts.
+type webhookPayload struct {
+	ID      int        `json:"id"`
+	UID     string     `json:"uid"`
+	Title   string     `json:"title"`
+	Due     *time.Time `json:"due,omitempty"`
+	At      time.Time  `json:"at"`
+	Offset  string     `json:"offset"`
+	Message string     `json:"message"`
+}
+
+func (n Webhook) Notify(ctx context.Context, reminder Reminder) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:      reminder.Task.ID,
+		UID:     reminder.Task.StableUID(),
+		Title:   reminder.Task.Title,
+		Due:     reminder.Task.Due,
+		At:      reminder.At,
+		Offset:  reminder.Offset,
+		Message: reminder.Body(time.Now()),
+	})
+
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.Client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("posting webhook: %v answered %v", n.URL, response.Status)
+	}
+
+	return nil
+}
+
+// Email sends reminders by mail through an SMTP server, such as a local
+// relay or a test stand-in like MailHog.
+type Email config.Email
+
+func (n Email) Notify(ctx context.Context, reminder Reminder) error {
+	port := n.Port
+
+	if port == 0 {
+		port = 25
+	}
+
+	var auth smtp.Auth
+
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	var message bytes.Buffer
+
+	fmt.Fprintf(&message, "From: %v\r\n", n.From)
+	fmt.Fprintf(&message, "To: %v\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&message, "Subject: Reminder: %v\r\n", strings.ReplaceAll(reminder.Task.Title, "\n", " "))
+	fmt.Fprintf(&message, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&message, "%v\r\n\r\nTask %d: %v\r\n", reminder.Body(time.Now()), reminder.Task.ID, reminder.Task.Title)
+
+	err := smtp.SendMail(net.JoinHostPort(n.Host, strconv.Itoa(port)), auth, n.From, n.To, message.Bytes())
+
+	if err != nil {
+		return fmt.Errorf("sending reminder mail: %w", err)
+	}
+
+	return nil
+}
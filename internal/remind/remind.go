@@ -0,0 +1,371 @@
+// Package remind works out when to remind of tasks with a due date and
+// remembers which reminders were delivered; taski daemon runs it in a loop
+// and hands what is due to the notifiers.
+package remind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+const (
+	// AtDue is the offset of a reminder at the due time itself.
+	AtDue = "due"
+	// None turns reminders off for a task, including the default ones.
+	None = "none"
+	// Snoozed is the offset of the reminder a snooze puts off to.
+	Snoozed = "snoozed"
+)
+
+// MaxLate is how late a reminder may still be delivered. Reminders missed
+// by more while the daemon was not running are dropped.
+const MaxLate = 24 * time.Hour
+
+// Reminder is one notification about a task.
+type Reminder struct {
+	Task io.Task
+	At   time.Time
+	// Offset is the setting the reminder comes from, such as 30m-before,
+	// due or snoozed.
+	Offset string
+}
+
+// State remembers delivered reminders by key, with the time they were for,
+// and snoozed tasks by UID, with the time they are snoozed until.
+type State struct {
+	Sent    map[string]time.Time `json:"sent"`
+	Snoozed map[string]time.Time `json:"snoozed"`
+}
+
+// StatePath returns the reminder state file that belongs to a database.
+func StatePath(fileName string) string {
+	return filepath.Join(filepath.Dir(fileName), "reminders.json")
+}
+
+// ParseOffset reads a reminder setting such as 30m-before, 1h30m-before,
+// 2d-before or due and returns how long before the due date it is.
+func ParseOffset(value string) (time.Duration, error) {
+	text := strings.ToLower(strings.TrimSpace(value))
+
+	if text == AtDue {
+		return 0, nil
+	}
+
+	offset, err := ParseDuration(strings.TrimSuffix(text, "-before"))
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid reminder %q, expected e.g. 30m-before, 1h-before, 2d-before or due", value)
+	}
+
+	return offset, nil
+}
+
+// FormatOffset writes an offset back the way ParseOffset reads it.
+func FormatOffset(offset time.Duration) string {
+	if offset <= 0 {
+		return AtDue
+	}
+
+	return FormatDuration(offset) + "-before"
+}
+
+// Normalize reads a comma-separated list of reminder settings into their
+// canonical form, dropping duplicates. "none" stands alone.
+func Normalize(value string) ([]string, error) {
+	var result []string
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+
+		if part == "" {
+			continue
+		}
+
+		if part == None {
+			return []string{None}, nil
+		}
+
+		offset, err := ParseOffset(part)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if canonical := FormatOffset(offset); !slices.Contains(result, canonical) {
+			result = append(result, canonical)
+		}
+	}
+
+	return result, nil
+}
+
+// ParseDuration reads a duration in weeks, days, hours and minutes, such as
+// 10m, 1h30m or 2d.
+func ParseDuration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{'w': 7 * 24 * time.Hour, 'd': 24 * time.Hour, 'h': time.Hour, 'm': time.Minute}
+	var total time.Duration
+	number := ""
+
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+
+		unit, found := units[c]
+
+		if !found || number == "" {
+			return 0, fmt.Errorf("invalid duration %q, expected e.g. 10m, 1h30m or 2d", value)
+		}
+
+		n, err := strconv.Atoi(number)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+
+		total += time.Duration(n) * unit
+		number = ""
+	}
+
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q: %q has no unit", value, number)
+	}
+
+	return total, nil
+}
+
+// FormatDuration writes a duration in days, hours and minutes, rounded to
+// the minute, such as 1d2h or 30m.
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	if d < time.Minute {
+		return "0m"
+	}
+
+	var b strings.Builder
+
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if n := d / unit.size; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			d -= n * unit.size
+		}
+	}
+
+	return b.String()
+}
+
+// Offsets returns the reminder settings that apply to a task: its own, or
+// the defaults when it has none.
+func Offsets(task io.Task, defaults []string) []string {
+	switch {
+	case len(task.Remind) == 1 && task.Remind[0] == None:
+		return nil
+	case len(task.Remind) > 0:
+		return task.Remind
+	default:
+		return defaults
+	}
+}
+
+// Schedule returns the reminders not yet delivered that fall in (from, to],
+// for open tasks, sorted by time and then task id. Settings that do not
+// parse are skipped. A snoozed task is only reminded of once its snooze is
+// over.
+func Schedule(tasks []io.Task, defaults []string, state State, from time.Time, to time.Time) []Reminder {
+	var reminders []Reminder
+
+	add := func(reminder Reminder) {
+		if reminder.At.After(from) && !reminder.At.After(to) {
+			if _, sent := state.Sent[reminder.Key()]; !sent {
+				reminders = append(reminders, reminder)
+			}
+		}
+	}
+
+	for _, task := range tasks {
+		if task.IsDone() || task.IsDeleted {
+			continue
+		}
+
+		until, snoozed := state.Snoozed[task.StableUID()]
+
+		if task.Due != nil {
+			for _, setting := range Offsets(task, defaults) {
+				offset, err := ParseOffset(setting)
+
+				if err != nil {
+					continue
+				}
+
+				at := task.Due.Add(-offset)
+
+				if snoozed && at.Before(until) {
+					continue
+				}
+
+				add(Reminder{Task: task, At: at, Offset: FormatOffset(offset)})
+			}
+		}
+
+		if snoozed {
+			add(Reminder{Task: task, At: until, Offset: Snoozed})
+		}
+	}
+
+	sort.SliceStable(reminders, func(i, j int) bool {
+		if !reminders[i].At.Equal(reminders[j].At) {
+			return reminders[i].At.Before(reminders[j].At)
+		}
+
+		return reminders[i].Task.ID < reminders[j].Task.ID
+	})
+
+	return reminders
+}
+
+// Due returns the reminders to deliver at now.
+func Due(tasks []io.Task, defaults []string, state State, now time.Time) []Reminder {
+	return Schedule(tasks, defaults, state, now.Add(-MaxLate), now)
+}
+
+// Key identifies a reminder in State.Sent.
+func (r Reminder) Key() string {
+	return r.Task.StableUID() + "@" + r.At.UTC().Format(time.RFC3339) + "/" + r.Offset
+}
+
+// Body describes the reminder at now, e.g. "Due Mon 19 Oct 2026, 15:30 (in
+// 30m)".
+func (r Reminder) Body(now time.Time) string {
+	if r.Task.Due == nil {
+		return "Snoozed reminder"
+	}
+
+	due := r.Task.Due.Local().Format("Mon 02 Jan 2006, 15:04")
+	left := r.Task.Due.Sub(now)
+
+	switch {
+	case left.Abs() < time.Minute:
+		return "Due now (" + due + ")"
+	case left > 0:
+		return fmt.Sprintf("Due %v (in %v)", due, FormatDuration(left))
+	default:
+		return fmt.Sprintf("Overdue since %v (%v ago)", due, FormatDuration(-left))
+	}
+}
+
+// LoadState reads the reminder state. A missing file yields an empty
+// state.
+func LoadState(fileName string) (State, error) {
+	state := State{Sent: map[string]time.Time{}, Snoozed: map[string]time.Time{}}
+
+	content, err := os.ReadFile(fileName)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+
+		return State{}, fmt.Errorf("reading reminder state: %w", err)
+	}
+
+	err = json.Unmarshal(content, &state)
+
+	if err != nil {
+		return State{}, fmt.Errorf("decoding reminder state: %w", err)
+	}
+
+	if state.Sent == nil {
+		state.Sent = map[string]time.Time{}
+	}
+
+	if state.Snoozed == nil {
+		state.Snoozed = map[string]time.Time{}
+	}
+
+	return state, nil
+}
+
+// UpdateState reads the state afresh, applies fn and writes it back, so
+// that a snooze made while the daemon was delivering is kept.
+func UpdateState(fileName string, fn func(state *State)) error {
+	state, err := LoadState(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	fn(&state)
+
+	content, err := json.MarshalIndent(state, "", "\t")
+
+	if err != nil {
+		return fmt.Errorf("encoding reminder state: %w", err)
+	}
+
+	err = os.WriteFile(fileName, content, 0644)
+
+	if err != nil {
+		return fmt.Errorf("writing reminder state: %w", err)
+	}
+
+	return nil
+}
+
+// MarkSent records a delivered reminder; delivering a snoozed reminder
+// ends the snooze.
+func (s *State) MarkSent(reminder Reminder) {
+	s.Sent[reminder.Key()] = reminder.At
+
+	if reminder.Offset == Snoozed {
+		delete(s.Snoozed, reminder.Task.StableUID())
+	}
+}
+
+// Snooze puts the reminders of a task off until the given time. Reminders
+// before then count as delivered.
+func (s *State) Snooze(task io.Task, defaults []string, until time.Time) {
+	delete(s.Snoozed, task.StableUID())
+
+	for _, reminder := range Schedule([]io.Task{task}, defaults, *s, time.Time{}, until) {
+		s.Sent[reminder.Key()] = reminder.At
+	}
+
+	s.Snoozed[task.StableUID()] = until
+}
+
+// Prune forgets delivered reminders and snoozes from before the given
+// time, which can no longer be due.
+func (s *State) Prune(before time.Time) {
+	for key, at := range s.Sent {
+		if at.Before(before) {
+			delete(s.Sent, key)
+		}
+	}
+
+	for uid, until := range s.Snoozed {
+		if until.Before(before) {
+			delete(s.Snoozed, uid)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	taskiv1 "github.com/tristnaja/taski/api/taski/v1"
+	"github.com/tristnaja/taski/internal/export"
+	"github.com/tristnaja/taski/internal/filter"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+type grpcService struct {
+	taskiv1.UnimplementedTaskiServer
+	s *Server
+}
+
+// GRPC returns a gRPC server for the Taski service. It shares the lock of
+// the HTTP handler, so both can be served from one process.
+func (s *Server) GRPC() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			err := s.authorize(ctx)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := s.authorize(stream.Context())
+
+			if err != nil {
+				return err
+			}
+
+			return handler(srv, stream)
+		}),
+	)
+
+	taskiv1.RegisterTaskiServer(srv, &grpcService{s: s})
+
+	return srv
+}
+
+func (s *Server) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, value := range md.Get("authorization") {
+		token, found := strings.CutPrefix(value, "Bearer ")
+
+		if found && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid or missing token")
+}
+
+func (g *grpcService) ListTasks(ctx context.Context, req *taskiv1.ListTasksRequest) (*taskiv1.Database, error) {
+	taskFilter, err := g.parseFilter(req.GetWhere())
+
+	if err != nil {
+		return nil, err
+	}
+
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	return g.snapshot(req.GetIncludeTrash(), taskFilter)
+}
+
+func (g *grpcService) GetTask(ctx context.Context, req *taskiv1.GetTaskRequest) (*taskiv1.Task, error) {
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	task, err := g.s.get(ctx, int(req.GetId()), false)
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(task), nil
+}
+
+func (g *grpcService) AddTask(ctx context.Context, req *taskiv1.AddTaskRequest) (*taskiv1.Task, error) {
+	task := io.Task{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Priority:    req.GetPriority(),
+		Tags:        req.GetTags(),
+		Contexts:    req.GetContexts(),
+	}
+
+	if req.GetDue() != nil {
+		due := req.GetDue().AsTime().Local()
+		task.Due = &due
+	}
+
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	task, err := g.s.repo.Add(ctx, task)
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(task), nil
+}
+
+func (g *grpcService) ChangeTask(ctx context.Context, req *taskiv1.ChangeTaskRequest) (*taskiv1.Task, error) {
+	title, description := req.GetTitle(), req.GetDescription()
+
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	task, err := g.s.repo.Update(ctx, int(req.GetId()), taski.Update{Title: &title, Description: &description})
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(task), nil
+}
+
+func (g *grpcService) DeleteTask(ctx context.Context, req *taskiv1.DeleteTaskRequest) (*taskiv1.Task, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	_, err := g.s.get(ctx, int(req.GetId()), false)
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	task, err := g.s.repo.Delete(ctx, int(req.GetId()))
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(task), nil
+}
+
+func (g *grpcService) RestoreTask(ctx context.Context, req *taskiv1.RestoreTaskRequest) (*taskiv1.Task, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	task, err := g.s.repo.Restore(ctx, int(req.GetId()))
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(task), nil
+}
+
+func (g *grpcService) RestoreAll(ctx context.Context, req *taskiv1.RestoreAllRequest) (*taskiv1.Database, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	err := g.s.repo.RestoreAll(ctx)
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return g.snapshot(false, filter.Filter{})
+}
+
+func (g *grpcService) ExportTasks(ctx context.Context, req *taskiv1.ExportTasksRequest) (*taskiv1.ExportTasksResponse, error) {
+	taskFilter, err := g.parseFilter(req.GetWhere())
+
+	if err != nil {
+		return nil, err
+	}
+
+	g.s.mu.RLock()
+	db, err := io.ReadAll(g.s.repo.FileName())
+	g.s.mu.RUnlock()
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	var tasks []io.Task
+
+	for _, task := range db.Tasks {
+		if (!task.IsDeleted || req.GetIncludeTrash()) && taskFilter.Match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	var content bytes.Buffer
+
+	err = export.Write(&content, req.GetFormat(), tasks)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &taskiv1.ExportTasksResponse{Content: content.Bytes()}, nil
+}
+
+func (g *grpcService) WatchTasks(req *taskiv1.WatchTasksRequest, stream grpc.ServerStreamingServer[taskiv1.Database]) error {
+	last := ""
+	ticker := time.NewTicker(g.s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		current := g.s.fileVersion()
+
+		if current != last {
+			last = current
+
+			g.s.mu.RLock()
+			db, err := g.snapshot(req.GetIncludeTrash(), filter.Filter{})
+			g.s.mu.RUnlock()
+
+			if err != nil {
+				return err
+			}
+
+			err = stream.Send(db)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseFilter parses a filter with the custom fields of the database.
+func (g *grpcService) parseFilter(where string) (filter.Filter, error) {
+	fields, err := uda.Load(g.s.repo.FileName())
+
+	if err != nil {
+		return filter.Filter{}, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	taskFilter, err := filter.ParseFields(where, fields)
+
+	if err != nil {
+		return filter.Filter{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return taskFilter, nil
+}
+
+// snapshot reads the database as a message. The caller must hold the lock.
+func (g *grpcService) snapshot(includeTrash bool, taskFilter filter.Filter) (*taskiv1.Database, error) {
+	db, err := io.ReadAll(g.s.repo.FileName())
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	result := &taskiv1.Database{Size: int32(db.Size)}
+
+	for _, task := range db.Tasks {
+		if (!task.IsDeleted || includeTrash) && taskFilter.Match(task) {
+			result.Tasks = append(result.Tasks, toProto(task))
+		}
+	}
+
+	return result, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, io.ErrNotFound), errors.Is(err, io.ErrOutOfBounds):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errBadID), errors.Is(err, io.ErrInvalid), errors.Is(err, io.ErrNoChange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, io.ErrLocked):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toProto(task io.Task) *taskiv1.Task {
+	result := &taskiv1.Task{
+		Id:          int32(task.ID),
+		Uid:         task.UID,
+		Title:       task.Title,
+		Description: task.Description,
+		Date:        timestamppb.New(task.Date),
+		Priority:    task.Priority,
+		Recurrence:  task.Recurrence,
+		Remind:      task.Remind,
+		IsDeleted:   task.IsDeleted,
+		Tags:        task.Tags,
+		Contexts:    task.Contexts,
+		Custom:      task.Custom,
+		Status:      task.Status,
+	}
+
+	if task.Due != nil {
+		result.Due = timestamppb.New(*task.Due)
+	}
+
+	if task.DeletedAt != nil {
+		result.DeletedAt = timestamppb.New(*task.DeletedAt)
+	}
+
+	for _, note := range task.Notes {
+		result.Notes = append(result.Notes, &taskiv1.Note{Time: timestamppb.New(note.Time), Text: note.Text})
+	}
+
+	return result
+}
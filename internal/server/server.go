@@ -0,0 +1,374 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+var (
+	errBadID   = errors.New("invalid id")
+	errNoToken = errors.New("an API token is required")
+)
+
+// Server exposes a taski.Repository over HTTP. Every request goes
+// through one lock, so concurrent clients never interleave a read-modify-
+// write of the database file.
+type Server struct {
+	// PollInterval is how often the database file is checked for changes
+	// made outside the server.
+	PollInterval time.Duration
+
+	repo  *taski.Repository
+	token string
+	mu    sync.RWMutex
+}
+
+type taskInput struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Due         *string   `json:"due"`
+	Priority    *string   `json:"priority"`
+	Tags        *[]string `json:"tags"`
+	Contexts    *[]string `json:"contexts"`
+	Status      *string   `json:"status"`
+}
+
+func New(fileName string, token string) (*Server, error) {
+	if token == "" {
+		return nil, errNoToken
+	}
+
+	return &Server{PollInterval: 500 * time.Millisecond, repo: taski.Open(fileName), token: token}, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+
+	api.HandleFunc("GET /tasks", s.listTasks)
+	api.HandleFunc("POST /tasks", s.addTask)
+	api.HandleFunc("GET /tasks/{id}", s.getTask)
+	api.HandleFunc("PATCH /tasks/{id}", s.changeTask)
+	api.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+	api.HandleFunc("POST /tasks/{id}/restore", s.restoreTask)
+	api.HandleFunc("GET /trash", s.listTrash)
+	api.HandleFunc("GET /events", s.events)
+
+	mux := http.NewServeMux()
+	authenticated := s.authenticate(api)
+
+	mux.Handle("/tasks", authenticated)
+	mux.Handle("/tasks/", authenticated)
+	mux.Handle("/trash", authenticated)
+	mux.Handle("/events", authenticated)
+	mux.Handle("/", staticHandler())
+
+	return mux
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		// EventSource cannot set headers, so the event stream also accepts
+		// the token as a query parameter.
+		if !found && r.URL.Path == "/events" {
+			token, found = r.URL.Query().Get("token"), true
+		}
+
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="taski"`)
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	db, err := io.ReadTask(s.repo.FileName())
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nonNil(db.Tasks))
+}
+
+func (s *Server) listTrash(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	db, err := io.ReadAll(s.repo.FileName())
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	var trash []io.Task
+
+	for _, task := range db.Tasks {
+		if task.IsDeleted {
+			trash = append(trash, task)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, nonNil(trash))
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, err := s.lookup(r, false)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) addTask(w http.ResponseWriter, r *http.Request) {
+	var input taskInput
+
+	err := json.NewDecoder(r.Body).Decode(&input)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decoding body: %w", err))
+		return
+	}
+
+	if input.Title == nil || *input.Title == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+
+	task := io.Task{Title: *input.Title}
+
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+
+	err = applyDetails(&task, input)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created, err := s.repo.Add(r.Context(), task)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) changeTask(w http.ResponseWriter, r *http.Request) {
+	var input taskInput
+
+	err := json.NewDecoder(r.Body).Decode(&input)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decoding body: %w", err))
+		return
+	}
+
+	if input.Due != nil || input.Priority != nil || input.Tags != nil || input.Contexts != nil {
+		writeError(w, http.StatusBadRequest, errors.New("only title, description and status can be changed"))
+		return
+	}
+
+	if input.Status != nil && !io.ValidStatus(*input.Status) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid status %q, expected one lowercase word such as pending, doing or done", *input.Status))
+		return
+	}
+
+	id, err := pathID(r)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.repo.Update(r.Context(), id, taski.Update{Title: input.Title, Description: input.Description, Status: input.Status})
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.lookup(r, false)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	_, err = s.repo.Delete(r.Context(), task.ID)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) restoreTask(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.repo.Restore(r.Context(), id)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, task)
+}
+
+// lookup resolves the {id} path value. Tasks in trash are only found when
+// includeTrash is set. The caller must hold the lock.
+func (s *Server) lookup(r *http.Request, includeTrash bool) (io.Task, error) {
+	id, err := pathID(r)
+
+	if err != nil {
+		return io.Task{}, err
+	}
+
+	return s.get(r.Context(), id, includeTrash)
+}
+
+// get reads a task from the repository, which also returns tasks in trash.
+func (s *Server) get(ctx context.Context, id int, includeTrash bool) (io.Task, error) {
+	task, err := s.repo.Get(ctx, id)
+
+	if err != nil {
+		return io.Task{}, err
+	}
+
+	if task.IsDeleted && !includeTrash {
+		return io.Task{}, fmt.Errorf("task %d is in trash: %w", id, io.ErrNotFound)
+	}
+
+	return task, nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", errBadID, r.PathValue("id"))
+	}
+
+	return id, nil
+}
+
+func applyDetails(task *io.Task, input taskInput) error {
+	if input.Tags != nil {
+		task.Tags = *input.Tags
+	}
+
+	if input.Contexts != nil {
+		task.Contexts = *input.Contexts
+	}
+
+	if input.Priority != nil {
+		if !io.ValidPriority(*input.Priority) {
+			return fmt.Errorf("invalid priority %q", *input.Priority)
+		}
+
+		task.Priority = *input.Priority
+	}
+
+	if input.Due != nil && *input.Due != "" {
+		due, err := time.Parse(time.RFC3339, *input.Due)
+
+		if err != nil {
+			return fmt.Errorf("invalid due date %q, expected RFC 3339", *input.Due)
+		}
+
+		task.Due = &due
+	}
+
+	return nil
+}
+
+// errorStatus maps an error from a lookup or the io layer to its HTTP
+// status. Hooks reject changes with io.ErrInvalid, which is the client's to
+// fix, not the server's.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errBadID), errors.Is(err, io.ErrNoChange):
+		return http.StatusBadRequest
+	case errors.Is(err, io.ErrNotFound), errors.Is(err, io.ErrOutOfBounds):
+		return http.StatusNotFound
+	case errors.Is(err, io.ErrInvalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, io.ErrLocked):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func nonNil(tasks []io.Task) []io.Task {
+	if tasks == nil {
+		return []io.Task{}
+	}
+
+	return tasks
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+func staticHandler() http.Handler {
+	root, err := fs.Sub(webFiles, "web")
+
+	if err != nil {
+		panic(fmt.Sprintf("embedded web files: %v", err))
+	}
+
+	return http.FileServerFS(root)
+}
+
+// events streams a "change" server-sent event whenever the database file
+// is modified, whether by this server, the CLI or any other process.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	last := s.fileVersion()
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			current := s.fileVersion()
+
+			if current == last {
+				continue
+			}
+
+			last = current
+			fmt.Fprint(w, "event: change\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) fileVersion() string {
+	info, err := os.Stat(s.repo.FileName())
+
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
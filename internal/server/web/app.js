@@ -0,0 +1,190 @@
+"use strict";
+
+const state = { token: localStorage.getItem("taski-token") || "", tasks: [], trash: [], events: null };
+const $ = (id) => document.getElementById(id);
+
+async function api(method, path, body) {
+  const response = await fetch(path, {
+    method,
+    headers: { "Authorization": "Bearer " + state.token, "Content-Type": "application/json" },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+
+  if (response.status === 401) {
+    logout();
+    throw new Error("unauthorized");
+  }
+
+  if (response.status === 204) {
+    return null;
+  }
+
+  const data = await response.json();
+
+  if (!response.ok) {
+    throw new Error(data.error || response.statusText);
+  }
+
+  return data;
+}
+
+async function refresh() {
+  const [tasks, trash] = await Promise.all([api("GET", "/tasks"), api("GET", "/trash")]);
+  state.tasks = tasks;
+  state.trash = trash;
+  render();
+}
+
+function render() {
+  const query = $("filter").value.trim().toLowerCase();
+  const showTrash = $("show-trash").checked;
+  const list = $("tasks");
+  const template = $("task-template");
+  const tasks = showTrash ? state.tasks.concat(state.trash) : state.tasks;
+  const visible = tasks.filter((task) =>
+    !query || task.title.toLowerCase().includes(query) || task.description.toLowerCase().includes(query));
+
+  list.replaceChildren();
+  $("empty").hidden = visible.length > 0;
+
+  for (const task of visible) {
+    const item = template.content.firstElementChild.cloneNode(true);
+    item.classList.toggle("deleted", task.is_deleted);
+    item.classList.toggle("completed", task.status === "done");
+    item.querySelector(".title").textContent = task.title;
+    item.querySelector(".description").textContent = task.description;
+    item.querySelector(".meta").textContent = meta(task);
+    item.querySelector(".done").checked = task.status === "done";
+    item.querySelector(".done").disabled = task.is_deleted;
+    item.querySelector(".edit").hidden = task.is_deleted;
+    item.querySelector(".delete").hidden = task.is_deleted;
+    item.querySelector(".restore").hidden = !task.is_deleted;
+
+    item.querySelector(".done").addEventListener("change", (event) =>
+      act(api("PATCH", "/tasks/" + task.id, { status: event.target.checked ? "done" : "pending" })));
+    item.querySelector(".delete").addEventListener("click", () => act(api("DELETE", "/tasks/" + task.id)));
+    item.querySelector(".restore").addEventListener("click", () => act(api("POST", "/tasks/" + task.id + "/restore")));
+    item.querySelector(".edit").addEventListener("click", () => toggleEditor(item, task, true));
+    item.querySelector(".cancel").addEventListener("click", () => toggleEditor(item, task, false));
+    item.querySelector(".editor").addEventListener("submit", (event) => {
+      event.preventDefault();
+      act(api("PATCH", "/tasks/" + task.id, {
+        title: item.querySelector(".edit-title").value,
+        description: item.querySelector(".edit-description").value,
+      }));
+    });
+
+    list.appendChild(item);
+  }
+}
+
+function meta(task) {
+  const parts = ["#" + task.id, new Date(task.date).toLocaleString()];
+
+  if (task.due) {
+    parts.push("due " + new Date(task.due).toLocaleString());
+  }
+
+  if (task.priority) {
+    parts.push(task.priority + " priority");
+  }
+
+  const labels = (task.tags || []).map((tag) => "+" + tag)
+    .concat((task.contexts || []).map((context) => "@" + context));
+
+  if (labels.length > 0) {
+    parts.push(labels.join(" "));
+  }
+
+  return parts.join(" · ");
+}
+
+function toggleEditor(item, task, open) {
+  item.querySelector(".view").hidden = open;
+  item.querySelector(".editor").hidden = !open;
+
+  if (open) {
+    item.querySelector(".edit-title").value = task.title;
+    item.querySelector(".edit-description").value = task.description;
+    item.querySelector(".edit-title").focus();
+  }
+}
+
+async function act(request) {
+  try {
+    await request;
+    await refresh();
+  } catch (error) {
+    if (error.message !== "unauthorized") {
+      alert(error.message);
+    }
+  }
+}
+
+function listen() {
+  if (state.events) {
+    state.events.close();
+  }
+
+  state.events = new EventSource("/events?token=" + encodeURIComponent(state.token));
+  state.events.addEventListener("open", () => setStatus(true));
+  state.events.addEventListener("error", () => setStatus(false));
+  state.events.addEventListener("change", () => refresh().catch(() => {}));
+}
+
+function setStatus(live) {
+  $("status").textContent = live ? "live" : "offline";
+  $("status").classList.toggle("live", live);
+}
+
+async function login(token) {
+  state.token = token;
+
+  try {
+    await refresh();
+  } catch (error) {
+    return;
+  }
+
+  localStorage.setItem("taski-token", token);
+  $("login").hidden = true;
+  $("app").hidden = false;
+  listen();
+}
+
+function logout() {
+  localStorage.removeItem("taski-token");
+
+  if (state.events) {
+    state.events.close();
+  }
+
+  setStatus(false);
+  $("app").hidden = true;
+  $("login").hidden = false;
+}
+
+$("login-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  login($("token").value);
+});
+
+$("add-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const title = $("add-title");
+  const description = $("add-description");
+
+  act(api("POST", "/tasks", { title: title.value, description: description.value }).then(() => {
+    title.value = "";
+    description.value = "";
+  }));
+});
+
+$("filter").addEventListener("input", render);
+$("show-trash").addEventListener("change", render);
+
+if (state.token) {
+  login(state.token);
+} else {
+  logout();
+}
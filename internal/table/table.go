@@ -0,0 +1,172 @@
+// Package table lays out rows of text as aligned columns for a terminal,
+// truncating the widest flexible columns to fit a width and styling cells
+// with ANSI escape codes.
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// minFlexWidth is the narrowest a flexible column is truncated to.
+const minFlexWidth = 8
+
+type Column struct {
+	Header string
+	// Flex columns give up width, with "…", when the table does not fit.
+	Flex bool
+	// Optional columns are left out when no row has a value in them.
+	Optional bool
+}
+
+// Cell is one value; Style holds ANSI SGR codes such as "1;31".
+type Cell struct {
+	Text  string
+	Style string
+}
+
+type Table struct {
+	Columns []Column
+	Rows    [][]Cell
+}
+
+func (t *Table) Append(cells ...Cell) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render writes the table to w. A width of zero or less means no limit;
+// header adds a row of column names.
+func (t *Table) Render(w io.Writer, width int, color bool, header bool) error {
+	t.dropEmpty()
+	widths := make([]int, len(t.Columns))
+
+	for i, column := range t.Columns {
+		if header {
+			widths[i] = length(column.Header)
+		}
+
+		for _, row := range t.Rows {
+			widths[i] = max(widths[i], length(row[i].Text))
+		}
+	}
+
+	if width > 0 {
+		shrink(widths, t.Columns, width)
+	}
+
+	var b strings.Builder
+
+	if header {
+		cells := make([]Cell, len(t.Columns))
+
+		for i, column := range t.Columns {
+			cells[i] = Cell{Text: column.Header, Style: "1"}
+		}
+
+		writeRow(&b, cells, widths, color)
+	}
+
+	for _, row := range t.Rows {
+		writeRow(&b, row, widths, color)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	if err != nil {
+		return fmt.Errorf("writing table: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Table) dropEmpty() {
+	for i := len(t.Columns) - 1; i >= 0; i-- {
+		if !t.Columns[i].Optional || t.hasValue(i) {
+			continue
+		}
+
+		t.Columns = append(t.Columns[:i:i], t.Columns[i+1:]...)
+
+		for r, row := range t.Rows {
+			t.Rows[r] = append(row[:i:i], row[i+1:]...)
+		}
+	}
+}
+
+func (t *Table) hasValue(column int) bool {
+	for _, row := range t.Rows {
+		if row[column].Text != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shrink narrows the widest flexible column, one character at a time, until
+// the table fits or every flexible column is at its minimum.
+func shrink(widths []int, columns []Column, width int) {
+	total := 2 * (len(widths) - 1)
+
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > width {
+		widest := -1
+
+		for i, column := range columns {
+			if column.Flex && widths[i] > minFlexWidth && (widest == -1 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+
+		if widest == -1 {
+			return
+		}
+
+		widths[widest]--
+		total--
+	}
+}
+
+func writeRow(b *strings.Builder, cells []Cell, widths []int, color bool) {
+	var line strings.Builder
+
+	for i, cell := range cells {
+		text := truncate(cell.Text, widths[i])
+		padding := ""
+
+		if i < len(cells)-1 {
+			padding = strings.Repeat(" ", widths[i]-length(text)+2)
+		}
+
+		if color && cell.Style != "" && text != "" {
+			text = "\x1b[" + cell.Style + "m" + text + "\x1b[0m"
+		}
+
+		line.WriteString(text + padding)
+	}
+
+	b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+}
+
+func truncate(text string, width int) string {
+	if length(text) <= width {
+		return text
+	}
+
+	if width <= 1 {
+		return "…"
+	}
+
+	runes := []rune(text)
+
+	return string(runes[:width-1]) + "…"
+}
+
+func length(text string) int {
+	return utf8.RuneCountInString(text)
+}
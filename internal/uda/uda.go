@@ -0,0 +1,315 @@
+// Package uda handles the custom fields declared in config.json, the user
+// defined attributes of a task. Values are stored in Task.Custom as text in
+// a canonical form per type, so that they survive every export and sync,
+// and are parsed back by type to be compared and sorted:
+//
+//	string    any single line of text
+//	number    a decimal number, such as 3 or 2.5
+//	date      YYYY-MM-DD, or YYYY-MM-DDTHH:MM with a time
+//	enum      one of the values of the field
+//	duration  weeks, days, hours and minutes, stored as e.g. 1d4h
+package uda
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/quickadd"
+	"github.com/tristnaja/taski/internal/remind"
+)
+
+const (
+	String   = "string"
+	Number   = "number"
+	Date     = "date"
+	Enum     = "enum"
+	Duration = "duration"
+)
+
+var Types = []string{String, Number, Date, Enum, Duration}
+
+// reserved are names taken by the fields every task has, in filters, sort
+// keys, front matter and CSV columns.
+var reserved = []string{"id", "uid", "title", "desc", "description", "note", "notes", "date", "due", "priority", "recurrence", "remind", "tag", "tags", "context", "contexts", "status", "deleted", "is_deleted", "deleted_at"}
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Fields are the custom fields of a database by name.
+type Fields map[string]config.Field
+
+// Load reads and checks the custom fields of a database.
+func Load(fileName string) (Fields, error) {
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := Fields(cfg.Fields)
+
+	err = fields.Check()
+
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", config.PathFor(fileName), err)
+	}
+
+	return fields, nil
+}
+
+// Check reports the first field with an invalid name, type or enum values.
+func (f Fields) Check() error {
+	for _, name := range f.Names() {
+		field := f[name]
+
+		switch {
+		case !validName.MatchString(name):
+			return fmt.Errorf("invalid field name %q, expected lowercase letters, digits, - and _", name)
+		case slices.Contains(reserved, name):
+			return fmt.Errorf("field name %q is taken by a built-in field", name)
+		case !slices.Contains(Types, field.Type):
+			return fmt.Errorf("field %q has invalid type %q, usable: %s", name, field.Type, strings.Join(Types, ", "))
+		case field.Type == Enum && len(field.Values) == 0:
+			return fmt.Errorf("enum field %q has no values", name)
+		case field.Type != Enum && len(field.Values) > 0:
+			return fmt.Errorf("field %q has values but is not an enum", name)
+		}
+	}
+
+	return nil
+}
+
+// Names returns the field names in sorted order.
+func (f Fields) Names() []string {
+	names := make([]string, 0, len(f))
+
+	for name := range f {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Columns returns the fields shown in the table of taski view.
+func (f Fields) Columns() []string {
+	var names []string
+
+	for _, name := range f.Names() {
+		if f[name].Column {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Normalize checks value against the type of the field and returns its
+// canonical form.
+func (f Fields) Normalize(name string, value string, now time.Time) (string, error) {
+	field, found := f[name]
+
+	if !found {
+		return "", f.unknown(name)
+	}
+
+	value = strings.TrimSpace(value)
+
+	switch field.Type {
+	case Number:
+		number, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return "", fmt.Errorf("invalid number %q for %v", value, name)
+		}
+
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case Date:
+		date, err := quickadd.ParseDate(value, now)
+
+		if err != nil {
+			return "", fmt.Errorf("%v: %w", name, err)
+		}
+
+		return formatDate(date), nil
+	case Enum:
+		for _, choice := range field.Values {
+			if strings.EqualFold(choice, value) {
+				return choice, nil
+			}
+		}
+
+		return "", fmt.Errorf("invalid %v %q, usable: %s", name, value, strings.Join(field.Values, ", "))
+	case Duration:
+		duration, err := remind.ParseDuration(strings.ToLower(value))
+
+		if err != nil {
+			return "", fmt.Errorf("%v: %w", name, err)
+		}
+
+		return remind.FormatDuration(duration), nil
+	default:
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("%v must be a single line", name)
+		}
+
+		return value, nil
+	}
+}
+
+// Assign checks "name=value" assignments, as given to --set, and returns
+// the canonical value of each field. An empty value removes the field, so
+// it is accepted for fields that are no longer declared too.
+func (f Fields) Assign(assignments []string, now time.Time) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid field assignment %q, expected name=value", assignment)
+		}
+
+		if strings.TrimSpace(value) == "" {
+			values[name] = ""
+			continue
+		}
+
+		value, err := f.Normalize(name, value, now)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// Apply stores the values returned by Assign in task, removing the fields
+// whose value is empty.
+func Apply(task *io.Task, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+
+	custom := maps.Clone(task.Custom)
+
+	if custom == nil {
+		custom = make(map[string]string)
+	}
+
+	for name, value := range values {
+		if value == "" {
+			delete(custom, name)
+		} else {
+			custom[name] = value
+		}
+	}
+
+	if len(custom) == 0 {
+		custom = nil
+	}
+
+	task.Custom = custom
+}
+
+// Compare orders two canonical values of a field by its type. Empty values
+// sort last, and values that do not parse, such as those stored before the
+// type of the field changed, sort after the ones that do.
+func (f Fields) Compare(name string, a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	x, xErr := f.key(name, a)
+	y, yErr := f.key(name, b)
+
+	switch {
+	case xErr != nil && yErr != nil:
+		return strings.Compare(a, b)
+	case xErr != nil:
+		return 1
+	case yErr != nil:
+		return -1
+	}
+
+	if c := cmp.Compare(x, y); c != 0 {
+		return c
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// key turns a value into a number to order by: the value of a number, the
+// Unix time of a date, the length of a duration and the position of an
+// enum value. Strings have no key and compare as text.
+func (f Fields) key(name string, value string) (float64, error) {
+	field := f[name]
+
+	switch field.Type {
+	case Number:
+		return strconv.ParseFloat(value, 64)
+	case Date:
+		for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+			date, err := time.ParseInLocation(layout, value, time.Local)
+
+			if err == nil {
+				return float64(date.Unix()), nil
+			}
+		}
+
+		return 0, fmt.Errorf("invalid date %q", value)
+	case Duration:
+		duration, err := remind.ParseDuration(value)
+
+		return float64(duration), err
+	case Enum:
+		index := slices.Index(field.Values, value)
+
+		if index < 0 {
+			return 0, fmt.Errorf("invalid %v %q", name, value)
+		}
+
+		return float64(index), nil
+	default:
+		return 0, nil
+	}
+}
+
+// Ordered reports whether the values of a field can be compared with < and
+// > in filters.
+func (f Fields) Ordered(name string) bool {
+	return f[name].Type != String
+}
+
+func (f Fields) unknown(name string) error {
+	if len(f) == 0 {
+		return fmt.Errorf("unknown field %q, declare custom fields under \"fields\" in config.json", name)
+	}
+
+	return fmt.Errorf("unknown field %q, usable: %s", name, strings.Join(f.Names(), ", "))
+}
+
+func formatDate(date time.Time) string {
+	if date.Hour() == 0 && date.Minute() == 0 {
+		return date.Format("2006-01-02")
+	}
+
+	return date.Format("2006-01-02T15:04")
+}
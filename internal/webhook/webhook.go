@@ -0,0 +1,295 @@
+// Package webhook posts task events to the URLs configured in config.json.
+// Events are first written to an outbox file next to the database and only
+// removed from it once the endpoint accepted them, so nothing is lost while
+// an endpoint is down; failed deliveries are retried with a growing delay.
+//
+// Payloads are the JSON encoded io.Event. With a secret configured, the
+// X-Taski-Signature header carries "sha256=" and the hex HMAC-SHA256 of the
+// body keyed with the secret.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+const (
+	// FirstRetry is the delay before the first retry; it doubles with each
+	// failed attempt up to MaxRetry.
+	FirstRetry = 30 * time.Second
+	MaxRetry   = 6 * time.Hour
+)
+
+// Delivery is one event waiting in the outbox for one URL.
+type Delivery struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Event       io.Event  `json:"event"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+type Outbox struct {
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Result counts what a Flush did; Errors holds why deliveries failed.
+type Result struct {
+	Delivered int
+	Pending   int
+	Errors    []error
+}
+
+// OutboxPath returns the outbox file that belongs to a database.
+func OutboxPath(fileName string) string {
+	return filepath.Join(filepath.Dir(fileName), "outbox.json")
+}
+
+// Enqueue adds the events to the outbox of the database, once for every
+// configured webhook that wants them.
+func Enqueue(fileName string, events []io.Event) error {
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Webhooks) == 0 {
+		return nil
+	}
+
+	return update(OutboxPath(fileName), func(outbox *Outbox) {
+		for _, event := range events {
+			for _, hook := range cfg.Webhooks {
+				if wants(hook, event.Type) {
+					outbox.Deliveries = append(outbox.Deliveries, Delivery{
+						ID:          io.NewUID(),
+						URL:         hook.URL,
+						Event:       event,
+						NextAttempt: event.Time,
+					})
+				}
+			}
+		}
+	})
+}
+
+// Flush posts the deliveries that are due, or all of them when force is
+// set, in order. The outbox is not locked while posting, so writes to the
+// database are not held up by a slow endpoint; a delivery may therefore
+// arrive twice and receivers should skip X-Taski-Delivery ids they have
+// seen. Deliveries to a URL that is no longer configured are dropped.
+func Flush(ctx context.Context, fileName string, client *http.Client, now time.Time, force bool) (Result, error) {
+	var result Result
+
+	cfg, err := config.Load(config.PathFor(fileName))
+
+	if err != nil {
+		return result, err
+	}
+
+	outbox, err := load(OutboxPath(fileName))
+
+	if err != nil || len(outbox.Deliveries) == 0 {
+		return result, err
+	}
+
+	delivered := map[string]bool{}
+	failed := map[string]string{}
+	down := map[string]bool{}
+
+	for _, delivery := range outbox.Deliveries {
+		hook, found := find(cfg, delivery.URL)
+
+		switch {
+		case !found:
+			delivered[delivery.ID] = true
+			continue
+		case delivery.NextAttempt.After(now) && !force:
+			down[delivery.URL] = true
+			continue
+		case down[delivery.URL]:
+			// Keep the events to one URL in order behind a failed one.
+			continue
+		}
+
+		err = Post(ctx, client, hook, delivery)
+
+		if err != nil {
+			failed[delivery.ID] = err.Error()
+			down[delivery.URL] = true
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+
+		delivered[delivery.ID] = true
+		result.Delivered++
+	}
+
+	err = update(OutboxPath(fileName), func(outbox *Outbox) {
+		outbox.Deliveries = slices.DeleteFunc(outbox.Deliveries, func(delivery Delivery) bool {
+			return delivered[delivery.ID]
+		})
+
+		for index := range outbox.Deliveries {
+			delivery := &outbox.Deliveries[index]
+
+			if message, found := failed[delivery.ID]; found {
+				delivery.Attempts++
+				delivery.LastError = message
+				delivery.NextAttempt = now.Add(Backoff(delivery.Attempts))
+			}
+		}
+
+		result.Pending = len(outbox.Deliveries)
+	})
+
+	return result, err
+}
+
+// Post sends one delivery, signed with the secret of the webhook.
+func Post(ctx context.Context, client *http.Client, hook config.Webhook, delivery Delivery) error {
+	body, err := json.Marshal(delivery.Event)
+
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "taski-webhook")
+	request.Header.Set("X-Taski-Event", delivery.Event.Type)
+	request.Header.Set("X-Taski-Delivery", delivery.ID)
+
+	if hook.Secret != "" {
+		request.Header.Set("X-Taski-Signature", Sign(hook.Secret, body))
+	}
+
+	response, err := client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("posting to %v: %w", hook.URL, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("posting to %v: answered %v", hook.URL, response.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the X-Taski-Signature header value for a body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is the delay before retrying a delivery that failed attempts
+// times.
+func Backoff(attempts int) time.Duration {
+	delay := FirstRetry
+
+	for i := 1; i < attempts && delay < MaxRetry; i++ {
+		delay *= 2
+	}
+
+	return min(delay, MaxRetry)
+}
+
+// Load reads the outbox of a database.
+func Load(fileName string) (Outbox, error) {
+	return load(OutboxPath(fileName))
+}
+
+func wants(hook config.Webhook, eventType string) bool {
+	return hook.URL != "" && (len(hook.Events) == 0 || slices.Contains(hook.Events, eventType))
+}
+
+func find(cfg config.Config, url string) (config.Webhook, bool) {
+	for _, hook := range cfg.Webhooks {
+		if hook.URL == url {
+			return hook, true
+		}
+	}
+
+	return config.Webhook{}, false
+}
+
+func load(path string) (Outbox, error) {
+	var outbox Outbox
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return outbox, nil
+		}
+
+		return Outbox{}, fmt.Errorf("reading outbox: %w", err)
+	}
+
+	err = json.Unmarshal(content, &outbox)
+
+	if err != nil {
+		return Outbox{}, fmt.Errorf("decoding outbox: %w", err)
+	}
+
+	return outbox, nil
+}
+
+// update changes the outbox under its lock.
+func update(path string, fn func(outbox *Outbox)) error {
+	unlock, err := io.LockFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	outbox, err := load(path)
+
+	if err != nil {
+		return err
+	}
+
+	fn(&outbox)
+
+	content, err := json.MarshalIndent(outbox, "", "\t")
+
+	if err != nil {
+		return fmt.Errorf("encoding outbox: %w", err)
+	}
+
+	err = os.WriteFile(path, content, 0644)
+
+	if err != nil {
+		return fmt.Errorf("writing outbox: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package client is a Go client for the taski gRPC API served by
+// "taski serve --grpc-addr".
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	taskiv1 "github.com/tristnaja/taski/api/taski/v1"
+)
+
+type Client struct {
+	conn *grpc.ClientConn
+	api  taskiv1.TaskiClient
+}
+
+// Dial connects to a taski server. The connection is plaintext unless
+// opts carries other transport credentials.
+func Dial(addr string, token string, opts ...grpc.DialOption) (*Client, error) {
+	options := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(bearer(token)),
+	}, opts...)
+
+	conn, err := grpc.NewClient(addr, options...)
+
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+
+	return &Client{conn: conn, api: taskiv1.NewTaskiClient(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// API exposes the generated client for calls not wrapped here.
+func (c *Client) API() taskiv1.TaskiClient {
+	return c.api
+}
+
+func (c *Client) List(ctx context.Context, includeTrash bool) ([]*taskiv1.Task, error) {
+	db, err := c.api.ListTasks(ctx, &taskiv1.ListTasksRequest{IncludeTrash: includeTrash})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetTasks(), nil
+}
+
+func (c *Client) Get(ctx context.Context, id int) (*taskiv1.Task, error) {
+	return c.api.GetTask(ctx, &taskiv1.GetTaskRequest{Id: int32(id)})
+}
+
+func (c *Client) Add(ctx context.Context, req *taskiv1.AddTaskRequest) (*taskiv1.Task, error) {
+	return c.api.AddTask(ctx, req)
+}
+
+// Change updates the title and/or description; empty values are kept.
+func (c *Client) Change(ctx context.Context, id int, title string, description string) (*taskiv1.Task, error) {
+	return c.api.ChangeTask(ctx, &taskiv1.ChangeTaskRequest{Id: int32(id), Title: title, Description: description})
+}
+
+func (c *Client) Delete(ctx context.Context, id int) (*taskiv1.Task, error) {
+	return c.api.DeleteTask(ctx, &taskiv1.DeleteTaskRequest{Id: int32(id)})
+}
+
+func (c *Client) Restore(ctx context.Context, id int) (*taskiv1.Task, error) {
+	return c.api.RestoreTask(ctx, &taskiv1.RestoreTaskRequest{Id: int32(id)})
+}
+
+func (c *Client) RestoreAll(ctx context.Context) (*taskiv1.Database, error) {
+	return c.api.RestoreAll(ctx, &taskiv1.RestoreAllRequest{})
+}
+
+func (c *Client) Export(ctx context.Context, format string, includeTrash bool) ([]byte, error) {
+	resp, err := c.api.ExportTasks(ctx, &taskiv1.ExportTasksRequest{Format: format, IncludeTrash: includeTrash})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetContent(), nil
+}
+
+// Watch calls fn with the current database and again after every change
+// until ctx is cancelled or fn returns an error.
+func (c *Client) Watch(ctx context.Context, includeTrash bool, fn func(*taskiv1.Database) error) error {
+	stream, err := c.api.WatchTasks(ctx, &taskiv1.WatchTasksRequest{IncludeTrash: includeTrash})
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		db, err := stream.Recv()
+
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = fn(db)
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type bearer string
+
+func (b bearer) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+func (b bearer) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,359 @@
+// Package taski is the public Go API for a taski task database. It is what
+// the taski command itself is built on.
+package taski
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tristnaja/taski/internal/filter"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+)
+
+type (
+	Task     = io.Task
+	Note     = io.Note
+	Database = io.Database
+	// Event describes one change to a task; see Subscribe.
+	Event = io.Event
+)
+
+const (
+	PriorityHigh   = io.PriorityHigh
+	PriorityMedium = io.PriorityMedium
+	PriorityLow    = io.PriorityLow
+
+	StatusPending = io.StatusPending
+	StatusDone    = io.StatusDone
+
+	EventAdded    = io.EventAdded
+	EventChanged  = io.EventChanged
+	EventDeleted  = io.EventDeleted
+	EventRestored = io.EventRestored
+	EventPurged   = io.EventPurged
+)
+
+var (
+	// ErrOutOfBounds is returned for an id that does not exist in the
+	// database at all; the error is an *IndexError carrying the id.
+	ErrOutOfBounds = io.ErrOutOfBounds
+	// ErrNotFound is returned for a task that exists but is not available
+	// for the operation, such as a task in trash passed to Update.
+	ErrNotFound = io.ErrNotFound
+	// ErrNoChange is returned by Update when nothing would change.
+	ErrNoChange = io.ErrNoChange
+	// ErrInvalid is returned for tasks that fail validation.
+	ErrInvalid = io.ErrInvalid
+	// ErrLocked is returned when another process holds the database for
+	// longer than io.LockTimeout.
+	ErrLocked = io.ErrLocked
+	// ErrCorrupt is returned when the database file cannot be decoded.
+	ErrCorrupt = io.ErrCorrupt
+)
+
+type IndexError = io.IndexError
+
+// Repository manages the tasks stored in one database file. Methods check
+// the context before touching the file; a started write is never
+// interrupted halfway.
+type Repository struct {
+	fileName string
+}
+
+type ListOptions struct {
+	IncludeTrash bool
+	// Where is a filter expression as accepted by the --where flag.
+	Where string
+}
+
+// Update lists the fields to change; nil fields are left alone.
+type Update struct {
+	Title       *string
+	Description *string
+	// Status is validated like the one set by the done and move commands.
+	Status *string
+}
+
+// Subscribe registers fn to receive the events of every write to any
+// database, including purges by Purge and writes made by Transact. fn runs
+// while the database is still locked and should return quickly.
+func Subscribe(fn func(fileName string, events []Event)) {
+	io.Subscribe(fn)
+}
+
+// Guard registers fn to check every task a write adds, changes, deletes or
+// restores before it is stored. fn returns the task to store or an error
+// that cancels the write; merges, imports and Purge are not guarded.
+func Guard(fn func(fileName string, event Event) (Task, error)) {
+	io.Guard(fn)
+}
+
+func Open(fileName string) *Repository {
+	return &Repository{fileName: fileName}
+}
+
+func (r *Repository) FileName() string {
+	return r.fileName
+}
+
+// Add stores a new task and returns it with its assigned ID and UID.
+func (r *Repository) Add(ctx context.Context, task Task) (Task, error) {
+	if err := ctx.Err(); err != nil {
+		return Task{}, err
+	}
+
+	if task.Title == "" {
+		return Task{}, fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+
+	if !io.ValidPriority(task.Priority) {
+		return Task{}, fmt.Errorf("%w: priority %q, usable: high, medium, low", ErrInvalid, task.Priority)
+	}
+
+	if task.Date.IsZero() {
+		task.Date = time.Now()
+	}
+
+	if len(task.Custom) > 0 {
+		fields, err := uda.Load(r.fileName)
+
+		if err != nil {
+			return Task{}, err
+		}
+
+		custom := make(map[string]string, len(task.Custom))
+
+		for name, value := range task.Custom {
+			custom[name], err = fields.Normalize(name, value, time.Now())
+
+			if err != nil {
+				return Task{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+		}
+
+		task.Custom = custom
+	}
+
+	var stored *io.Database
+	var added Task
+
+	err := io.Transact(r.fileName, func(db *io.Database) error {
+		added = db.Add(task)
+		stored = db
+		return nil
+	})
+
+	if err != nil {
+		return Task{}, err
+	}
+
+	// The guards, such as hooks, may have changed the task after Add, so
+	// it is taken from the database as it was written.
+	return stored.Tasks[added.ID], nil
+}
+
+// Get returns any task, including one in trash.
+func (r *Repository) Get(ctx context.Context, id int) (Task, error) {
+	return r.find(ctx, id, true)
+}
+
+func (r *Repository) List(ctx context.Context, opts ListOptions) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fields, err := uda.Load(r.fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	taskFilter, err := filter.ParseFields(opts.Where, fields)
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing filter: %w", err)
+	}
+
+	db, err := io.ReadAll(r.fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+
+	for _, task := range db.Tasks {
+		if task.IsDeleted && !opts.IncludeTrash {
+			continue
+		}
+
+		if taskFilter.Match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id int, update Update) (Task, error) {
+	var title, description string
+
+	if update.Title != nil {
+		title = *update.Title
+	}
+
+	if update.Description != nil {
+		description = *update.Description
+	}
+
+	if title == "" && description == "" && update.Status == nil {
+		return Task{}, ErrNoChange
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Task{}, err
+	}
+
+	var stored *io.Database
+
+	err := io.Transact(r.fileName, func(db *io.Database) error {
+		task, err := db.Get(id)
+
+		if err != nil {
+			return err
+		}
+
+		if task.IsDeleted {
+			return fmt.Errorf("task %d is in trash: %w", id, ErrNotFound)
+		}
+
+		err = db.Update(id, func(task *Task) error {
+			if title != "" {
+				task.Title = title
+			}
+
+			if description != "" {
+				task.Description = description
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if update.Status != nil {
+			err = db.SetStatus(id, *update.Status)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		stored = db
+		return nil
+	})
+
+	if err != nil {
+		return Task{}, err
+	}
+
+	return stored.Tasks[id], nil
+}
+
+// Delete moves a task to trash.
+func (r *Repository) Delete(ctx context.Context, id int) (Task, error) {
+	_, err := r.find(ctx, id, true)
+
+	if err != nil {
+		return Task{}, fmt.Errorf("deleting task: %w", err)
+	}
+
+	err = io.RemoveTask(r.fileName, id)
+
+	if err != nil {
+		return Task{}, err
+	}
+
+	return r.find(context.WithoutCancel(ctx), id, true)
+}
+
+// Restore takes a task out of trash; restoring an active task is a no-op.
+func (r *Repository) Restore(ctx context.Context, id int) (Task, error) {
+	_, err := r.find(ctx, id, true)
+
+	if err != nil {
+		return Task{}, fmt.Errorf("restoring task: %w", err)
+	}
+
+	err = io.RestoreTask(r.fileName, id)
+
+	if err != nil {
+		return Task{}, err
+	}
+
+	return r.find(context.WithoutCancel(ctx), id, true)
+}
+
+func (r *Repository) RestoreAll(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return io.RestoreAll(r.fileName)
+}
+
+// Purge permanently removes tasks that have been in trash for longer than
+// retention and reports how many were removed.
+func (r *Repository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return io.CleanUp(r.fileName, retention)
+}
+
+// Transact applies fn to the whole database and saves the result in a
+// single write. If fn returns an error nothing is saved, so a batch of
+// changes made through the Database methods is all or nothing.
+func (r *Repository) Transact(ctx context.Context, fn func(db *Database) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return io.Transact(r.fileName, fn)
+}
+
+// Import adds or updates tasks matched by UID, as done by ical import.
+func (r *Repository) Import(ctx context.Context, tasks []Task) (added int, updated int, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	return io.ImportTasks(r.fileName, tasks)
+}
+
+func (r *Repository) find(ctx context.Context, id int, includeTrash bool) (Task, error) {
+	if err := ctx.Err(); err != nil {
+		return Task{}, err
+	}
+
+	db, err := io.ReadAll(r.fileName)
+
+	if err != nil {
+		return Task{}, err
+	}
+
+	if id < 0 || id >= len(db.Tasks) {
+		return Task{}, &IndexError{Index: id}
+	}
+
+	if db.Tasks[id].IsDeleted && !includeTrash {
+		return Task{}, fmt.Errorf("task %d is in trash: %w", id, ErrNotFound)
+	}
+
+	return db.Tasks[id], nil
+}
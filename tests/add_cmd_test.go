@@ -33,15 +33,52 @@ func TestRunAdd(t *testing.T) {
 			initialDB:        io.Database{Tasks: []io.Task{}},
 			expectedStdout:   "",
 			expectedStderr:   "Usage of add:|unfilled arguments",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
-			name:             "missing description",
+			name:             "without description",
 			args:             []string{"-t", "New Task"},
 			initialDB:        io.Database{Tasks: []io.Task{}},
-			expectedStdout:   "",
-			expectedStderr:   "Usage of add:|unfilled arguments",
-			expectedExitCode: 1,
+			expectedStdout:   "Added New Task:",
+			expectedInDB:     "New Task",
+			expectedExitCode: 0,
+		},
+		{
+			name:             "quick add",
+			args:             []string{"Buy milk +errands @home due:2026-03-01 !high"},
+			initialDB:        io.Database{Tasks: []io.Task{}},
+			expectedStdout:   "Labels: +errands @home",
+			expectedInDB:     "Buy milk",
+			expectedExitCode: 0,
+		},
+		{
+			name:             "quick add with flags after text",
+			args:             []string{"Call", "plumber", "-d", "about the leak"},
+			initialDB:        io.Database{Tasks: []io.Task{}},
+			expectedStdout:   "Description: about the leak",
+			expectedInDB:     "Call plumber",
+			expectedExitCode: 0,
+		},
+		{
+			name:             "quick add without title",
+			args:             []string{"+errands !low"},
+			initialDB:        io.Database{Tasks: []io.Task{}},
+			expectedStderr:   "no title",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "quick add bad priority",
+			args:             []string{"Buy milk !urgent"},
+			initialDB:        io.Database{Tasks: []io.Task{}},
+			expectedStderr:   `invalid priority "urgent"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "title twice",
+			args:             []string{"-t", "One", "Two"},
+			initialDB:        io.Database{Tasks: []io.Task{}},
+			expectedStderr:   "either with --title or as text",
+			expectedExitCode: 2,
 		},
 	}
 
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/recur"
+)
+
+func agendaDB() io.Database {
+	standup := time.Date(2030, 1, 7, 9, 30, 0, 0, time.Local)
+	rent := time.Date(2030, 1, 1, 0, 0, 0, 0, time.Local)
+	review := time.Date(2030, 1, 8, 0, 0, 0, 0, time.Local)
+	released := time.Date(2030, 1, 8, 0, 0, 0, 0, time.Local)
+
+	return io.Database{
+		Size: 4,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Standup", Tags: []string{"work"}, Due: &standup, Recurrence: "FREQ=WEEKLY;BYDAY=MO,WE,FR"},
+			{ID: 1, Title: "Pay rent", Due: &rent, Recurrence: "FREQ=MONTHLY"},
+			{ID: 2, Title: "Code review", Due: &review},
+			{ID: 3, Title: "Release", Due: &released, Status: io.StatusDone},
+		},
+	}
+}
+
+func TestRunAgenda(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "days with projections",
+			args:           []string{"--from", "2030-01-07", "--days", "3"},
+			expectedStdout: "Mon 07 Jan 2030:\n  #0   09:30  Standup +work\nTue 08 Jan 2030:\n  #2          Code review\nWed 09 Jan 2030:\n  #0   09:30  Standup +work ↻\n",
+			unexpected:     "Release",
+		},
+		{
+			name:           "empty day",
+			args:           []string{"--from", "2030-01-05", "-n", "1"},
+			expectedStdout: "Sat 05 Jan 2030:\n  -\n",
+		},
+		{
+			name:           "monthly projection",
+			args:           []string{"--from", "2030-02-01", "-n", "1"},
+			expectedStdout: "  #1          Pay rent ↻",
+		},
+		{
+			name:           "filtered",
+			args:           []string{"--from", "2030-01-07", "-n", "2", "--where", "tag:work"},
+			expectedStdout: "Standup",
+			unexpected:     "Code review",
+		},
+		{
+			name:             "invalid days",
+			args:             []string{"--days", "0"},
+			expectedStderr:   "--days must be at least 1",
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, agendaDB())
+			stdout, stderr, exitCode := runTestCommand(t, "RunAgenda", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got:\n%s", tc.expectedStdout, stdout)
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got:\n%s", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRunCalendar(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "month grid",
+			args:           []string{"--month", "2030-01"},
+			expectedStdout: "January 2030\nMon     Tue     Wed     Thu     Fri     Sat     Sun\n         1 (1)   2       3       4       5       6\n 7 (1)   8 (1)   9 (1)  10      11 (1)  12      13\n|Due this month: 13 (10 from repeating tasks)",
+		},
+		{
+			name:           "projections only",
+			args:           []string{"-m", "2030-02", "-w", "title:rent"},
+			expectedStdout: " 1 (1)   2       3\n|Due this month: 1 (1 from repeating tasks)",
+		},
+		{
+			name:             "invalid month",
+			args:             []string{"--month", "November"},
+			expectedStderr:   `invalid month "November", expected YYYY-MM`,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, agendaDB())
+			stdout, stderr, exitCode := runTestCommand(t, "RunCalendar", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got:\n%s", expected, stdout)
+				}
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRecurBetween(t *testing.T) {
+	start := time.Date(2030, 1, 31, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		rule     string
+		from     time.Time
+		to       time.Time
+		expected []string
+	}{
+		{"daily", start, start.AddDate(0, 0, 3), []string{"2030-01-31", "2030-02-01", "2030-02-02"}},
+		{"FREQ=DAILY;INTERVAL=2;COUNT=3", start, start.AddDate(1, 0, 0), []string{"2030-01-31", "2030-02-02", "2030-02-04"}},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH", start, start.AddDate(0, 0, 7), []string{"2030-01-31", "2030-02-04"}},
+		{"monthly", start, start.AddDate(0, 5, 0), []string{"2030-01-31", "2030-03-31", "2030-05-31"}},
+		{"FREQ=YEARLY;UNTIL=20320101", start, start.AddDate(5, 0, 0), []string{"2030-01-31", "2031-01-31"}},
+		{"weekly", start.AddDate(0, 0, 10), start.AddDate(0, 0, 20), []string{"2030-02-14"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			rule, err := recur.Parse(tc.rule)
+
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an unexpected error: %v", tc.rule, err)
+			}
+
+			var got []string
+
+			for _, date := range rule.Between(start, tc.from, tc.to) {
+				got = append(got, date.Format("2006-01-02"))
+			}
+
+			if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Between() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRecurParse(t *testing.T) {
+	if rule, err := recur.Parse("weekdays"); err != nil || rule.String() != "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR" {
+		t.Errorf("Parse(weekdays) = %v, %v", rule, err)
+	}
+
+	for _, value := range []string{"", "sometimes", "FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;BYMONTHDAY=1"} {
+		if _, err := recur.Parse(value); err == nil {
+			t.Errorf("Parse(%q) expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/internal/board"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func boardDB() io.Database {
+	return io.Database{
+		Size: 4,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Plan the sprint with the whole team", Tags: []string{"work"}},
+			{ID: 1, Title: "Fix login", Status: io.StatusDoing, Priority: io.PriorityHigh, Tags: []string{"work", "bug"}},
+			{ID: 2, Title: "Write docs", Status: io.StatusDoing},
+			{ID: 3, Title: "Release 1.0", Status: io.StatusDone},
+		},
+	}
+}
+
+func TestRunBoard(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		config           string
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "by status",
+			args:           []string{},
+			expectedStdout: "PENDING (1)       DOING (2)         DONE (1)\n|#0 Plan the       #1 Fix login      #3 Release 1.0\n   sprint with       !high\n   the whole      #2 Write docs\n   team\n",
+		},
+		{
+			name:           "wip limit exceeded",
+			args:           []string{},
+			config:         `{"board": {"wip": {"doing": 1}}}`,
+			expectedStdout: "DOING (2/1) !",
+			expectedStderr: "Warning: doing holds 2 tasks, over its WIP limit of 1",
+		},
+		{
+			name:           "configured columns",
+			args:           []string{},
+			config:         `{"board": {"columns": ["pending", "doing", "review", "done"]}}`,
+			expectedStdout: "REVIEW (0)",
+		},
+		{
+			name:           "by tag",
+			args:           []string{"--by", "tag"},
+			expectedStdout: "BUG (1)           WORK (1)          NONE (2)",
+		},
+		{
+			name:             "unknown field",
+			args:             []string{"--by", "colour"},
+			expectedStderr:   `unknown board field "colour"`,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, boardDB())
+			t.Setenv("COLUMNS", "54")
+
+			if tc.config != "" {
+				if err := os.WriteFile(filepath.Join(filepath.Dir(dbFile), "config.json"), []byte(tc.config), 0644); err != nil {
+					t.Fatalf("failed to write config: %v", err)
+				}
+			}
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunBoard", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got:\n%s", expected, stdout)
+				}
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+		})
+	}
+}
+
+func TestRunMove(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		config           string
+		expectedStderr   string
+		check            func(db io.Database) bool
+		expectedExitCode int
+	}{
+		{
+			name:  "to another status",
+			args:  []string{"0", "doing"},
+			check: func(db io.Database) bool { return db.Tasks[0].Status == io.StatusDoing },
+		},
+		{
+			name:  "back to pending",
+			args:  []string{"1-2", "pending"},
+			check: func(db io.Database) bool { return db.Tasks[1].Status == "" && db.Tasks[2].Status == "" },
+		},
+		{
+			name:  "between tag columns",
+			args:  []string{"1", "ops", "--by", "tag"},
+			check: func(db io.Database) bool { return strings.Join(db.Tasks[1].Tags, ",") == "work,ops" },
+		},
+		{
+			name:  "priority none",
+			args:  []string{"1", "none", "-b", "priority"},
+			check: func(db io.Database) bool { return db.Tasks[1].Priority == "" },
+		},
+		{
+			name:             "wip warning",
+			args:             []string{"0", "doing"},
+			config:           `{"board": {"wip": {"doing": 2}}}`,
+			expectedStderr:   "Warning: doing holds 3 tasks",
+			check:            func(db io.Database) bool { return db.Tasks[0].Status == io.StatusDoing },
+			expectedExitCode: 0,
+		},
+		{
+			name:             "unknown configured column",
+			args:             []string{"0", "blocked"},
+			config:           `{"board": {"columns": ["pending", "doing", "done"]}}`,
+			expectedStderr:   `no column "blocked" on the board`,
+			check:            func(db io.Database) bool { return db.Tasks[0].Status == "" },
+			expectedExitCode: 2,
+		},
+		{
+			name:             "missing column",
+			args:             []string{"0"},
+			expectedStderr:   "Usage of move:|unfilled arguments",
+			check:            func(db io.Database) bool { return true },
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, boardDB())
+
+			if tc.config != "" {
+				if err := os.WriteFile(filepath.Join(filepath.Dir(dbFile), "config.json"), []byte(tc.config), 0644); err != nil {
+					t.Fatalf("failed to write config: %v", err)
+				}
+			}
+
+			_, stderr, exitCode := runTestCommand(t, "RunMove", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			if db := readTestDB(t, dbFile); !tc.check(db) {
+				t.Errorf("unexpected database after move: %+v", db.Tasks)
+			}
+		})
+	}
+}
+
+func TestBoardColumnOf(t *testing.T) {
+	b, err := board.New(boardDB().Tasks, "tag", []string{"bug", "work"}, nil)
+
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+
+	if got := b.ColumnOf(boardDB().Tasks[1]); got != "bug" {
+		t.Errorf("task with several tags went to %q, want the first matching column %q", got, "bug")
+	}
+
+	if column, _ := b.Column(board.None); len(column.Tasks) != 2 {
+		t.Errorf("expected 2 untagged tasks, got %+v", column.Tasks)
+	}
+}
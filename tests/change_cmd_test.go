@@ -38,7 +38,17 @@ func TestRunChange(t *testing.T) {
 				Tasks: []io.Task{{ID: 0, Title: "Original Task", Description: "Original Description"}},
 			},
 			expectedStderr:   "Usage of change:|unfilled arguments",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
+		},
+		{
+			name: "missing index with title and description",
+			args: []string{"-t", "some title", "-d", "some description"},
+			initialDB: io.Database{
+				Size:  1,
+				Tasks: []io.Task{{ID: 0, Title: "Original Task", Description: "Original Description"}},
+			},
+			expectedStderr:   "Usage of change:|unfilled arguments",
+			expectedExitCode: 2,
 		},
 		{
 			name: "no new values",
@@ -49,7 +59,7 @@ func TestRunChange(t *testing.T) {
 			},
 			expectedStdout:   "",
 			expectedStderr:   "changing task: No value is changed",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
 			name: "invalid index",
@@ -59,7 +69,7 @@ func TestRunChange(t *testing.T) {
 				Tasks: []io.Task{{ID: 0, Title: "Original Task", Description: "Original Description"}},
 			},
 			expectedStderr:   "changing task: invalid index 99: out of bounds",
-			expectedExitCode: 1,
+			expectedExitCode: 3,
 		},
 	}
 
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/app/cmd"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestExecute(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{name: "alias", args: []string{"ls"}, expectedStdout: "ID  STATUS  TITLE", expectedExitCode: 0},
+		{name: "typo suggestion", args: []string{"delte"}, expectedStderr: `unknown command "delte", did you mean "delete"?`, expectedExitCode: 2},
+		{name: "unknown command", args: []string{"frobnicate"}, expectedStderr: "usable: add, view, show, change", expectedExitCode: 2},
+		{name: "no command", args: []string{}, expectedStderr: "Commands:|arguments not enough", expectedExitCode: 2},
+		{name: "help overview", args: []string{"help"}, expectedStdout: "Run \"taski help <command>\"", expectedExitCode: 0},
+		{name: "help command", args: []string{"help", "rm"}, expectedStdout: "taski delete <id|range>...|Aliases: rm, del|-index int", expectedExitCode: 0},
+		{name: "help subcommand group", args: []string{"help", "sync"}, expectedStdout: "taski sync caldav|taski sync git", expectedExitCode: 0},
+		{name: "command help flag", args: []string{"change", "--help"}, expectedStderr: "Usage of change:|taski change <id", expectedExitCode: 0},
+		{name: "help unknown", args: []string{"help", "ad"}, expectedStderr: `did you mean "add"?`, expectedExitCode: 2},
+		{name: "completion script", args: []string{"completion", "fish"}, expectedStdout: "complete -c taski", expectedExitCode: 0},
+		{name: "completion unknown shell", args: []string{"completion", "tcsh"}, expectedStderr: `unknown shell "tcsh"`, expectedExitCode: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Only"}}})
+
+			stdout, stderr, exitCode := runTestCommand(t, "Execute", tc.args, dbFile)
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{
+		Size: 2,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Write report", Tags: []string{"work"}},
+			{ID: 1, Title: "Trashed", IsDeleted: true, Tags: []string{"old"}},
+			{ID: 12, Title: "Buy milk", Tags: []string{"errands", "work"}, Contexts: []string{"home"}},
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		words    []string
+		expected []string
+	}{
+		{name: "command prefix", words: []string{"re"}, expected: []string{"restore", "remind"}},
+		{name: "alias prefix", words: []string{"mo"}, expected: []string{"modify", "mod", "move"}},
+		{name: "subcommand", words: []string{"ical", ""}, expected: []string{"export", "import"}},
+		{name: "help topic", words: []string{"help", "ex"}, expected: []string{"export"}},
+		{name: "task ids", words: []string{"delete", "-i", ""}, expected: []string{"0\tWrite report", "12\tBuy milk"}},
+		{name: "task id prefix", words: []string{"change", "--index", "1"}, expected: []string{"12\tBuy milk"}},
+		{name: "positional ids", words: []string{"rm", "0", ""}, expected: []string{"0\tWrite report", "12\tBuy milk"}},
+		{name: "trashed ids for restore", words: []string{"restore", ""}, expected: []string{"1\tTrashed"}},
+		{name: "tags", words: []string{"add", "Milk", "+"}, expected: []string{"+errands", "+work"}},
+		{name: "tag prefix", words: []string{"export", "--where", "+w"}, expected: []string{"+work"}},
+		{name: "contexts", words: []string{"add", "@"}, expected: []string{"@home"}},
+		{name: "unknown command", words: []string{"nope", ""}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := cmd.Complete(tc.words, dbFile)
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Complete(%q) = %q, want %q", tc.words, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	testCases := map[string]string{
+		"ad":      "add",
+		"veiw":    "view",
+		"restroe": "restore",
+		"serv":    "serve",
+	}
+
+	for typo, want := range testCases {
+		got := cmd.Suggest(typo)
+
+		if len(got) == 0 || got[0] != want {
+			t.Errorf("Suggest(%q) = %q, want %q first", typo, got, want)
+		}
+	}
+
+	if got := cmd.Suggest("xyzzy"); len(got) != 0 {
+		t.Errorf("Suggest(%q) = %q, want none", "xyzzy", got)
+	}
+}
@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// setContext makes name the active context of the database, as taski
+// context set does.
+func setContext(t *testing.T, dbFile string, name string) {
+	t.Helper()
+
+	if name == "" {
+		return
+	}
+
+	if _, stderr, exitCode := runTestCommand(t, "RunContext", []string{"set", name}, dbFile); exitCode != 0 {
+		t.Fatalf("failed to set context: %s", stderr)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	testCases := []struct {
+		name             string
+		active           string
+		command          string
+		args             []string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+		check            func(t *testing.T, db io.Database)
+	}{
+		{
+			name:           "no context",
+			command:        "RunContext",
+			args:           []string{},
+			expectedStdout: "No context set.",
+		},
+		{
+			name:           "shows the context",
+			active:         "office",
+			command:        "RunContext",
+			args:           []string{},
+			expectedStdout: "Context: @office",
+		},
+		{
+			name:           "set accepts the sigil",
+			command:        "RunContext",
+			args:           []string{"set", "@phone"},
+			expectedStdout: "Context Set: @phone",
+		},
+		{
+			name:             "set rejects two words",
+			command:          "RunContext",
+			args:             []string{"set", "home office"},
+			expectedStderr:   `invalid context "home office"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "set rejects none",
+			command:          "RunContext",
+			args:             []string{"set", "none"},
+			expectedStderr:   `"none" clears the context`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown subcommand",
+			command:          "RunContext",
+			args:             []string{"use", "office"},
+			expectedStderr:   `unknown context subcommand "use"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:           "list marks the active context",
+			active:         "garden",
+			command:        "RunContext",
+			args:           []string{"list"},
+			expectedStdout: "Contexts:\n* @garden (0 task(s))\n  @office (2 task(s))\n  @phone (1 task(s))\n",
+		},
+		{
+			name:           "view is narrowed",
+			active:         "office",
+			command:        "RunView",
+			args:           []string{},
+			expectedStdout: "Print report",
+			unexpected:     "Call mom",
+		},
+		{
+			name:           "view combines the context with --where",
+			active:         "office",
+			command:        "RunView",
+			args:           []string{"--where", "book"},
+			expectedStdout: "Book room",
+			unexpected:     "Print report",
+		},
+		{
+			name:           "view in an empty context",
+			active:         "garden",
+			command:        "RunView",
+			args:           []string{},
+			expectedStdout: "No tasks in context @garden.",
+		},
+		{
+			name:           "board is narrowed",
+			active:         "phone",
+			command:        "RunBoard",
+			args:           []string{},
+			expectedStdout: "Call mom",
+			unexpected:     "Print report",
+		},
+		{
+			name:    "new tasks join the context",
+			active:  "office",
+			command: "RunAdd",
+			args:    []string{"Order toner"},
+			check: func(t *testing.T, db io.Database) {
+				if contexts := db.Tasks[3].Contexts; !slices.Equal(contexts, []string{"office"}) {
+					t.Errorf("expected the new task in @office, got %q", contexts)
+				}
+			},
+		},
+		{
+			name:    "new tasks keep their own contexts",
+			active:  "office",
+			command: "RunAdd",
+			args:    []string{"Call plumber @phone @Office"},
+			check: func(t *testing.T, db io.Database) {
+				if contexts := db.Tasks[3].Contexts; !slices.Equal(contexts, []string{"phone", "Office"}) {
+					t.Errorf("expected the typed contexts only, got %q", contexts)
+				}
+			},
+		},
+		{
+			name:    "changes are not narrowed",
+			active:  "phone",
+			command: "RunDone",
+			args:    []string{"1"},
+			check: func(t *testing.T, db io.Database) {
+				if !db.Tasks[1].IsDone() {
+					t.Errorf("expected a task outside the context to be completed")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{Size: 3, Tasks: []io.Task{
+				{ID: 0, Title: "Call mom", Contexts: []string{"phone"}},
+				{ID: 1, Title: "Print report", Contexts: []string{"office"}},
+				{ID: 2, Title: "Book room", Contexts: []string{"Office"}},
+			}})
+
+			setContext(t, dbFile, tc.active)
+
+			stdout, stderr, exitCode := runTestCommand(t, tc.command, tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, stdout)
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got %q", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+
+			if tc.check != nil {
+				tc.check(t, readTestDB(t, dbFile))
+			}
+		})
+	}
+}
+
+func TestRunContextNone(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Call mom", Contexts: []string{"phone"}}}})
+	setContext(t, dbFile, "office")
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunContext", []string{"none"}, dbFile)
+
+	if exitCode != 0 || !strings.Contains(stdout, "Context Cleared") {
+		t.Fatalf("expected the context to be cleared, got exit code %d: %s%s", exitCode, stdout, stderr)
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dbFile), "context.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the context state to be removed, got %v", err)
+	}
+
+	if stdout, _, _ := runTestCommand(t, "RunView", []string{}, dbFile); !strings.Contains(stdout, "Call mom") {
+		t.Errorf("expected every task to be listed again, got %q", stdout)
+	}
+}
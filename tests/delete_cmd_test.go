@@ -30,12 +30,30 @@ func TestRunDelete(t *testing.T) {
 			taskShouldBeDeleted: true,
 			expectedExitCode:    0,
 		},
+		{
+			name: "delete several positional ids",
+			args: []string{"0", "2"},
+			initialDB: io.Database{
+				Size:  3,
+				Tasks: []io.Task{{ID: 0, Title: "First"}, {ID: 1, Title: "Second"}, {ID: 2, Title: "Third"}},
+			},
+			expectedStdout:      "Deleted Tasks:\nIndex: 0\nIndex: 2",
+			taskShouldBeDeleted: true,
+			expectedExitCode:    0,
+		},
+		{
+			name:             "invalid positional id",
+			args:             []string{"two"},
+			initialDB:        io.Database{},
+			expectedStderr:   `invalid task id "two"`,
+			expectedExitCode: 2,
+		},
 		{
 			name:             "missing index",
 			args:             []string{},
 			initialDB:        io.Database{},
 			expectedStderr:   "Usage of delete:|unfilled arguments",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
 			name: "invalid index",
@@ -45,7 +63,7 @@ func TestRunDelete(t *testing.T) {
 				Tasks: []io.Task{{ID: 0, Title: "A Task"}},
 			},
 			expectedStderr:   "deleting task: deleting task: invalid index 99: out of bounds",
-			expectedExitCode: 1,
+			expectedExitCode: 3,
 		},
 	}
 
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func bulkDB() io.Database {
+	return io.Database{
+		Size: 4,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Plan sprint", Tags: []string{"sprint12"}},
+			{ID: 1, Title: "Fix login", Tags: []string{"sprint12"}},
+			{ID: 2, Title: "Write docs"},
+			{ID: 3, Title: "Review PR", Tags: []string{"sprint12"}},
+		},
+	}
+}
+
+func TestRunDone(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		expectedStderr   string
+		expectedDone     []int
+		expectedExitCode int
+	}{
+		{name: "single id", args: []string{"2"}, expectedStdout: "Completed Task:\nIndex: 2", expectedDone: []int{2}},
+		{name: "range and list", args: []string{"0-1,3"}, expectedStdout: "Completed Tasks:\nIndex: 0\nIndex: 1\nIndex: 3", expectedDone: []int{0, 1, 3}},
+		{name: "where filter", args: []string{"--where", "tag:sprint12"}, expectedDone: []int{0, 1, 3}},
+		{name: "ids and where", args: []string{"1", "-w", "+sprint12"}, expectedStderr: "not both", expectedExitCode: 2},
+		{name: "nothing matches", args: []string{"-w", "tag:nope"}, expectedStderr: `nothing matches "tag:nope"`, expectedExitCode: 3},
+		{name: "out of bounds leaves others untouched", args: []string{"0-1,9"}, expectedStderr: "invalid index 9", expectedExitCode: 3},
+		{name: "bad range", args: []string{"3-1"}, expectedStderr: `invalid task range "3-1"`, expectedExitCode: 2},
+		{name: "range past the end", args: []string{"2-999999999"}, expectedStderr: "invalid index 4: out of bounds", expectedExitCode: 3},
+		{name: "missing ids", args: []string{}, expectedStderr: "Usage of done:|unfilled arguments", expectedExitCode: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, bulkDB())
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunDone", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if tc.expectedStdout != "" && !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, stdout)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			db := readTestDB(t, dbFile)
+			done := map[int]bool{}
+
+			for _, id := range tc.expectedDone {
+				done[id] = true
+			}
+
+			for _, task := range db.Tasks {
+				if task.IsDone() != done[task.ID] {
+					t.Errorf("task %d done = %v, want %v", task.ID, task.IsDone(), done[task.ID])
+				}
+			}
+		})
+	}
+}
+
+func TestRunDoneUndo(t *testing.T) {
+	dbFile := setupTestDB(t, bulkDB())
+
+	_, stderr, exitCode := runTestCommand(t, "RunDone", []string{"0-3"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("done failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunDone", []string{"1", "--undo"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("undo failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "Reopened Task:") {
+		t.Errorf("expected reopened output, got %q", stdout)
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if db.Tasks[1].IsDone() || !db.Tasks[0].IsDone() {
+		t.Errorf("expected only task 1 reopened, got %+v", db.Tasks)
+	}
+
+	stdout, _, _ = runTestCommand(t, "RunExport", []string{"-f", "todotxt", "-w", "status:done"}, dbFile)
+
+	if !strings.HasPrefix(stdout, "x ") || strings.Contains(stdout, "Fix login") {
+		t.Errorf("expected only done tasks exported with an x, got %q", stdout)
+	}
+}
+
+func TestRunDeleteWhere(t *testing.T) {
+	dbFile := setupTestDB(t, bulkDB())
+
+	_, stderr, exitCode := runTestCommand(t, "RunDelete", []string{"-w", "+sprint12"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("delete failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if db.Size != 1 || db.Tasks[2].IsDeleted || !db.Tasks[3].IsDeleted {
+		t.Fatalf("expected the sprint12 tasks in trash, got %+v", db)
+	}
+
+	_, stderr, exitCode = runTestCommand(t, "RunRestore", []string{"0-1"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("restore failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if db := readTestDB(t, dbFile); db.Size != 3 || db.Tasks[0].IsDeleted || !db.Tasks[3].IsDeleted {
+		t.Errorf("expected tasks 0 and 1 restored, got %+v", db)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	testCases := []struct {
+		name             string
+		script           string
+		args             []string
+		expectedStdout   string
+		expectedStderr   string
+		expectedSize     int
+		expectedExitCode int
+	}{
+		{
+			name: "applies every line",
+			script: `# weekly cleanup
+add "Ship release" +sprint12 !high
+done 0-1
+delete --where 'title:docs'
+change 3 --title "Review PR #42"
+`,
+			expectedStdout: "Applied 4 Operation(s):|added 4 Ship release|completed 1|deleted 2",
+			expectedSize:   4,
+		},
+		{
+			name:           "dry run writes nothing",
+			script:         "delete 0-3\n",
+			args:           []string{"--dry-run"},
+			expectedStdout: "Dry Run, Nothing Written:|deleted 3",
+			expectedSize:   4,
+		},
+		{
+			name:             "failing line aborts the batch",
+			script:           "delete 0\ndone 7\n",
+			expectedStderr:   "line 2: invalid index 7",
+			expectedSize:     4,
+			expectedExitCode: 3,
+		},
+		{
+			name:           "undo reports reopened tasks",
+			script:         "done 0\ndone --undo 0\n",
+			expectedStdout: "completed 0|reopened 0",
+			expectedSize:   4,
+		},
+		{
+			name:           "range over added tasks",
+			script:         "add Extra\ndelete 3-4\n",
+			expectedStdout: "deleted 3|deleted 4",
+			expectedSize:   3,
+		},
+		{
+			name:             "range past the end",
+			script:           "delete 0-999999999\n",
+			expectedStderr:   "line 1: invalid index 4: out of bounds",
+			expectedSize:     4,
+			expectedExitCode: 3,
+		},
+		{
+			name:             "syntax error",
+			script:           "delete 0\nfrobnicate 1\n",
+			expectedStderr:   `line 2: unknown batch command "frobnicate"`,
+			expectedSize:     4,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unterminated quote",
+			script:           "add \"Oops\n",
+			expectedStderr:   "line 1: unterminated \" quote",
+			expectedSize:     4,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, bulkDB())
+			script := filepath.Join(t.TempDir(), "batch.txt")
+
+			if err := os.WriteFile(script, []byte(tc.script), 0644); err != nil {
+				t.Fatalf("failed to write script: %v", err)
+			}
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunBatch", append([]string{"-f", script}, tc.args...), dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			if db := readTestDB(t, dbFile); db.Size != tc.expectedSize {
+				t.Errorf("expected %d active tasks, got %d", tc.expectedSize, db.Size)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+//go:build unix
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/frontmatter"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// fakeEditor points $EDITOR at a script that replaces the file it is given
+// with content, or leaves it alone when content is empty.
+func fakeEditor(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "editor.sh")
+	replacement := filepath.Join(dir, "content.md")
+
+	if err := os.WriteFile(replacement, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write editor content: %v", err)
+	}
+
+	body := "#!/bin/sh\n"
+
+	if content != "" {
+		body += "cat '" + replacement + "' > \"$1\"\n"
+	}
+
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatalf("failed to write editor script: %v", err)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+}
+
+func editDB() io.Database {
+	return io.Database{
+		Size: 2,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Write report", Description: "Quarterly numbers", Tags: []string{"work"}},
+			{ID: 1, Title: "Buy milk"},
+		},
+	}
+}
+
+func TestRunEdit(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		content          string
+		expectedStdout   string
+		expectedStderr   string
+		expectedTitle    string
+		expectedExitCode int
+	}{
+		{
+			name:           "edit fields and description",
+			args:           []string{"0"},
+			content:        "---\ntitle: Write annual report\ntags: work, finance\ndue: 2026-03-01\npriority: high\n---\nNumbers for\n\n---\n\nthe whole year.\n",
+			expectedStdout: "- title: Write report|+ title: Write annual report|+ priority: high|Edited Task:",
+			expectedTitle:  "Write annual report",
+		},
+		{
+			name:           "unchanged file",
+			args:           []string{"0"},
+			expectedStdout: "No changes, nothing was saved.",
+			expectedTitle:  "Write report",
+		},
+		{
+			name:             "invalid priority",
+			args:             []string{"0"},
+			content:          "---\ntitle: Write report\npriority: urgent\n---\n",
+			expectedStderr:   `line 3: invalid priority "urgent"|your edits are kept in`,
+			expectedTitle:    "Write report",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown key",
+			args:             []string{"0"},
+			content:          "---\ntitle: Write report\nowner: me\n---\n",
+			expectedStderr:   `unknown key "owner"`,
+			expectedTitle:    "Write report",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "missing title",
+			args:             []string{"0"},
+			content:          "---\ntitle:\n---\nSomething\n",
+			expectedStderr:   "task has no title",
+			expectedTitle:    "Write report",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "out of bounds",
+			args:             []string{"9"},
+			expectedStderr:   "invalid index 9",
+			expectedTitle:    "Write report",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "id and all",
+			args:             []string{"0", "--all"},
+			expectedStderr:   "not both",
+			expectedTitle:    "Write report",
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, editDB())
+			fakeEditor(t, tc.content)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunEdit", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			if db := readTestDB(t, dbFile); db.Tasks[0].Title != tc.expectedTitle {
+				t.Errorf("expected title %q, got %q", tc.expectedTitle, db.Tasks[0].Title)
+			}
+		})
+	}
+}
+
+func TestRunEditDescription(t *testing.T) {
+	dbFile := setupTestDB(t, editDB())
+	fakeEditor(t, "---\ntitle: Write report\ntags: +work @office\nstatus: done\n---\nFirst line\n\n---\n\nAfter a rule.\n")
+
+	_, stderr, exitCode := runTestCommand(t, "RunEdit", []string{"0"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("edit failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	task := readTestDB(t, dbFile).Tasks[0]
+
+	if task.Description != "First line\n\n---\n\nAfter a rule." {
+		t.Errorf("description not kept with its rule, got %q", task.Description)
+	}
+
+	if strings.Join(task.Tags, ",") != "work,office" || !task.IsDone() {
+		t.Errorf("labels or status not applied, got %+v", task)
+	}
+}
+
+func TestRunEditAll(t *testing.T) {
+	dbFile := setupTestDB(t, editDB())
+	fakeEditor(t, "---\nid: 0\ntitle: Write report\ntags: work\n---\nQuarterly numbers\n\n---\ntitle: Call plumber\ndue: 2026-04-02\n---\n")
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunEdit", []string{"--all"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("edit --all failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	for _, expected := range []string{"Deleted Task 1: Buy milk", "Added Task 2: Call plumber", "Changed: 0, Added: 1, Deleted: 1"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+		}
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if len(db.Tasks) != 3 || db.Size != 2 || !db.Tasks[1].IsDeleted || db.Tasks[2].Due == nil {
+		t.Errorf("expected one task trashed and one added, got %+v", db)
+	}
+}
+
+func TestRunEditAllInContext(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{
+		Size: 2,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Write report", Contexts: []string{"office"}},
+			{ID: 1, Title: "Buy milk", Contexts: []string{"home"}},
+		},
+	})
+	setContext(t, dbFile, "office")
+	fakeEditor(t, "---\nid: 0\ntitle: Write report\ncontexts: office\n---\n\n---\ntitle: Book room\n---\n")
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunEdit", []string{"--all"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("edit --all failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "Changed: 0, Added: 1, Deleted: 0") {
+		t.Errorf("expected one task added and none deleted, got %q", stdout)
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if db.Tasks[1].IsDeleted || len(db.Tasks) != 3 || strings.Join(db.Tasks[2].Contexts, ",") != "office" {
+		t.Errorf("expected the new task in @office and @home untouched, got %+v", db.Tasks)
+	}
+}
+
+func TestFrontmatterRoundTrip(t *testing.T) {
+	due := time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local)
+	task := io.Task{ID: 4, Title: "Plan", Description: "Steps\n---\nmore", Tags: []string{"a", "b"}, Due: &due, Priority: io.PriorityLow}
+
+	documents, err := frontmatter.Decode(frontmatter.EncodeAll([]io.Task{task, {ID: 5, Title: "Other"}}, nil), time.Now(), nil)
+
+	if err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+
+	if len(documents) != 2 || documents[0].ID != 4 || documents[1].ID != 5 {
+		t.Fatalf("expected two documents with ids, got %+v", documents)
+	}
+
+	decoded := documents[0].Task
+
+	if decoded.Description != task.Description || !decoded.Due.Equal(due) || decoded.Priority != task.Priority {
+		t.Errorf("round trip lost fields: %+v", decoded)
+	}
+
+	if diff := frontmatter.Diff("a\nb\nc\n", "a\nB\nc\nd\n"); diff != "- b\n+ B\n+ d\n" {
+		t.Errorf("unexpected diff %q", diff)
+	}
+}
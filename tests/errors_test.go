@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tristnaja/taski/app/cmd"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Only"}}})
+
+	err := io.RemoveTask(dbFile, 4)
+	var indexErr *io.IndexError
+
+	if !errors.Is(err, io.ErrOutOfBounds) || !errors.As(err, &indexErr) || indexErr.Index != 4 {
+		t.Errorf("RemoveTask() error = %v, want IndexError for 4", err)
+	}
+
+	err = io.ChangeTask(dbFile, -1, "x", "")
+
+	if !errors.Is(err, io.ErrOutOfBounds) {
+		t.Errorf("ChangeTask(-1) error = %v, want ErrOutOfBounds", err)
+	}
+
+	err = io.ChangeTask(dbFile, 0, "", "")
+
+	if !errors.Is(err, io.ErrNoChange) {
+		t.Errorf("ChangeTask() without values error = %v, want ErrNoChange", err)
+	}
+
+	corrupt := filepath.Join(t.TempDir(), "corrupt.json")
+	os.WriteFile(corrupt, []byte("{not json"), 0644)
+
+	_, err = io.ReadAll(corrupt)
+
+	if !errors.Is(err, io.ErrCorrupt) {
+		t.Errorf("ReadAll() on corrupt file error = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: cmd.ExitOK},
+		{name: "usage", err: cmd.Usagef("unfilled arguments"), want: cmd.ExitUsage},
+		{name: "no change", err: fmt.Errorf("changing task: %w", io.ErrNoChange), want: cmd.ExitUsage},
+		{name: "out of bounds", err: fmt.Errorf("deleting task: %w", &io.IndexError{Index: 9}), want: cmd.ExitNotFound},
+		{name: "not found", err: io.ErrNotFound, want: cmd.ExitNotFound},
+		{name: "locked", err: fmt.Errorf("adding task: %w", io.ErrLocked), want: cmd.ExitLocked},
+		{name: "corrupt", err: fmt.Errorf("reading file: %w", io.ErrCorrupt), want: cmd.ExitCorrupt},
+		{name: "io", err: fmt.Errorf("opening: %w", &os.PathError{Op: "open", Path: "x", Err: os.ErrPermission}), want: cmd.ExitIO},
+		{name: "other", err: errors.New("boom"), want: cmd.ExitFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cmd.ExitCode(tc.err); got != tc.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	var out struct {
+		Error    string `json:"error"`
+		Kind     string `json:"kind"`
+		ExitCode int    `json:"exit_code"`
+	}
+
+	err := json.Unmarshal(cmd.ErrorJSON(fmt.Errorf("deleting task: %w", &io.IndexError{Index: 9})), &out)
+
+	if err != nil {
+		t.Fatalf("ErrorJSON() is not valid JSON: %v", err)
+	}
+
+	if out.Kind != "not_found" || out.ExitCode != cmd.ExitNotFound || out.Error != "deleting task: invalid index 9: out of bounds" {
+		t.Errorf("ErrorJSON() = %+v", out)
+	}
+}
@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestRunExport(t *testing.T) {
+	deletedAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	initialDB := io.Database{
+		Size: 2,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Write report", Description: "Quarterly numbers", Date: time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)},
+			{ID: 1, Title: "Call plumber", Description: "Kitchen sink", Date: time.Date(2025, 1, 11, 8, 0, 0, 0, time.UTC)},
+			{ID: 2, Title: "Old idea", Date: time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC), IsDeleted: true, DeletedAt: &deletedAt},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   []string
+		unexpectedStdout []string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:             "todotxt",
+			args:             []string{"-f", "todotxt"},
+			expectedStdout:   []string{"2025-01-10 Write report id:0", "2025-01-11 Call plumber id:1"},
+			unexpectedStdout: []string{"Old idea"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "markdown with trash",
+			args:             []string{"--format", "markdown", "--trash"},
+			expectedStdout:   []string{"# Tasks", "- [ ] **Write report** (#0)", "  Quarterly numbers", "## Trash", "- [ ] ~~Old idea~~ (#2)"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "csv",
+			args:             []string{"-f", "csv"},
+			expectedStdout:   []string{"id,title,description,date,is_deleted,deleted_at", "0,Write report,Quarterly numbers,2025-01-10T08:00:00Z,false,"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "html",
+			args:             []string{"-f", "html"},
+			expectedStdout:   []string{"<!DOCTYPE html>", "<h2>Write report</h2>", "Kitchen sink"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "where filter",
+			args:             []string{"-f", "todotxt", "--where", "title:plumber"},
+			expectedStdout:   []string{"Call plumber"},
+			unexpectedStdout: []string{"Write report"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "missing format",
+			args:             []string{},
+			expectedStderr:   "Usage of export:|unfilled arguments",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown format",
+			args:             []string{"-f", "pdf"},
+			expectedStderr:   `unknown format "pdf"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown filter key",
+			args:             []string{"-f", "csv", "-w", "colour:red"},
+			expectedStderr:   `unknown filter key "colour"`,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, initialDB)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunExport", tc.args, dbFile)
+
+			for _, expected := range tc.expectedStdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, unexpected := range tc.unexpectedStdout {
+				if strings.Contains(stdout, unexpected) {
+					t.Errorf("expected stdout not to contain %q, got %q", unexpected, stdout)
+				}
+			}
+
+			if tc.expectedStderr != "" {
+				for _, expected := range strings.Split(tc.expectedStderr, "|") {
+					if !strings.Contains(stderr, expected) {
+						t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+					}
+				}
+			}
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+		})
+	}
+}
+
+func TestRunExportToFile(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Ship it"}}})
+	output := filepath.Join(t.TempDir(), "tasks.md")
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunExport", []string{"-f", "md", "-o", output}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr %q)", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "Exported 1 Task(s)") {
+		t.Errorf("expected stdout to report the export, got %q", stdout)
+	}
+
+	content, err := os.ReadFile(output)
+
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "**Ship it**") {
+		t.Errorf("expected export file to contain the task, got %q", content)
+	}
+}
+
+func TestRunExportInvalidKeepsNoFile(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Ship it"}}})
+
+	for _, args := range [][]string{{"-f", "bogus"}, {"-f", "md", "-w", "colour:red"}} {
+		output := filepath.Join(t.TempDir(), "out.txt")
+
+		_, _, exitCode := runTestCommand(t, "RunExport", append(args, "-o", output), dbFile)
+
+		if exitCode != 2 {
+			t.Errorf("expected exit code 2 for %v, got %d", args, exitCode)
+		}
+
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Errorf("expected no output file for %v, got %v", args, err)
+		}
+	}
+}
+
+func TestRunExportLabels(t *testing.T) {
+	due := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	initialDB := io.Database{
+		Size: 3,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Buy milk", Date: date, Due: &due, Priority: io.PriorityHigh, Tags: []string{"errands"}, Contexts: []string{"home"}},
+			{ID: 1, Title: "Write report", Date: date, Tags: []string{"work", "errands"}},
+			{ID: 2, Title: "Read book", Date: date},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedStdout []string
+	}{
+		{
+			name:           "todotxt",
+			args:           []string{"-f", "todotxt"},
+			expectedStdout: []string{"(A) 2026-03-01 Buy milk +errands @home due:2026-03-05 id:0", "2026-03-01 Write report +work +errands id:1"},
+		},
+		{
+			name:           "markdown grouped by tag",
+			args:           []string{"-f", "markdown"},
+			expectedStdout: []string{"## +errands\n\n- [ ] **Buy milk** (#0) @home !high due 2026-03-05\n- [ ] **Write report** (#1)\n", "## +work\n\n- [ ] **Write report** (#1)\n", "## Untagged\n\n- [ ] **Read book** (#2)\n"},
+		},
+		{
+			name:           "csv",
+			args:           []string{"-f", "csv"},
+			expectedStdout: []string{"due,priority,tags,contexts", ",2026-03-05T00:00:00Z,high,errands,home"},
+		},
+		{
+			name:           "html",
+			args:           []string{"-f", "html"},
+			expectedStdout: []string{"due 05 Mar 2026, 00:00", `<span class="label">+errands</span><span class="label">@home</span>`},
+		},
+		{
+			name:           "where tag",
+			args:           []string{"-f", "todotxt", "--where", "+work"},
+			expectedStdout: []string{"Write report"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, initialDB)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunExport", tc.args, dbFile)
+
+			if exitCode != 0 {
+				t.Fatalf("expected exit code 0, got %d: %s", exitCode, stderr)
+			}
+
+			for _, expected := range tc.expectedStdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/config"
+	"github.com/tristnaja/taski/internal/crdt"
+	"github.com/tristnaja/taski/internal/gitsync"
+	"github.com/tristnaja/taski/internal/hlc"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+type device struct {
+	dbFile   string
+	settings config.Git
+}
+
+func setupGitRemote(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("failed to create bare repo: %v: %s", err, out)
+	}
+
+	return remote
+}
+
+func newDevice(t *testing.T, remote string, db io.Database) device {
+	t.Helper()
+
+	return device{
+		dbFile:   setupTestDB(t, db),
+		settings: config.Git{Repo: filepath.Join(t.TempDir(), "repo"), Remote: remote},
+	}
+}
+
+func (d device) sync(t *testing.T) string {
+	t.Helper()
+	args := []string{"git", "--repo", d.settings.Repo, "--remote", d.settings.Remote}
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunSync", args, d.dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("sync git failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	return stdout
+}
+
+func (d device) edit(t *testing.T, change func(db *io.Database)) {
+	t.Helper()
+	db := readTestDB(t, d.dbFile)
+	change(&db)
+	writeTestDB(t, d.dbFile, db)
+
+	if _, err := gitsync.Commit(d.settings, d.dbFile); err != nil {
+		t.Fatalf("Commit() returned an unexpected error: %v", err)
+	}
+}
+
+func gitLog(t *testing.T, repo string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", repo, "log", "--format=%B").CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("git log failed: %v: %s", err, out)
+	}
+
+	return string(out)
+}
+
+func TestRunSyncGitMerge(t *testing.T) {
+	remote := setupGitRemote(t)
+	day := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	laptop := newDevice(t, remote, io.Database{
+		Size:  1,
+		Tasks: []io.Task{{ID: 0, UID: "shared", Title: "Plan trip", Description: "Pick dates", Date: day}},
+	})
+	desktop := newDevice(t, remote, io.Database{})
+
+	laptop.sync(t)
+	desktop.sync(t)
+	stdout := laptop.sync(t)
+
+	if !strings.Contains(stdout, "Fast-Forwarded: true") {
+		t.Errorf("expected laptop to fast-forward, got %q", stdout)
+	}
+
+	if db := readTestDB(t, desktop.dbFile); len(db.Tasks) != 1 || db.Tasks[0].Title != "Plan trip" {
+		t.Fatalf("desktop did not receive tasks, got %+v", db.Tasks)
+	}
+
+	laptop.edit(t, func(db *io.Database) {
+		db.Tasks[0].Title = "Plan summer trip"
+		db.Tasks[0].Date = day.Add(time.Hour)
+	})
+	desktop.edit(t, func(db *io.Database) {
+		db.Tasks[0].Description = "Pick dates and book flights"
+		db.Tasks[0].Date = day.Add(2 * time.Hour)
+		db.Tasks = append(db.Tasks, io.Task{ID: 1, UID: "desk", Title: "Water plants", Date: day})
+		db.Size++
+	})
+
+	laptop.sync(t)
+	stdout = desktop.sync(t)
+
+	if !strings.Contains(stdout, "Merged: true") || !strings.Contains(stdout, "Conflicts: 0") {
+		t.Errorf("expected a clean semantic merge, got %q", stdout)
+	}
+
+	laptop.sync(t)
+
+	for name, d := range map[string]device{"laptop": laptop, "desktop": desktop} {
+		db := readTestDB(t, d.dbFile)
+
+		if len(db.Tasks) != 2 || db.Size != 2 {
+			t.Fatalf("%s: expected 2 tasks, got %+v", name, db)
+		}
+
+		if db.Tasks[0].Title != "Plan summer trip" || db.Tasks[0].Description != "Pick dates and book flights" {
+			t.Errorf("%s: fields not merged, got %+v", name, db.Tasks[0])
+		}
+	}
+
+	history := gitLog(t, desktop.settings.Repo)
+
+	for _, expected := range []string{"Merge tasks from", "Update tasks: 1 added, 1 changed", "Add task 1: Water plants"} {
+		if !strings.Contains(history, expected) {
+			t.Errorf("expected history to contain %q, got %q", expected, history)
+		}
+	}
+}
+
+func TestRunSyncGitConflict(t *testing.T) {
+	remote := setupGitRemote(t)
+	day := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	initial := io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "shared", Title: "Original", Date: day}}}
+
+	laptop := newDevice(t, remote, initial)
+	desktop := newDevice(t, remote, initial)
+
+	laptop.sync(t)
+	desktop.sync(t)
+
+	laptop.edit(t, func(db *io.Database) {
+		db.Tasks[0].Title = "Laptop wins"
+		db.Tasks[0].Date = day.Add(2 * time.Hour)
+	})
+	desktop.edit(t, func(db *io.Database) {
+		db.Tasks[0].Title = "Desktop loses"
+		db.Tasks[0].Date = day.Add(time.Hour)
+	})
+
+	laptop.sync(t)
+	stdout := desktop.sync(t)
+
+	if !strings.Contains(stdout, "Conflicts: 1") {
+		t.Errorf("expected one conflict, got %q", stdout)
+	}
+
+	if db := readTestDB(t, desktop.dbFile); db.Tasks[0].Title != "Laptop wins" {
+		t.Errorf("newest change should win, got %q", db.Tasks[0].Title)
+	}
+}
+
+func TestRunSyncGitConfig(t *testing.T) {
+	remote := setupGitRemote(t)
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "a", Title: "From config"}}})
+	repo := filepath.Join(t.TempDir(), "repo")
+	content := `{"git": {"repo": "` + repo + `", "remote": "` + remote + `", "branch": "tasks"}}`
+
+	if err := os.WriteFile(config.PathFor(dbFile), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, stderr, exitCode := runTestCommand(t, "RunSync", []string{"git"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("sync git failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	out, err := exec.Command("git", "-C", remote, "show", "tasks:"+gitsync.DataFile).CombinedOutput()
+
+	if err != nil || !strings.Contains(string(out), "From config") {
+		t.Errorf("expected branch tasks on the remote to hold the database, got %v: %s", err, out)
+	}
+}
+
+func TestGitSyncMergePurge(t *testing.T) {
+	base := io.Database{Tasks: []io.Task{
+		{ID: 0, UID: "keep", Title: "Keep"},
+		{ID: 1, UID: "purged", Title: "Purged", IsDeleted: true},
+	}}
+	ours := io.Database{Tasks: []io.Task{{ID: 0, UID: "keep", Title: "Keep"}}}
+	theirs := base
+
+	merged, conflicts := gitsync.Merge(base, ours, theirs)
+
+	if conflicts != 0 || len(merged.Tasks) != 1 || merged.Tasks[0].UID != "keep" {
+		t.Errorf("purged task should stay purged, got %+v", merged.Tasks)
+	}
+}
+
+func TestGitSyncMergeKeepsReplicaState(t *testing.T) {
+	base := io.Database{Tasks: []io.Task{{ID: 0, UID: "a", Title: "Draft"}}}
+	ours := io.Database{
+		Node:       "ours",
+		Clock:      hlc.Timestamp{Wall: 20, Node: "ours"},
+		Tombstones: map[string]hlc.Timestamp{"purged": {Wall: 15, Node: "ours"}},
+		Tasks: []io.Task{{ID: 0, UID: "a", Title: "Draft", Status: "doing", Clocks: map[string]hlc.Timestamp{
+			io.FieldTitle:  {Wall: 1, Node: "base"},
+			io.FieldStatus: {Wall: 20, Node: "ours"},
+		}}},
+	}
+	theirs := io.Database{
+		Node:  "theirs",
+		Clock: hlc.Timestamp{Wall: 30, Node: "theirs"},
+		Tasks: []io.Task{
+			{ID: 0, UID: "a", Title: "Final", Clocks: map[string]hlc.Timestamp{io.FieldTitle: {Wall: 30, Node: "theirs"}}},
+			{ID: 1, UID: "purged", Title: "Old", Clocks: map[string]hlc.Timestamp{io.FieldTitle: {Wall: 5, Node: "theirs"}}},
+		},
+	}
+
+	merged, _ := gitsync.Merge(base, ours, theirs)
+
+	if merged.Node != "ours" || merged.Clock.Compare(theirs.Clock) <= 0 {
+		t.Errorf("expected our node and a clock past both sides, got %q %v", merged.Node, merged.Clock)
+	}
+
+	if _, found := merged.Tombstones["purged"]; !found || len(merged.Tasks) != 1 {
+		t.Fatalf("expected the tombstone to be kept and to bury the task, got %v %+v", merged.Tombstones, merged.Tasks)
+	}
+
+	clocks := merged.Tasks[0].Clocks
+
+	if clocks[io.FieldTitle].Node != "theirs" || clocks[io.FieldStatus].Node != "ours" {
+		t.Errorf("expected each field to keep the clock of the side it came from, got %v", clocks)
+	}
+
+	again, stats := crdt.Merge(merged, theirs)
+
+	if stats != (crdt.Stats{}) || again.Tasks[0].Title != "Final" || again.Tasks[0].Status != "doing" {
+		t.Errorf("expected a CRDT merge after git sync to change nothing, got %+v %+v", stats, again.Tasks)
+	}
+}
+
+func TestRunSyncGitKeepsConcurrentEdits(t *testing.T) {
+	remote := setupGitRemote(t)
+	laptop := newDevice(t, remote, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "a", Title: "Plan trip"}}})
+
+	laptop.sync(t)
+
+	// Edit the database while the push runs, as another taski would.
+	hook := filepath.Join(laptop.settings.Repo, ".git", "hooks", "pre-push")
+	script := "#!/bin/sh\nsed -i.bak 's/\"Plan trip\"/\"Plan summer trip\"/' '" + laptop.dbFile + "'\n"
+
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	laptop.sync(t)
+
+	if db := readTestDB(t, laptop.dbFile); db.Tasks[0].Title != "Plan summer trip" {
+		t.Errorf("expected the edit made during the sync to be kept, got %+v", db.Tasks)
+	}
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	taskiv1 "github.com/tristnaja/taski/api/taski/v1"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/server"
+	"github.com/tristnaja/taski/pkg/client"
+)
+
+func startTestGRPC(t *testing.T, db io.Database, token string) (*client.Client, string) {
+	t.Helper()
+	dbFile := setupTestDB(t, db)
+
+	srv, err := server.New(dbFile, testToken)
+
+	if err != nil {
+		t.Fatalf("server.New() returned an unexpected error: %v", err)
+	}
+
+	srv.PollInterval = 10 * time.Millisecond
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	grpcServer := srv.GRPC()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	c, err := client.Dial(listener.Addr().String(), token)
+
+	if err != nil {
+		t.Fatalf("client.Dial() returned an unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { c.Close() })
+
+	return c, dbFile
+}
+
+func expectCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+
+	if status.Code(err) != code {
+		t.Errorf("expected status %v, got %v", code, err)
+	}
+}
+
+func TestGRPCRequiresToken(t *testing.T) {
+	c, _ := startTestGRPC(t, io.Database{}, "wrong")
+
+	_, err := c.List(context.Background(), false)
+	expectCode(t, err, codes.Unauthenticated)
+}
+
+func TestGRPCCommands(t *testing.T) {
+	ctx := context.Background()
+	c, dbFile := startTestGRPC(t, io.Database{}, testToken)
+	due := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	added, err := c.Add(ctx, &taskiv1.AddTaskRequest{Title: "Typed API", Description: "No shelling out", Priority: io.PriorityHigh, Due: timestamppb.New(due)})
+
+	if err != nil {
+		t.Fatalf("Add() returned an unexpected error: %v", err)
+	}
+
+	if added.GetId() != 0 || added.GetUid() == "" || !added.GetDue().AsTime().Equal(due) {
+		t.Errorf("Add() got %+v", added)
+	}
+
+	_, err = c.Add(ctx, &taskiv1.AddTaskRequest{})
+	expectCode(t, err, codes.InvalidArgument)
+
+	changed, err := c.Change(ctx, 0, "Typed gRPC API", "")
+
+	if err != nil || changed.GetTitle() != "Typed gRPC API" || changed.GetDescription() != "No shelling out" {
+		t.Errorf("Change() got %+v, %v", changed, err)
+	}
+
+	_, err = c.Change(ctx, 9, "x", "")
+	expectCode(t, err, codes.NotFound)
+
+	deleted, err := c.Delete(ctx, 0)
+
+	if err != nil || !deleted.GetIsDeleted() {
+		t.Errorf("Delete() got %+v, %v", deleted, err)
+	}
+
+	_, err = c.Get(ctx, 0)
+	expectCode(t, err, codes.NotFound)
+
+	tasks, err := c.List(ctx, true)
+
+	if err != nil || len(tasks) != 1 {
+		t.Errorf("List(includeTrash) got %d tasks, %v", len(tasks), err)
+	}
+
+	restored, err := c.Restore(ctx, 0)
+
+	if err != nil || restored.GetIsDeleted() {
+		t.Errorf("Restore() got %+v, %v", restored, err)
+	}
+
+	_, err = c.Delete(ctx, 0)
+
+	if err != nil {
+		t.Fatalf("Delete() returned an unexpected error: %v", err)
+	}
+
+	db, err := c.RestoreAll(ctx)
+
+	if err != nil || db.GetSize() != 1 || len(db.GetTasks()) != 1 {
+		t.Errorf("RestoreAll() got %+v, %v", db, err)
+	}
+
+	content, err := c.Export(ctx, "markdown", false)
+
+	if err != nil || !strings.Contains(string(content), "**Typed gRPC API**") {
+		t.Errorf("Export() got %q, %v", content, err)
+	}
+
+	_, err = c.Export(ctx, "pdf", false)
+	expectCode(t, err, codes.InvalidArgument)
+
+	if stored := readTestDB(t, dbFile); len(stored.Tasks) != 1 || stored.Tasks[0].Title != "Typed gRPC API" {
+		t.Errorf("database not updated, got %+v", stored.Tasks)
+	}
+}
+
+func TestGRPCWatchTasks(t *testing.T) {
+	c, dbFile := startTestGRPC(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "a", Title: "Initial"}}}, testToken)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var snapshots []*taskiv1.Database
+	stop := errors.New("stop")
+
+	err := c.Watch(ctx, false, func(db *taskiv1.Database) error {
+		snapshots = append(snapshots, db)
+
+		if len(snapshots) == 1 {
+			// Change the file behind the server's back, as the CLI would.
+			time.Sleep(20 * time.Millisecond)
+			return io.AddTask(io.Task{Title: "From the CLI"}, dbFile)
+		}
+
+		return stop
+	})
+
+	if !errors.Is(err, stop) {
+		t.Fatalf("Watch() returned %v", err)
+	}
+
+	if len(snapshots[0].GetTasks()) != 1 || len(snapshots[1].GetTasks()) != 2 {
+		t.Errorf("expected snapshots with 1 then 2 tasks, got %v", snapshots)
+	}
+}
@@ -121,13 +121,51 @@ func TestMain(m *testing.M) {
 			err = cmd.RunDelete(args, dbFile)
 		case "RunRestore":
 			err = cmd.RunRestore(args, dbFile)
+		case "RunDone":
+			err = cmd.RunDone(args, dbFile)
+		case "RunEdit":
+			err = cmd.RunEdit(args, dbFile)
+		case "RunBoard":
+			err = cmd.RunBoard(args, dbFile)
+		case "RunMove":
+			err = cmd.RunMove(args, dbFile)
+		case "RunAgenda":
+			err = cmd.RunAgenda(args, dbFile)
+		case "RunCalendar":
+			err = cmd.RunCalendar(args, dbFile)
+		case "RunRemind":
+			err = cmd.RunRemind(args, dbFile)
+		case "RunDaemon":
+			err = cmd.RunDaemon(args, dbFile)
+		case "RunContext":
+			err = cmd.RunContext(args, dbFile)
+		case "RunWebhook":
+			err = cmd.RunWebhook(args, dbFile)
+		case "RunBatch":
+			err = cmd.RunBatch(args, dbFile)
+		case "RunNote":
+			err = cmd.RunNote(args, dbFile)
+		case "RunShow":
+			err = cmd.RunShow(args, dbFile)
 		case "RunView":
 			err = cmd.RunView(args, dbFile)
+		case "RunExport":
+			err = cmd.RunExport(args, dbFile)
+		case "RunIcal":
+			err = cmd.RunIcal(args, dbFile)
+		case "RunSync":
+			err = cmd.RunSync(args, dbFile)
+		case "RunMerge":
+			err = cmd.RunMerge(args, dbFile)
+		case "RunServe":
+			err = cmd.RunServe(args, dbFile)
+		case "Execute":
+			err = cmd.Execute(args, dbFile)
 		}
 
 		if err != nil {
 			fmt.Fprint(os.Stderr, err)
-			os.Exit(1)
+			os.Exit(cmd.ExitCode(err))
 		}
 
 		os.Exit(0)
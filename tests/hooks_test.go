@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// writeHook installs a shell script as the hook called name of the
+// database and points its config at the hooks directory.
+func writeHook(t *testing.T, dbFile string, name string, script string, mode os.FileMode) {
+	t.Helper()
+
+	dir := filepath.Join(filepath.Dir(dbFile), "hooks")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create hooks directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	testCases := []struct {
+		name             string
+		hook             string
+		script           string
+		mode             os.FileMode
+		timeout          string
+		command          string
+		args             []string
+		expectedStderr   string
+		expectedExitCode int
+		check            func(t *testing.T, db io.Database)
+	}{
+		{
+			name:             "on-add rejects",
+			hook:             "on-add",
+			script:           "grep -q '\"title\":\"[A-Z]*-[0-9]' || { echo 'title must start with a ticket number' >&2; exit 1; }\n",
+			command:          "RunAdd",
+			args:             []string{"Buy milk"},
+			expectedStderr:   "on-add hook rejected task 2: title must start with a ticket number",
+			expectedExitCode: 2,
+			check: func(t *testing.T, db io.Database) {
+				if len(db.Tasks) != 2 {
+					t.Errorf("expected the rejected task not to be stored, got %d tasks", len(db.Tasks))
+				}
+			},
+		},
+		{
+			name:    "on-add accepts",
+			hook:    "on-add",
+			script:  "grep -q '\"title\":\"[A-Z]*-[0-9]' || exit 1\n",
+			command: "RunAdd",
+			args:    []string{"OPS-12 Rotate keys"},
+			check: func(t *testing.T, db io.Database) {
+				if len(db.Tasks) != 3 || db.Tasks[2].Title != "OPS-12 Rotate keys" {
+					t.Errorf("expected the task to be added, got %+v", db.Tasks)
+				}
+			},
+		},
+		{
+			name:    "on-add changes the task",
+			hook:    "on-add",
+			script:  "echo '{\"id\": 99, \"priority\": \"high\", \"tags\": [\"triage\"]}'\n",
+			command: "RunAdd",
+			args:    []string{"Investigate outage"},
+			check: func(t *testing.T, db io.Database) {
+				task := db.Tasks[2]
+
+				if task.ID != 2 || task.Title != "Investigate outage" || task.Priority != io.PriorityHigh || strings.Join(task.Tags, ",") != "triage" {
+					t.Errorf("expected the hook's priority and tags on task 2, got %+v", task)
+				}
+
+				if _, found := task.Clocks[io.FieldPriority]; !found {
+					t.Errorf("expected the changed priority to be stamped, got clocks %v", task.Clocks)
+				}
+			},
+		},
+		{
+			name:             "on-modify sees the changed fields",
+			hook:             "on-modify",
+			script:           "[ \"$TASKI_FIELDS\" = status ] || { echo \"changed $TASKI_FIELDS\" >&2; exit 1; }\n",
+			command:          "RunChange",
+			args:             []string{"0", "--title", "Renamed"},
+			expectedStderr:   "on-modify hook rejected task 0: changed title",
+			expectedExitCode: 2,
+		},
+		{
+			name:    "on-modify allows",
+			hook:    "on-modify",
+			script:  "[ \"$TASKI_FIELDS\" = status ] || exit 1\n",
+			command: "RunDone",
+			args:    []string{"0"},
+			check: func(t *testing.T, db io.Database) {
+				if !db.Tasks[0].IsDone() {
+					t.Errorf("expected task 0 to be done")
+				}
+			},
+		},
+		{
+			name:    "on-modify replaces custom fields",
+			hook:    "on-modify",
+			script:  "echo '{\"custom\": {\"team\": \"sre\"}}'\n",
+			command: "RunDone",
+			args:    []string{"0"},
+			check: func(t *testing.T, db io.Database) {
+				task := db.Tasks[0]
+
+				if len(task.Custom) != 1 || task.Custom["team"] != "sre" {
+					t.Errorf("expected the hook's custom fields to replace the old ones, got %v", task.Custom)
+				}
+
+				if _, found := task.Clocks[io.FieldCustom]; !found {
+					t.Errorf("expected the changed custom fields to be stamped, got clocks %v", task.Clocks)
+				}
+			},
+		},
+		{
+			name:             "on-delete rejects",
+			hook:             "on-delete",
+			script:           "grep -q '\"keep\"' && { echo 'keep tasks stay' >&2; exit 1; }\nexit 0\n",
+			command:          "RunDelete",
+			args:             []string{"0,1"},
+			expectedStderr:   "on-delete hook rejected task 1: keep tasks stay",
+			expectedExitCode: 2,
+			check: func(t *testing.T, db io.Database) {
+				if db.Tasks[0].IsDeleted || db.Tasks[1].IsDeleted {
+					t.Errorf("expected the whole delete to be cancelled")
+				}
+			},
+		},
+		{
+			name:             "timeout",
+			hook:             "on-add",
+			script:           "sleep 5\n",
+			timeout:          "200ms",
+			command:          "RunAdd",
+			args:             []string{"Slow"},
+			expectedStderr:   "on-add hook did not finish within the timeout",
+			expectedExitCode: 1,
+		},
+		{
+			name:    "not executable",
+			hook:    "on-add",
+			script:  "exit 1\n",
+			mode:    0644,
+			command: "RunAdd",
+			args:    []string{"Buy milk"},
+			check: func(t *testing.T, db io.Database) {
+				if len(db.Tasks) != 3 {
+					t.Errorf("expected a hook that is not executable to be skipped")
+				}
+			},
+		},
+		{
+			name:             "invalid output",
+			hook:             "on-add",
+			script:           "echo '{\"priority\": \"urgent\"}'\n",
+			command:          "RunAdd",
+			args:             []string{"Buy milk"},
+			expectedStderr:   `on-add hook set invalid priority "urgent"`,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{Size: 2, Tasks: []io.Task{
+				{ID: 0, UID: "a", Title: "OPS-1 Deploy", Custom: map[string]string{"team": "ops", "size": "s"}},
+				{ID: 1, UID: "b", Title: "OPS-2 Backups", Tags: []string{"keep"}},
+			}})
+
+			mode := tc.mode
+
+			if mode == 0 {
+				mode = 0755
+			}
+
+			writeHook(t, dbFile, tc.hook, tc.script, mode)
+			writeConfig(t, dbFile, `{"hooks": {"dir": "hooks", "timeout": "`+tc.timeout+`"}}`)
+
+			_, stderr, exitCode := runTestCommand(t, tc.command, tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+
+			if tc.check != nil {
+				tc.check(t, readTestDB(t, dbFile))
+			}
+		})
+	}
+}
+
+func TestHooksSkipMerges(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "a", Title: "Local"}}})
+	otherFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "b", Title: "Remote"}}})
+
+	writeHook(t, dbFile, "on-add", "exit 1\n", 0755)
+	writeConfig(t, dbFile, `{"hooks": {"dir": "hooks"}}`)
+
+	_, stderr, exitCode := runTestCommand(t, "RunMerge", []string{otherFile}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("expected the merge not to run hooks, got exit code %d: %s", exitCode, stderr)
+	}
+
+	if db := readTestDB(t, dbFile); len(db.Tasks) != 2 {
+		t.Errorf("expected the remote task to be merged, got %+v", db.Tasks)
+	}
+}
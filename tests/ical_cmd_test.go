@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/ical"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestRunIcalExport(t *testing.T) {
+	due := time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)
+	deletedAt := time.Now()
+	initialDB := io.Database{
+		Size: 1,
+		Tasks: []io.Task{
+			{ID: 0, UID: "abc123", Title: "Pay rent; today", Description: "Line one\nLine two", Date: time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC), Due: &due, Priority: io.PriorityHigh, Recurrence: "FREQ=MONTHLY"},
+			{ID: 1, UID: "gone", Title: "Trashed", IsDeleted: true, DeletedAt: &deletedAt},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   []string
+		unexpectedStdout []string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name: "export active tasks",
+			args: []string{"export"},
+			expectedStdout: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VTODO",
+				"UID:abc123",
+				`SUMMARY:Pay rent\; today`,
+				`DESCRIPTION:Line one\nLine two`,
+				"DUE:20250301T170000Z",
+				"PRIORITY:1",
+				"RRULE:FREQ=MONTHLY",
+				"STATUS:NEEDS-ACTION",
+				"END:VCALENDAR",
+			},
+			unexpectedStdout: []string{"UID:gone"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "export with trash",
+			args:             []string{"export", "--trash"},
+			expectedStdout:   []string{"UID:gone", "STATUS:CANCELLED"},
+			expectedExitCode: 0,
+		},
+		{
+			name:             "missing subcommand",
+			args:             []string{},
+			expectedStderr:   "unfilled arguments",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown subcommand",
+			args:             []string{"sync"},
+			expectedStderr:   `unknown ical command "sync"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "import without input",
+			args:             []string{"import"},
+			expectedStderr:   "Usage of ical import:|unfilled arguments",
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, initialDB)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunIcal", tc.args, dbFile)
+
+			for _, expected := range tc.expectedStdout {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, unexpected := range tc.unexpectedStdout {
+				if strings.Contains(stdout, unexpected) {
+					t.Errorf("expected stdout not to contain %q, got %q", unexpected, stdout)
+				}
+			}
+
+			if tc.expectedStderr != "" {
+				for _, expected := range strings.Split(tc.expectedStderr, "|") {
+					if !strings.Contains(stderr, expected) {
+						t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+					}
+				}
+			}
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+		})
+	}
+}
+
+func TestRunIcalRoundTrip(t *testing.T) {
+	due := time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)
+	source := setupTestDB(t, io.Database{
+		Size: 2,
+		Tasks: []io.Task{
+			{ID: 0, UID: "first", Title: "First", Description: strings.Repeat("long, text ", 20), Date: time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC), Due: &due, Priority: io.PriorityLow},
+			{ID: 1, Title: "Legacy task without uid", Date: time.Date(2025, 2, 2, 8, 0, 0, 0, time.UTC)},
+		},
+	})
+	calendar := filepath.Join(t.TempDir(), "tasks.ics")
+
+	_, stderr, exitCode := runTestCommand(t, "RunIcal", []string{"export", "-o", calendar}, source)
+
+	if exitCode != 0 {
+		t.Fatalf("export failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	target := setupTestDB(t, io.Database{})
+
+	for run := 0; run < 2; run++ {
+		stdout, stderr, exitCode := runTestCommand(t, "RunIcal", []string{"import", "-i", calendar}, target)
+
+		if exitCode != 0 {
+			t.Fatalf("import %d failed with exit code %d: %s", run, exitCode, stderr)
+		}
+
+		if run == 1 && !strings.Contains(stdout, "Added: 0") {
+			t.Errorf("expected second import to add nothing, got %q", stdout)
+		}
+	}
+
+	db := readTestDB(t, target)
+
+	if len(db.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks after importing twice, got %d", len(db.Tasks))
+	}
+
+	first := db.Tasks[0]
+
+	if first.UID != "first" || first.Description != strings.Repeat("long, text ", 20) {
+		t.Errorf("folded description or uid not round-tripped: %+v", first)
+	}
+
+	if first.Due == nil || !first.Due.Equal(due) {
+		t.Errorf("due date not round-tripped, got %v", first.Due)
+	}
+
+	if first.Priority != io.PriorityLow {
+		t.Errorf("priority got %q, want %q", first.Priority, io.PriorityLow)
+	}
+
+	if db.Size != 2 {
+		t.Errorf("size got %d, want 2", db.Size)
+	}
+}
+
+func TestRunIcalLegacyTaskKeepsUID(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Legacy", Date: time.Date(2025, 2, 2, 8, 0, 0, 0, time.UTC)}}})
+	calendar := filepath.Join(t.TempDir(), "tasks.ics")
+
+	if _, stderr, exitCode := runTestCommand(t, "RunIcal", []string{"export", "-o", calendar}, dbFile); exitCode != 0 {
+		t.Fatalf("export failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if _, stderr, exitCode := runTestCommand(t, "RunChange", []string{"0", "--title", "Renamed"}, dbFile); exitCode != 0 {
+		t.Fatalf("change failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunIcal", []string{"import", "-i", calendar}, dbFile)
+
+	if exitCode != 0 || !strings.Contains(stdout, "Added: 0") {
+		t.Errorf("expected the edited task to be matched by its UID, got exit code %d: %s%s", exitCode, stdout, stderr)
+	}
+
+	if db := readTestDB(t, dbFile); len(db.Tasks) != 1 || db.Tasks[0].UID == "" {
+		t.Errorf("expected one task with a saved UID, got %+v", db.Tasks)
+	}
+}
+
+func TestIcalDecodeSkipsEvents(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Meeting\r\nEND:VEVENT\r\n" +
+		"BEGIN:VTODO\r\nUID:x\r\nSUMMARY:Todo\r\nDUE;VALUE=DATE:20250102\r\nSTATUS:CANCELLED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	tasks, err := ical.Decode(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+
+	if len(tasks) != 1 || tasks[0].Title != "Todo" || !tasks[0].IsDeleted {
+		t.Fatalf("Decode() got %+v", tasks)
+	}
+
+	if tasks[0].Due == nil || tasks[0].Due.Format("2006-01-02") != "2025-01-02" {
+		t.Errorf("Decode() due got %v", tasks[0].Due)
+	}
+}
+
+func TestIcalCategories(t *testing.T) {
+	var out bytes.Buffer
+	tasks := []io.Task{{UID: "c1", Title: "Buy milk", Tags: []string{"errands", "a,b"}, Contexts: []string{"home"}}}
+
+	err := ical.Encode(&out, tasks)
+
+	if err != nil {
+		t.Fatalf("Encode() returned an unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), `CATEGORIES:errands,a\,b,@home`) {
+		t.Errorf("Encode() categories missing, got %q", out.String())
+	}
+
+	decoded, err := ical.Decode(&out)
+
+	if err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded[0].Tags, tasks[0].Tags) || !reflect.DeepEqual(decoded[0].Contexts, tasks[0].Contexts) {
+		t.Errorf("Decode() tags %q contexts %q, want %q %q", decoded[0].Tags, decoded[0].Contexts, tasks[0].Tags, tasks[0].Contexts)
+	}
+}
+
+func TestIcalAlarms(t *testing.T) {
+	var out bytes.Buffer
+	due := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	tasks := []io.Task{{UID: "a1", Title: "Call", Description: "Ask about the invoice", Due: &due, Remind: []string{"1d2h-before", "due"}}}
+
+	err := ical.Encode(&out, tasks)
+
+	if err != nil {
+		t.Fatalf("Encode() returned an unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "TRIGGER;RELATED=END:-P1DT2H\r\n") {
+		t.Errorf("Encode() alarm missing, got %q", out.String())
+	}
+
+	decoded, err := ical.Decode(&out)
+
+	if err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded[0].Remind, tasks[0].Remind) {
+		t.Errorf("Decode() remind got %q, want %q", decoded[0].Remind, tasks[0].Remind)
+	}
+
+	if decoded[0].Description != tasks[0].Description {
+		t.Errorf("Decode() took the alarm description %q for the task's", decoded[0].Description)
+	}
+}
@@ -62,9 +62,17 @@ func TestAddTask(t *testing.T) {
 
 			if !tc.expectError {
 				resultDB := readTestDB(t, dbFile)
+
+				if added := resultDB.Tasks[len(resultDB.Tasks)-1]; added.UID == "" {
+					t.Errorf("AddTask() stored the new task without a UID")
+				}
+
 				// We don't care about the date, so we ignore it in comparison
 				for i := range resultDB.Tasks {
 					resultDB.Tasks[i].Date = time.Time{}
+
+					resultDB.Tasks[i].UID = ""
+					resultDB.Tasks[i].Clocks = nil
 				}
 				if !reflect.DeepEqual(resultDB.Size, tc.expectedDB.Size) {
 					t.Errorf("AddTask() got size = %v, want %v", resultDB.Size, tc.expectedDB.Size)
@@ -93,13 +101,13 @@ func TestReadTask(t *testing.T) {
 			initialDB: io.Database{
 				Size: 2,
 				Tasks: []io.Task{
-					{ID: 0, Title: "Active Task", IsDeleted: false},
-					{ID: 1, Title: "Deleted Task", IsDeleted: true, DeletedAt: &deletedAt},
+					{ID: 0, UID: "a", Title: "Active Task", IsDeleted: false},
+					{ID: 1, UID: "b", Title: "Deleted Task", IsDeleted: true, DeletedAt: &deletedAt},
 				},
 			},
 			expectedDB: io.Database{
 				Size:  2, // ReadTask doesn't filter size
-				Tasks: []io.Task{{ID: 0, Title: "Active Task", IsDeleted: false}},
+				Tasks: []io.Task{{ID: 0, UID: "a", Title: "Active Task", IsDeleted: false}},
 			},
 			expectError: false,
 		},
@@ -404,13 +412,17 @@ func TestCleanUp(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			dbFile := setupTestDB(t, initialDB)
 
-			err := io.CleanUp(dbFile, tc.retention)
+			removed, err := io.CleanUp(dbFile, tc.retention)
 
 			if (err != nil) != tc.expectError {
 				t.Errorf("CleanUp() error = %v, expectError %v", err, tc.expectError)
 				return
 			}
 
+			if removed != len(initialDB.Tasks)-len(tc.expectedTaskIDs) {
+				t.Errorf("CleanUp() removed = %v, want %v", removed, len(initialDB.Tasks)-len(tc.expectedTaskIDs))
+			}
+
 			if !tc.expectError {
 				db := readTestDB(t, dbFile)
 				var resultingIDs []int
@@ -0,0 +1,76 @@
+//go:build unix
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestLockedDatabase(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{})
+
+	holder, err := os.OpenFile(dbFile+".lock", os.O_CREATE|os.O_RDWR, 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer holder.Close()
+
+	err = syscall.Flock(int(holder.Fd()), syscall.LOCK_EX)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := io.LockTimeout
+	io.LockTimeout = 50 * time.Millisecond
+	defer func() { io.LockTimeout = timeout }()
+
+	err = io.AddTask(io.Task{Title: "Blocked"}, dbFile)
+
+	if !errors.Is(err, io.ErrLocked) {
+		t.Fatalf("AddTask() on locked database error = %v, want ErrLocked", err)
+	}
+
+	syscall.Flock(int(holder.Fd()), syscall.LOCK_UN)
+
+	err = io.AddTask(io.Task{Title: "Unblocked"}, dbFile)
+
+	if err != nil {
+		t.Errorf("AddTask() after unlock error = %v", err)
+	}
+}
+
+func TestRewriteHoldsLock(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{})
+	done := make(chan error)
+
+	err := io.Rewrite(dbFile, func(db io.Database) io.Database {
+		go func() { done <- io.AddTask(io.Task{Title: "Concurrent"}, dbFile) }()
+		time.Sleep(50 * time.Millisecond)
+
+		db.Add(io.Task{Title: "Merged"})
+		return db
+	})
+
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("AddTask() during Rewrite() error = %v", err)
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if len(db.Tasks) != 2 || db.Tasks[0].Title != "Merged" || db.Tasks[1].Title != "Concurrent" {
+		t.Errorf("expected the concurrent add to wait for the rewrite, got %+v", db.Tasks)
+	}
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/crdt"
+	"github.com/tristnaja/taski/internal/hlc"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// replicas creates a database with the given titles and an identical copy
+// of it, as if data.json had been copied to a second machine.
+func replicas(t *testing.T, titles ...string) (string, string) {
+	t.Helper()
+	laptop := setupTestDB(t, io.Database{})
+
+	for _, title := range titles {
+		if err := io.AddTask(io.Task{Title: title, Description: title + " desc"}, laptop); err != nil {
+			t.Fatalf("AddTask() returned an unexpected error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(laptop)
+
+	if err != nil {
+		t.Fatalf("failed to read replica: %v", err)
+	}
+
+	desktop := filepath.Join(t.TempDir(), "desktop.json")
+
+	if err := os.WriteFile(desktop, content, 0644); err != nil {
+		t.Fatalf("failed to copy replica: %v", err)
+	}
+
+	return laptop, desktop
+}
+
+func TestRunMerge(t *testing.T) {
+	laptop, desktop := replicas(t, "Groceries", "Taxes")
+
+	mustDo(t, io.ChangeTask(laptop, 0, "Groceries for the week", ""))
+	mustDo(t, io.ChangeTask(desktop, 0, "", "Milk, eggs, bread"))
+	mustDo(t, io.RemoveTask(desktop, 1))
+	mustDo(t, io.AddTask(io.Task{Title: "Call mom"}, desktop))
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunMerge", []string{desktop}, laptop)
+
+	if exitCode != 0 {
+		t.Fatalf("merge failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	for _, expected := range []string{"Added: 1", "Updated: 2", "Removed: 0"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+		}
+	}
+
+	db := readTestDB(t, laptop)
+
+	if len(db.Tasks) != 3 || db.Size != 2 {
+		t.Fatalf("expected 3 tasks with 2 active, got %+v", db)
+	}
+
+	if db.Tasks[0].Title != "Groceries for the week" || db.Tasks[0].Description != "Milk, eggs, bread" {
+		t.Errorf("concurrent field edits not both kept, got %+v", db.Tasks[0])
+	}
+
+	if !db.Tasks[1].IsDeleted || db.Tasks[2].Title != "Call mom" {
+		t.Errorf("delete or add not merged, got %+v", db.Tasks[1:])
+	}
+
+	// Merging the other way converges on the same content.
+	_, stderr, exitCode = runTestCommand(t, "RunMerge", []string{laptop}, desktop)
+
+	if exitCode != 0 {
+		t.Fatalf("reverse merge failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	other := readTestDB(t, desktop)
+
+	for i := range db.Tasks {
+		a, b := db.Tasks[i], findByUID(other, db.Tasks[i].UID)
+
+		if a.Title != b.Title || a.Description != b.Description || a.IsDeleted != b.IsDeleted {
+			t.Errorf("replicas diverged: %+v vs %+v", a, b)
+		}
+	}
+}
+
+func TestRunMergeTombstones(t *testing.T) {
+	laptop, desktop := replicas(t, "Keep", "Purge me", "Edit after purge")
+
+	mustDo(t, io.RemoveTask(laptop, 1))
+	mustDo(t, io.RemoveTask(laptop, 2))
+	_, err := io.CleanUp(laptop, 0)
+	mustDo(t, err)
+	// An edit made after the purge wins over the tombstone.
+	mustDo(t, io.ChangeTask(desktop, 2, "Still needed", ""))
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunMerge", []string{laptop}, desktop)
+
+	if exitCode != 0 {
+		t.Fatalf("merge failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "Removed: 1") {
+		t.Errorf("expected the purged task to be removed, got %q", stdout)
+	}
+
+	db := readTestDB(t, desktop)
+
+	if len(db.Tasks) != 2 || db.Tasks[1].Title != "Still needed" {
+		t.Fatalf("expected purged task removed and edited task kept, got %+v", db.Tasks)
+	}
+
+	_, _, exitCode = runTestCommand(t, "RunMerge", []string{desktop}, laptop)
+
+	if exitCode != 0 {
+		t.Fatalf("reverse merge failed with exit code %d", exitCode)
+	}
+
+	if db := readTestDB(t, laptop); len(db.Tasks) != 2 {
+		t.Errorf("purged task came back on the purging replica, got %+v", db.Tasks)
+	}
+}
+
+func TestRunMergeErrors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{name: "missing file argument", args: []string{}, expectedStderr: "Usage of merge:|unfilled arguments", expectedExitCode: 2},
+		{name: "nonexistent file", args: []string{filepath.Join(t.TempDir(), "nope.json")}, expectedStderr: "opening other database", expectedExitCode: 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{})
+
+			_, stderr, exitCode := runTestCommand(t, "RunMerge", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+		})
+	}
+}
+
+func TestRunMergeNotes(t *testing.T) {
+	laptop, desktop := replicas(t, "Fix login")
+	asked := io.Note{Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local), Text: "Asked ops"}
+	logs := io.Note{Time: time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local), Text: "Logs received"}
+
+	setNotes := func(fileName string, notes ...io.Note) {
+		mustDo(t, io.Transact(fileName, func(db *io.Database) error {
+			return db.Update(0, func(task *io.Task) error {
+				task.Notes = notes
+				return nil
+			})
+		}))
+	}
+
+	texts := func(fileName string) string {
+		var texts []string
+
+		for _, note := range readTestDB(t, fileName).Tasks[0].Notes {
+			texts = append(texts, note.Text)
+		}
+
+		return strings.Join(texts, ", ")
+	}
+
+	setNotes(laptop, asked)
+	setNotes(desktop, logs)
+
+	if _, stderr, exitCode := runTestCommand(t, "RunMerge", []string{desktop}, laptop); exitCode != 0 {
+		t.Fatalf("merge failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if got := texts(laptop); got != "Asked ops, Logs received" {
+		t.Errorf("expected notes added on both replicas to be kept, got %q", got)
+	}
+
+	setNotes(desktop)
+
+	if _, stderr, exitCode := runTestCommand(t, "RunMerge", []string{desktop}, laptop); exitCode != 0 {
+		t.Fatalf("merge failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	if got := texts(laptop); got != "Asked ops" {
+		t.Errorf("expected the note removed on the desktop to be removed, got %q", got)
+	}
+}
+
+func TestCRDTMergeIsIdempotent(t *testing.T) {
+	stamp := hlc.Timestamp{Wall: 10, Node: "a"}
+	db := io.Database{Size: 1, Node: "a", Clock: stamp, Tasks: []io.Task{
+		{ID: 0, UID: "x", Title: "Same", Clocks: map[string]hlc.Timestamp{io.FieldTitle: stamp}},
+	}}
+
+	merged, stats := crdt.Merge(db, db)
+
+	if stats != (crdt.Stats{}) || len(merged.Tasks) != 1 || merged.Tasks[0].Title != "Same" {
+		t.Errorf("merging a replica with itself changed it: %+v %+v", stats, merged)
+	}
+
+	if !merged.Clock.After(db.Clock) {
+		t.Errorf("clock did not advance after receiving, got %v", merged.Clock)
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func findByUID(db io.Database, uid string) io.Task {
+	for _, task := range db.Tasks {
+		if task.UID == uid {
+			return task
+		}
+	}
+
+	return io.Task{}
+}
@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/gitsync"
+	"github.com/tristnaja/taski/internal/ical"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+func TestRunNote(t *testing.T) {
+	written := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)
+
+	testCases := []struct {
+		name             string
+		command          string
+		args             []string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+		check            func(t *testing.T, db io.Database)
+	}{
+		{
+			name:           "adds a note",
+			command:        "RunNote",
+			args:           []string{"0", "Waiting", "for", "logs"},
+			expectedStdout: "Added Note to Task 0:",
+			check: func(t *testing.T, db io.Database) {
+				task := db.Tasks[0]
+
+				if len(task.Notes) != 2 || task.Notes[1].Text != "Waiting for logs" || task.Notes[1].Time.Before(written) {
+					t.Errorf("expected the note to be appended, got %+v", task.Notes)
+				}
+
+				if task.Description != "Users cannot log in" {
+					t.Errorf("expected the description to stay, got %q", task.Description)
+				}
+
+				if _, found := task.Clocks[io.FieldNotes]; !found {
+					t.Errorf("expected the notes to be stamped, got clocks %v", task.Clocks)
+				}
+			},
+		},
+		{
+			name:           "lists the notes",
+			command:        "RunNote",
+			args:           []string{"0"},
+			expectedStdout: "Notes on Task 0, Fix login:\n1. 02 Mar 2026, 09:30  Reproduced on staging\n",
+		},
+		{
+			name:           "no notes",
+			command:        "RunNote",
+			args:           []string{"1"},
+			expectedStdout: "No notes on task 1 yet.",
+		},
+		{
+			name:           "edits a note",
+			command:        "RunNote",
+			args:           []string{"0", "--edit", "1", "Reproduced everywhere"},
+			expectedStdout: "Edited Note 1 of Task 0:\n02 Mar 2026, 09:30  Reproduced everywhere\n",
+			check: func(t *testing.T, db io.Database) {
+				if note := db.Tasks[0].Notes[0]; note.Text != "Reproduced everywhere" || !note.Time.Equal(written) {
+					t.Errorf("expected the text to change and the time to stay, got %+v", note)
+				}
+			},
+		},
+		{
+			name:           "removes a note",
+			command:        "RunNote",
+			args:           []string{"0", "--remove", "1"},
+			expectedStdout: "Removed Note 1 of Task 0.",
+			check: func(t *testing.T, db io.Database) {
+				if db.Tasks[0].Notes != nil {
+					t.Errorf("expected no notes left, got %+v", db.Tasks[0].Notes)
+				}
+			},
+		},
+		{
+			name:             "note out of range",
+			command:          "RunNote",
+			args:             []string{"0", "--remove", "3"},
+			expectedStderr:   "task 0 has no note 3",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "edit without text",
+			command:          "RunNote",
+			args:             []string{"0", "--edit", "1"},
+			expectedStderr:   "--edit needs the new text",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "task in trash",
+			command:          "RunNote",
+			args:             []string{"2", "Too late"},
+			expectedStderr:   "task 2 is in trash",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "invalid id",
+			command:          "RunNote",
+			args:             []string{"first", "Hello"},
+			expectedStderr:   `invalid task id "first"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:           "show lists notes after the description",
+			command:        "RunShow",
+			args:           []string{"0", "--raw"},
+			expectedStdout: "Users cannot log in\n\nNotes:\n  02 Mar 2026, 09:30  Reproduced on staging\n",
+		},
+		{
+			name:           "bare words search notes",
+			command:        "RunView",
+			args:           []string{"--where", "staging"},
+			expectedStdout: "Fix login",
+			unexpected:     "Water plants",
+		},
+		{
+			name:           "note key",
+			command:        "RunView",
+			args:           []string{"--where", "note:staging"},
+			expectedStdout: "Fix login",
+		},
+		{
+			name:           "csv export",
+			command:        "RunExport",
+			args:           []string{"--format", "csv"},
+			expectedStdout: ",pending," + written.Format(time.RFC3339) + " Reproduced on staging\n",
+		},
+		{
+			name:           "markdown export",
+			command:        "RunExport",
+			args:           []string{"--format", "markdown"},
+			expectedStdout: "  Users cannot log in\n  - _2026-03-02 09:30_ Reproduced on staging\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{Size: 2, Tasks: []io.Task{
+				{ID: 0, Title: "Fix login", Description: "Users cannot log in", Notes: []io.Note{{Time: written, Text: "Reproduced on staging"}}},
+				{ID: 1, Title: "Water plants"},
+				{ID: 2, Title: "Old", IsDeleted: true},
+			}})
+
+			stdout, stderr, exitCode := runTestCommand(t, tc.command, tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, stdout)
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got %q", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+
+			if tc.check != nil {
+				tc.check(t, readTestDB(t, dbFile))
+			}
+		})
+	}
+}
+
+func TestNoteTimesRoundTrip(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Fix login"}}})
+
+	for _, text := range []string{"Asked ops", "Logs received"} {
+		if _, stderr, exitCode := runTestCommand(t, "RunNote", []string{"0", text}, dbFile); exitCode != 0 {
+			t.Fatalf("note failed with exit code %d: %s", exitCode, stderr)
+		}
+	}
+
+	notes := readTestDB(t, dbFile).Tasks[0].Notes
+
+	if len(notes) != 2 || notes[0].Time.Nanosecond() != 0 || !notes[1].Time.After(notes[0].Time) {
+		t.Fatalf("expected two notes in seconds of their own, got %+v", notes)
+	}
+
+	var buf bytes.Buffer
+
+	if err := ical.Encode(&buf, []io.Task{{UID: "a", Title: "Fix login", Notes: notes}}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	tasks, err := ical.Decode(&buf)
+
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if !slices.EqualFunc(tasks[0].Notes, notes, io.Note.Equal) {
+		t.Errorf("expected the notes to survive iCalendar, got %+v, want %+v", tasks[0].Notes, notes)
+	}
+}
+
+func TestGitSyncMergeNotes(t *testing.T) {
+	first := io.Note{Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Text: "Asked ops"}
+	ours := io.Note{Time: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), Text: "Logs received"}
+	theirs := io.Note{Time: time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), Text: "Ops are on it"}
+	task := io.Task{UID: "a", Title: "Fix login", Notes: []io.Note{first}}
+
+	base := io.Database{Size: 1, Tasks: []io.Task{task}}
+	task.Notes = []io.Note{first, ours}
+	local := io.Database{Size: 1, Tasks: []io.Task{task}}
+	task.Notes = []io.Note{first, theirs}
+	remote := io.Database{Size: 1, Tasks: []io.Task{task}}
+
+	merged, conflicts := gitsync.Merge(base, local, remote)
+
+	if conflicts != 0 {
+		t.Errorf("expected notes added on both sides not to conflict, got %d conflicts", conflicts)
+	}
+
+	var texts []string
+
+	for _, note := range merged.Tasks[0].Notes {
+		texts = append(texts, note.Text)
+	}
+
+	if got := strings.Join(texts, ", "); got != "Asked ops, Ops are on it, Logs received" {
+		t.Errorf("expected every note, oldest first, got %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tristnaja/taski/app/cmd"
+	"github.com/tristnaja/taski/internal/io"
+)
+
+// installPlugins puts shell scripts named taski-<name> in a directory at
+// the front of PATH.
+func installPlugins(t *testing.T, scripts map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, script := range scripts {
+		mode := os.FileMode(0755)
+
+		if strings.HasSuffix(name, ".off") {
+			name, mode = strings.TrimSuffix(name, ".off"), 0644
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, cmd.PluginPrefix+name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+			t.Fatalf("failed to write plugin: %v", err)
+		}
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	installPlugins(t, map[string]string{
+		"hello":   "echo \"hello $* db=$TASKI_DB config=$TASKI_CONFIG\"\n[ \"$1\" = fail ] && exit 7\nexit 0\n",
+		"view":    "echo shadowed\n",
+		"off.off": "echo off\n",
+	})
+
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Task"}}})
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "runs with the database and config",
+			args:           []string{"hello", "a", "b"},
+			expectedStdout: "hello a b db=" + dbFile + " config=" + filepath.Join(filepath.Dir(dbFile), "config.json"),
+		},
+		{
+			name:             "passes the exit code on",
+			args:             []string{"hello", "fail"},
+			expectedStdout:   "hello fail",
+			expectedStderr:   "taski-hello exited with status 7",
+			expectedExitCode: 7,
+		},
+		{
+			name:           "built-in commands win",
+			args:           []string{"view"},
+			expectedStdout: "Task",
+			unexpected:     "shadowed",
+		},
+		{
+			name:             "not executable",
+			args:             []string{"off"},
+			expectedStderr:   `unknown command "off"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:           "listed in help",
+			args:           []string{"help"},
+			expectedStdout: "External Commands:\n  hello\n",
+			unexpected:     "  view\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runTestCommand(t, "Execute", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, stdout)
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got %q", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+
+	if got := cmd.Complete([]string{"hel"}, dbFile); !slices.Contains(got, "hello") {
+		t.Errorf("expected hello among the completions, got %q", got)
+	}
+
+	if command := cmd.Lookup("hello"); command == nil || !command.Mutates {
+		t.Errorf("expected an external command that counts as mutating, got %+v", command)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var got []string
+
+	cmd.Register(&cmd.Command{
+		Name:    "register-test",
+		Aliases: []string{"rt"},
+		Usage:   "register-test <words>",
+		Summary: "Command registered by a test.",
+		Run: func(args []string, fileName string) error {
+			got = args
+			return nil
+		},
+	})
+
+	if err := cmd.Execute([]string{"rt", "a", "b"}, "unused.json"); err != nil || strings.Join(got, " ") != "a b" {
+		t.Errorf("expected the registered command to run with its arguments, got %q, %v", got, err)
+	}
+
+	for _, name := range []string{"register-test", "view", "ls"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected Register to panic for the taken name %q", name)
+				}
+			}()
+
+			cmd.Register(&cmd.Command{Name: name, Run: func([]string, string) error { return nil }})
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/quickadd"
+)
+
+func TestQuickAddParse(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) *time.Time {
+		date := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected io.Task
+	}{
+		{
+			name:     "everything",
+			input:    "Buy milk +errands @home due:tomorrow !high",
+			expected: io.Task{Title: "Buy milk", Tags: []string{"errands"}, Contexts: []string{"home"}, Due: day(2026, 3, 5), Priority: io.PriorityHigh},
+		},
+		{
+			name:     "markers between words",
+			input:    "Call +work the @phone plumber !l",
+			expected: io.Task{Title: "Call the plumber", Tags: []string{"work"}, Contexts: []string{"phone"}, Priority: io.PriorityLow},
+		},
+		{
+			name:     "duplicate tags",
+			input:    "Plan +trip +trip +family",
+			expected: io.Task{Title: "Plan", Tags: []string{"trip", "family"}},
+		},
+		{
+			name:     "absolute date with time",
+			input:    "Dentist due:2026-04-01T09:30",
+			expected: io.Task{Title: "Dentist", Due: func() *time.Time { d := time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC); return &d }()},
+		},
+		{
+			name:     "weekday is the next one",
+			input:    "Standup due:wed",
+			expected: io.Task{Title: "Standup", Due: day(2026, 3, 11)},
+		},
+		{
+			name:     "relative days",
+			input:    "Renew due:3d",
+			expected: io.Task{Title: "Renew", Due: day(2026, 3, 7)},
+		},
+		{
+			name:     "escaped and lone markers",
+			input:    `Vote \+1 on + proposal @`,
+			expected: io.Task{Title: "Vote +1 on + proposal @"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task, err := quickadd.Parse(tc.input, now)
+
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tc.input, err)
+			}
+
+			if !reflect.DeepEqual(task, tc.expected) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tc.input, task, tc.expected)
+			}
+		})
+	}
+
+	for _, input := range []string{"+only @tags", "Milk due:someday", "Milk !urgent"} {
+		if _, err := quickadd.Parse(input, now); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/remind"
+)
+
+func remindDB() io.Database {
+	soon := time.Now().Add(20 * time.Minute)
+	later := time.Now().Add(3 * time.Hour)
+
+	return io.Database{
+		Size: 3,
+		Tasks: []io.Task{
+			{ID: 0, UID: "call", Title: "Call mum", Due: &soon, Remind: []string{"30m-before"}},
+			{ID: 1, UID: "ship", Title: "Ship", Due: &later, Remind: []string{"1h-before"}},
+			{ID: 2, UID: "old", Title: "Old", Due: &soon, Remind: []string{"30m-before"}, Status: io.StatusDone},
+		},
+	}
+}
+
+func writeConfig(t *testing.T, dbFile string, config string) {
+	t.Helper()
+
+	if config == "" {
+		return
+	}
+
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dbFile), "config.json"), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestRunDaemon(t *testing.T) {
+	dbFile := setupTestDB(t, remindDB())
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunDaemon", []string{"--once"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stderr)
+	}
+
+	if !strings.Contains(stdout, "Reminder: #0 Call mum, Due ") || strings.Contains(stdout, "Ship") || strings.Contains(stdout, "Old") {
+		t.Errorf("expected only the reminder of task 0, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runTestCommand(t, "RunDaemon", []string{"--once"}, dbFile)
+
+	if strings.Contains(stdout, "Reminder:") {
+		t.Errorf("expected a delivered reminder not to be sent again, got:\n%s", stdout)
+	}
+}
+
+func TestRunDaemonWebhook(t *testing.T) {
+	received := make(chan map[string]any, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	}))
+	defer server.Close()
+
+	dbFile := setupTestDB(t, remindDB())
+	writeConfig(t, dbFile, `{"reminders": {"notifiers": ["webhook"], "webhook": "`+server.URL+`"}}`)
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunDaemon", []string{"--once"}, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stderr)
+	}
+
+	if strings.Contains(stdout, "Reminder:") {
+		t.Errorf("expected no stdout reminder with only the webhook notifier, got:\n%s", stdout)
+	}
+
+	select {
+	case payload := <-received:
+		if payload["title"] != "Call mum" || payload["offset"] != "30m-before" || payload["uid"] != "call" {
+			t.Errorf("unexpected webhook payload %v", payload)
+		}
+	default:
+		t.Errorf("expected the webhook to be called")
+	}
+}
+
+func TestRunDaemonErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedStderr string
+	}{
+		{"unknown notifier", []string{"--once", "--notify", "pager"}, `unknown notifier "pager"`},
+		{"webhook without url", []string{"--once", "--notify", "stdout,webhook"}, "needs reminders.webhook"},
+		{"short interval", []string{"--interval", "10ms"}, "--interval must be at least 1s"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, remindDB())
+
+			_, stderr, exitCode := runTestCommand(t, "RunDaemon", tc.args, dbFile)
+
+			if exitCode != 2 {
+				t.Errorf("expected exit code 2, got %d: %s", exitCode, stderr)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRunRemind(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		config           string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "list",
+			args:           []string{"list"},
+			expectedStdout: "Upcoming Reminders:\n  #1   |  Ship  (1h before due)",
+			unexpected:     "Call mum",
+		},
+		{
+			name:           "ls alias",
+			args:           []string{"ls", "-n", "1"},
+			expectedStdout: "Ship",
+		},
+		{
+			name:           "snooze",
+			args:           []string{"snooze", "0", "--for", "1h"},
+			expectedStdout: "Snoozed Task until |Index: 0",
+		},
+		{
+			name:             "snooze done task",
+			args:             []string{"snooze", "2"},
+			expectedStderr:   "task 2 is done",
+			expectedExitCode: 2,
+		},
+		{
+			name:             "snooze unknown task",
+			args:             []string{"snooze", "9"},
+			expectedStderr:   "invalid index 9",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "invalid snooze length",
+			args:             []string{"snooze", "0", "--for", "soon"},
+			expectedStderr:   `invalid --for "soon"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "unknown subcommand",
+			args:             []string{"clear"},
+			expectedStderr:   `unknown remind subcommand "clear", usable: list, snooze`,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, remindDB())
+			writeConfig(t, dbFile, tc.config)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunRemind", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got:\n%s", expected, stdout)
+				}
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got:\n%s", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRemindSnoozeHoldsBackReminders(t *testing.T) {
+	dbFile := setupTestDB(t, remindDB())
+
+	if _, stderr, exitCode := runTestCommand(t, "RunRemind", []string{"snooze", "0", "--for", "5m"}, dbFile); exitCode != 0 {
+		t.Fatalf("snooze failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	stdout, _, _ := runTestCommand(t, "RunDaemon", []string{"--once"}, dbFile)
+
+	if strings.Contains(stdout, "Reminder:") {
+		t.Errorf("expected the snoozed reminder to be held back, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runTestCommand(t, "RunRemind", []string{"list"}, dbFile)
+
+	if !strings.Contains(stdout, "Call mum  (snoozed)") {
+		t.Errorf("expected the snoozed reminder in the list, got:\n%s", stdout)
+	}
+}
+
+func TestRemindDefaults(t *testing.T) {
+	due := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	tasks := []io.Task{
+		{ID: 0, UID: "a", Title: "Default", Due: &due},
+		{ID: 1, UID: "b", Title: "Own", Due: &due, Remind: []string{"1h-before"}},
+		{ID: 2, UID: "c", Title: "Off", Due: &due, Remind: []string{"none"}},
+	}
+	state := remind.State{Sent: map[string]time.Time{}, Snoozed: map[string]time.Time{}}
+
+	reminders := remind.Schedule(tasks, []string{"due"}, state, due.Add(-24*time.Hour), due)
+
+	if len(reminders) != 2 || reminders[0].Task.ID != 1 || reminders[1].Task.ID != 0 || !reminders[1].At.Equal(due) {
+		t.Errorf("Schedule() = %+v", reminders)
+	}
+}
+
+func TestRemindNormalize(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{value: "30m, 1h-before,30m-before", expected: []string{"30m-before", "1h-before"}},
+		{value: "90m-before,due", expected: []string{"1h30m-before", "due"}},
+		{value: "1w", expected: []string{"7d-before"}},
+		{value: "none,1h", expected: []string{"none"}},
+		{value: "soon", wantErr: true},
+		{value: "5-before", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := remind.Normalize(tc.value)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Normalize(%q) = %q, want %q", tc.value, got, tc.expected)
+			}
+		})
+	}
+}
@@ -57,21 +57,21 @@ func TestRunRestore(t *testing.T) {
 			args:             []string{},
 			initialDB:        io.Database{},
 			expectedStderr:   "Usage of restore:|unfilled arguments",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
 			name:             "all and index flag",
 			args:             []string{"-a", "-i", "0"},
 			initialDB:        io.Database{},
 			expectedStderr:   "When restoring all, you do not need to input an index",
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
 			name:             "invalid index",
 			args:             []string{"-i", "99"},
 			initialDB:        io.Database{Tasks: []io.Task{{ID: 0, IsDeleted: true, DeletedAt: &deletedAt}}},
 			expectedStderr:   "restoring task: restoring task: invalid index 99: out of bounds",
-			expectedExitCode: 1,
+			expectedExitCode: 3,
 		},
 	}
 
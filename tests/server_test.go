@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/server"
+)
+
+const testToken = "s3cret"
+
+func startTestServer(t *testing.T, db io.Database) (*httptest.Server, string) {
+	t.Helper()
+	dbFile := setupTestDB(t, db)
+
+	srv, err := server.New(dbFile, testToken)
+
+	if err != nil {
+		t.Fatalf("server.New() returned an unexpected error: %v", err)
+	}
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts, dbFile
+}
+
+func doRequest(t *testing.T, ts *httptest.Server, method string, path string, body string, token string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := ts.Client().Do(req)
+
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	var content bytes.Buffer
+	_, err = content.ReadFrom(resp.Body)
+
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	return resp, content.String()
+}
+
+func TestServerEndpoints(t *testing.T) {
+	deletedAt := time.Now()
+	initialDB := io.Database{
+		Size: 1,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Active", Description: "Visible"},
+			{ID: 1, Title: "Trashed", IsDeleted: true, DeletedAt: &deletedAt},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "missing token", method: "GET", path: "/tasks", expectedStatus: http.StatusUnauthorized, expectedBody: "invalid or missing token"},
+		{name: "wrong token", method: "GET", path: "/tasks", token: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "list tasks", method: "GET", path: "/tasks", token: testToken, expectedStatus: http.StatusOK, expectedBody: `"title":"Active"`},
+		{name: "list trash", method: "GET", path: "/trash", token: testToken, expectedStatus: http.StatusOK, expectedBody: `"title":"Trashed"`},
+		{name: "get task", method: "GET", path: "/tasks/0", token: testToken, expectedStatus: http.StatusOK, expectedBody: `"description":"Visible"`},
+		{name: "get out of bounds", method: "GET", path: "/tasks/99", token: testToken, expectedStatus: http.StatusNotFound, expectedBody: "out of bounds"},
+		{name: "get trashed task", method: "GET", path: "/tasks/1", token: testToken, expectedStatus: http.StatusNotFound, expectedBody: "in trash"},
+		{name: "get invalid id", method: "GET", path: "/tasks/abc", token: testToken, expectedStatus: http.StatusBadRequest},
+		{name: "add task", method: "POST", path: "/tasks", body: `{"title":"New","description":"Via API","priority":"high"}`, token: testToken, expectedStatus: http.StatusCreated, expectedBody: `"id":2`},
+		{name: "add without title", method: "POST", path: "/tasks", body: `{"description":"x"}`, token: testToken, expectedStatus: http.StatusBadRequest, expectedBody: "title is required"},
+		{name: "add malformed json", method: "POST", path: "/tasks", body: `{`, token: testToken, expectedStatus: http.StatusBadRequest},
+		{name: "add bad priority", method: "POST", path: "/tasks", body: `{"title":"x","priority":"urgent"}`, token: testToken, expectedStatus: http.StatusBadRequest},
+		{name: "change task", method: "PATCH", path: "/tasks/0", body: `{"title":"Renamed"}`, token: testToken, expectedStatus: http.StatusOK, expectedBody: `"title":"Renamed"`},
+		{name: "change nothing", method: "PATCH", path: "/tasks/0", body: `{}`, token: testToken, expectedStatus: http.StatusBadRequest},
+		{name: "complete task", method: "PATCH", path: "/tasks/0", body: `{"status":"done"}`, token: testToken, expectedStatus: http.StatusOK, expectedBody: `"status":"done"`},
+		{name: "change invalid status", method: "PATCH", path: "/tasks/0", body: `{"status":"Done!"}`, token: testToken, expectedStatus: http.StatusBadRequest, expectedBody: "invalid status"},
+		{name: "complete trashed task", method: "PATCH", path: "/tasks/1", body: `{"status":"done"}`, token: testToken, expectedStatus: http.StatusNotFound, expectedBody: "in trash"},
+		{name: "change out of bounds", method: "PATCH", path: "/tasks/5", body: `{"title":"x"}`, token: testToken, expectedStatus: http.StatusNotFound},
+		{name: "delete task", method: "DELETE", path: "/tasks/0", token: testToken, expectedStatus: http.StatusNoContent},
+		{name: "delete out of bounds", method: "DELETE", path: "/tasks/-1", token: testToken, expectedStatus: http.StatusNotFound},
+		{name: "restore task", method: "POST", path: "/tasks/1/restore", token: testToken, expectedStatus: http.StatusOK, expectedBody: `"is_deleted":false`},
+		{name: "restore out of bounds", method: "POST", path: "/tasks/7/restore", token: testToken, expectedStatus: http.StatusNotFound},
+		{name: "method not allowed", method: "PUT", path: "/tasks/0", token: testToken, expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, _ := startTestServer(t, initialDB)
+
+			resp, body := doRequest(t, ts, tc.method, tc.path, tc.body, tc.token)
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d (%s)", tc.expectedStatus, resp.StatusCode, body)
+			}
+
+			if tc.expectedBody != "" && !strings.Contains(body, tc.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestServerHookVeto(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	ts, dbFile := startTestServer(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Active"}}})
+	writeHook(t, dbFile, "on-add", "echo 'no new tasks today' >&2; exit 1\n", 0755)
+	writeHook(t, dbFile, "on-modify", "echo '{\"priority\": \"urgent\"}'\n", 0755)
+	writeConfig(t, dbFile, `{"hooks": {"dir": "hooks"}}`)
+
+	resp, body := doRequest(t, ts, "POST", "/tasks", `{"title":"New"}`, testToken)
+
+	if resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(body, "no new tasks today") {
+		t.Errorf("expected a vetoed add to return 422 with the reason, got %d (%s)", resp.StatusCode, body)
+	}
+
+	resp, body = doRequest(t, ts, "PATCH", "/tasks/0", `{"title":"Renamed"}`, testToken)
+
+	if resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(body, `invalid priority \"urgent\"`) {
+		t.Errorf("expected invalid hook output to return 422, got %d (%s)", resp.StatusCode, body)
+	}
+}
+
+func TestServerAddReturnsStoredTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	ts, dbFile := startTestServer(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Active"}}})
+	writeHook(t, dbFile, "on-add", "echo '{\"priority\": \"high\"}'\n", 0755)
+	writeConfig(t, dbFile, `{"hooks": {"dir": "hooks"}}`)
+
+	resp, body := doRequest(t, ts, "POST", "/tasks", `{"title":"New"}`, testToken)
+
+	var created io.Task
+
+	if err := json.Unmarshal([]byte(body), &created); err != nil {
+		t.Fatalf("failed to decode response %q: %v", body, err)
+	}
+
+	stored := readTestDB(t, dbFile).Tasks[created.ID]
+
+	if resp.StatusCode != http.StatusCreated || created.Priority != io.PriorityHigh || created.UID == "" || created.UID != stored.UID {
+		t.Errorf("expected the task as stored after the on-add hook, got %d %+v, stored %+v", resp.StatusCode, created, stored)
+	}
+}
+
+func TestServerSerializesConcurrentWrites(t *testing.T) {
+	ts, dbFile := startTestServer(t, io.Database{})
+	const clients = 25
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			resp, body := doRequest(t, ts, "POST", "/tasks", fmt.Sprintf(`{"title":"Task %d"}`, i), testToken)
+
+			if resp.StatusCode != http.StatusCreated {
+				t.Errorf("expected status 201, got %d (%s)", resp.StatusCode, body)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	db := readTestDB(t, dbFile)
+
+	if len(db.Tasks) != clients || db.Size != clients {
+		t.Fatalf("expected %d tasks, got %d (size %d)", clients, len(db.Tasks), db.Size)
+	}
+
+	for i, task := range db.Tasks {
+		if task.ID != i {
+			t.Errorf("task %d has id %d", i, task.ID)
+		}
+	}
+
+	_, body := doRequest(t, ts, "GET", "/tasks", "", testToken)
+	var tasks []io.Task
+
+	if err := json.Unmarshal([]byte(body), &tasks); err != nil || len(tasks) != clients {
+		t.Errorf("expected %d tasks from GET /tasks, got %d (%v)", clients, len(tasks), err)
+	}
+}
+
+func TestRunServeRequiresToken(t *testing.T) {
+	t.Setenv("TASKI_TOKEN", "")
+	dbFile := setupTestDB(t, io.Database{})
+
+	_, stderr, exitCode := runTestCommand(t, "RunServe", []string{"--addr", "127.0.0.1:0"}, dbFile)
+
+	if exitCode != 1 || !strings.Contains(stderr, "an API token is required") {
+		t.Errorf("expected missing token error, got exit %d: %q", exitCode, stderr)
+	}
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/markdown"
+)
+
+const richDescription = "Prepare the **release** notes:\n\n- [x] changelog\n- [ ] blog post, see [the draft](https://example.com/draft)\n\n```\ngo test ./...\n```"
+
+func TestRunShow(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	db := io.Database{
+		Size: 1,
+		Tasks: []io.Task{
+			{ID: 0, UID: "abc", Title: "Ship 2.0", Description: richDescription, Priority: io.PriorityHigh, Tags: []string{"release"}, Status: io.StatusDone},
+			{ID: 1, Title: "Old idea", IsDeleted: true, DeletedAt: &deletedAt},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{
+			name:           "rendered description",
+			args:           []string{"0"},
+			expectedStdout: "Ship 2.0\nIndex: 0\nStatus: done|Priority: high|Labels: +release|UID: abc|Prepare the release notes:|[x] changelog|[ ] blog post, see the draft (https://example.com/draft)|    go test ./...",
+		},
+		{
+			name:           "raw description",
+			args:           []string{"0", "--raw"},
+			expectedStdout: "Prepare the **release** notes:|- [ ] blog post, see [the draft](https://example.com/draft)|```",
+		},
+		{
+			name:           "task in trash",
+			args:           []string{"-i", "1"},
+			expectedStdout: "Status: in trash since 02 Jan 2026",
+		},
+		{
+			name:             "out of bounds",
+			args:             []string{"5"},
+			expectedStderr:   "showing task: invalid index 5: out of bounds",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "missing id",
+			args:             []string{},
+			expectedStderr:   "Usage of show:|unfilled arguments",
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, db)
+
+			stdout, stderr, exitCode := runTestCommand(t, "RunShow", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			for _, expected := range strings.Split(tc.expectedStdout, "|") {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
+				}
+			}
+
+			for _, expected := range strings.Split(tc.expectedStderr, "|") {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("expected stderr to contain %q, got %q", expected, stderr)
+				}
+			}
+
+			if strings.Contains(stdout, "\x1b[") {
+				t.Errorf("expected no ANSI styling when stdout is not a terminal, got %q", stdout)
+			}
+		})
+	}
+}
+
+func TestMarkdownRender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		width    int
+		color    bool
+		expected string
+	}{
+		{
+			name:     "wraps paragraphs",
+			source:   "one two three\nfour five six",
+			width:    10,
+			expected: "one two\nthree four\nfive six\n",
+		},
+		{
+			name:     "hanging indent for list items",
+			source:   "- alpha beta gamma\n  - nested item",
+			width:    12,
+			expected: "• alpha beta\n  gamma\n  • nested\n    item\n",
+		},
+		{
+			name:     "code blocks are not wrapped",
+			source:   "```go\nfmt.Println(\"a long line\")\n```",
+			width:    10,
+			expected: "    fmt.Println(\"a long line\")\n",
+		},
+		{
+			name:     "headings, quotes and rules",
+			source:   "# Title\n\n> quoted\n\n---\nafter",
+			expected: "Title\n\n│ quoted\n\n" + strings.Repeat("─", 40) + "\nafter\n",
+		},
+		{
+			name:     "ansi styling",
+			source:   "**bold** and `code`",
+			color:    true,
+			expected: "\x1b[1mbold\x1b[0m and \x1b[36mcode\x1b[0m\n",
+		},
+		{
+			name:     "unclosed markers and snake_case stay as typed",
+			source:   "2 * 3 in some_file_name",
+			expected: "2 * 3 in some_file_name\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := markdown.Render(tc.source, tc.width, tc.color)
+
+			if got != tc.expected {
+				t.Errorf("Render() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
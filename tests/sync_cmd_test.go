@@ -0,0 +1,282 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	taskio "github.com/tristnaja/taski/internal/io"
+)
+
+// fakeCalDAV is a minimal stand-in for a Radicale style task collection
+// mounted at /tasks/.
+type fakeCalDAV struct {
+	mu      sync.Mutex
+	objects map[string]string
+	etags   map[string]string
+	version int
+}
+
+func newFakeCalDAV(t *testing.T) (*fakeCalDAV, *httptest.Server) {
+	t.Helper()
+	fake := &fakeCalDAV{objects: map[string]string{}, etags: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+func (f *fakeCalDAV) set(path string, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version++
+	f.objects[path] = body
+	f.etags[path] = fmt.Sprintf(`"v%d"`, f.version)
+}
+
+func (f *fakeCalDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+
+	if !ok || user != "alice" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.Path
+
+	switch r.Method {
+	case "PROPFIND":
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:">`)
+		b.WriteString(`<d:response><d:href>/tasks/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+
+		for href, etag := range f.etags {
+			fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag><d:resourcetype/></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, href, etag)
+		}
+
+		b.WriteString(`</d:multistatus>`)
+		w.WriteHeader(http.StatusMultiStatus)
+		io.WriteString(w, b.String())
+	case http.MethodGet:
+		body, found := f.objects[path]
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("ETag", f.etags[path])
+		io.WriteString(w, body)
+	case http.MethodPut:
+		current, exists := f.etags[path]
+
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		if match := r.Header.Get("If-Match"); match != "" && match != current {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		f.version++
+		f.objects[path] = string(body)
+		f.etags[path] = fmt.Sprintf(`"v%d"`, f.version)
+		w.Header().Set("ETag", f.etags[path])
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if match := r.Header.Get("If-Match"); match != "" && match != f.etags[path] {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		delete(f.objects, path)
+		delete(f.etags, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func vtodo(uid string, summary string, modified string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:" + uid + "\r\nSUMMARY:" + summary +
+		"\r\nLAST-MODIFIED:" + modified + "\r\nSTATUS:NEEDS-ACTION\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+}
+
+func runSync(t *testing.T, server *httptest.Server, dbFile string, extra ...string) string {
+	t.Helper()
+	args := append([]string{"caldav", "--url", server.URL + "/tasks/", "-u", "alice", "--password", "secret"}, extra...)
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunSync", args, dbFile)
+
+	if exitCode != 0 {
+		t.Fatalf("sync failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	return stdout
+}
+
+func writeTestDB(t *testing.T, dbFile string, db taskio.Database) {
+	t.Helper()
+	content, err := json.Marshal(db)
+
+	if err != nil {
+		t.Fatalf("failed to encode db: %v", err)
+	}
+
+	if err := os.WriteFile(dbFile, content, 0644); err != nil {
+		t.Fatalf("failed to write db: %v", err)
+	}
+}
+
+func TestRunSyncCalDAV(t *testing.T) {
+	fake, server := newFakeCalDAV(t)
+	dbFile := setupTestDB(t, taskio.Database{
+		Size: 2,
+		Tasks: []taskio.Task{
+			{ID: 0, UID: "local-1", Title: "Local one"},
+			{ID: 1, UID: "local-2", Title: "Local two"},
+		},
+	})
+
+	stdout := runSync(t, server, dbFile)
+
+	if !strings.Contains(stdout, "Pushed: 2") || len(fake.objects) != 2 {
+		t.Fatalf("expected 2 tasks pushed, got %q and %d objects", stdout, len(fake.objects))
+	}
+
+	stdout = runSync(t, server, dbFile)
+
+	if !strings.Contains(stdout, "Pushed: 0") || !strings.Contains(stdout, "Pulled: 0") {
+		t.Errorf("expected second sync to be a no-op, got %q", stdout)
+	}
+
+	fake.set("/tasks/local-1.ics", vtodo("local-1", "Edited remotely", "20300101T000000Z"))
+	fake.set("/tasks/phone.ics", vtodo("phone", "Added on phone", "20300101T000000Z"))
+
+	stdout = runSync(t, server, dbFile)
+
+	if !strings.Contains(stdout, "Pulled: 2") {
+		t.Errorf("expected 2 tasks pulled, got %q", stdout)
+	}
+
+	db := readTestDB(t, dbFile)
+
+	if len(db.Tasks) != 3 || db.Tasks[0].Title != "Edited remotely" || db.Tasks[2].UID != "phone" {
+		t.Fatalf("remote changes not applied, got %+v", db.Tasks)
+	}
+
+	// CleanUp purging a task after the retention maps to a remote deletion.
+	db.Tasks = db.Tasks[:2]
+	db.Size = 2
+	writeTestDB(t, dbFile, db)
+
+	stdout = runSync(t, server, dbFile)
+
+	if !strings.Contains(stdout, "Deleted Remotely: 1") {
+		t.Errorf("expected 1 remote deletion, got %q", stdout)
+	}
+
+	if _, found := fake.objects["/tasks/phone.ics"]; found {
+		t.Errorf("purged task still exists on the server")
+	}
+
+	// Removing an object remotely moves the local task to trash.
+	fake.mu.Lock()
+	delete(fake.objects, "/tasks/local-2.ics")
+	delete(fake.etags, "/tasks/local-2.ics")
+	fake.mu.Unlock()
+
+	runSync(t, server, dbFile)
+	db = readTestDB(t, dbFile)
+
+	if !db.Tasks[1].IsDeleted {
+		t.Errorf("task deleted on the server was not moved to trash")
+	}
+}
+
+func TestRunSyncCalDAVConflict(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        string
+		expectedLocal string
+	}{
+		{name: "local wins", policy: "local", expectedLocal: "Local edit"},
+		{name: "remote wins", policy: "remote", expectedLocal: "Remote edit"},
+		{name: "newest wins", policy: "newest", expectedLocal: "Remote edit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake, server := newFakeCalDAV(t)
+			dbFile := setupTestDB(t, taskio.Database{Size: 1, Tasks: []taskio.Task{{ID: 0, UID: "shared", Title: "Original"}}})
+
+			runSync(t, server, dbFile)
+
+			db := readTestDB(t, dbFile)
+			db.Tasks[0].Title = "Local edit"
+			writeTestDB(t, dbFile, db)
+			fake.set("/tasks/shared.ics", vtodo("shared", "Remote edit", "20300101T000000Z"))
+
+			stdout := runSync(t, server, dbFile, "--conflict", tc.policy)
+
+			if !strings.Contains(stdout, "Conflicts ("+tc.policy+" wins): 1") {
+				t.Errorf("expected one conflict, got %q", stdout)
+			}
+
+			db = readTestDB(t, dbFile)
+
+			if db.Tasks[0].Title != tc.expectedLocal {
+				t.Errorf("local title got %q, want %q", db.Tasks[0].Title, tc.expectedLocal)
+			}
+
+			if !strings.Contains(fake.objects["/tasks/shared.ics"], "SUMMARY:"+tc.expectedLocal) {
+				t.Errorf("remote object not converged, got %q", fake.objects["/tasks/shared.ics"])
+			}
+		})
+	}
+}
+
+func TestRunSyncErrors(t *testing.T) {
+	_, server := newFakeCalDAV(t)
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedStderr   string
+		expectedExitCode int
+	}{
+		{name: "missing backend", args: []string{}, expectedStderr: "unfilled arguments", expectedExitCode: 2},
+		{name: "unknown backend", args: []string{"dropbox"}, expectedStderr: `unknown sync backend "dropbox"`, expectedExitCode: 2},
+		{name: "missing url", args: []string{"caldav"}, expectedStderr: "no CalDAV url", expectedExitCode: 2},
+		{name: "bad policy", args: []string{"caldav", "--url", server.URL, "-c", "coinflip"}, expectedStderr: `unknown conflict policy "coinflip"`, expectedExitCode: 1},
+		{name: "wrong credentials", args: []string{"caldav", "--url", server.URL, "-u", "alice", "--password", "nope"}, expectedStderr: "401 Unauthorized", expectedExitCode: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, taskio.Database{})
+
+			_, stderr, exitCode := runTestCommand(t, "RunSync", tc.args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+		})
+	}
+}
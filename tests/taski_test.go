@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/pkg/taski"
+)
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := taski.Open(setupTestDB(t, io.Database{}))
+
+	first, err := repo.Add(ctx, taski.Task{Title: "Write report", Description: "quarterly"})
+
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if first.ID != 0 || first.UID == "" || first.Date.IsZero() {
+		t.Errorf("Add() = %+v, want ID 0 with UID and date", first)
+	}
+
+	_, err = repo.Add(ctx, taski.Task{Title: "Buy milk", Priority: taski.PriorityLow})
+
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	title := "Write annual report"
+	changed, err := repo.Update(ctx, 0, taski.Update{Title: &title})
+
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if changed.Title != title || changed.Description != "quarterly" {
+		t.Errorf("Update() = %+v", changed)
+	}
+
+	deleted, err := repo.Delete(ctx, 1)
+
+	if err != nil || !deleted.IsDeleted {
+		t.Fatalf("Delete() = %+v, %v", deleted, err)
+	}
+
+	active, err := repo.List(ctx, taski.ListOptions{})
+
+	if err != nil || len(active) != 1 {
+		t.Fatalf("List() = %d tasks, %v; want 1", len(active), err)
+	}
+
+	all, err := repo.List(ctx, taski.ListOptions{IncludeTrash: true, Where: "milk"})
+
+	if err != nil || len(all) != 1 || all[0].Title != "Buy milk" {
+		t.Fatalf("List(trash, milk) = %+v, %v", all, err)
+	}
+
+	_, err = repo.Update(ctx, 1, taski.Update{Title: &title})
+
+	if !errors.Is(err, taski.ErrNotFound) {
+		t.Errorf("Update() on trashed task error = %v, want ErrNotFound", err)
+	}
+
+	restored, err := repo.Restore(ctx, 1)
+
+	if err != nil || restored.IsDeleted {
+		t.Fatalf("Restore() = %+v, %v", restored, err)
+	}
+
+	got, err := repo.Get(ctx, 1)
+
+	if err != nil || got.Title != "Buy milk" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+}
+
+func TestRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	repo := taski.Open(setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, Title: "Only"}}}))
+	title := "x"
+
+	testCases := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{name: "get out of bounds", run: func() error { _, err := repo.Get(ctx, 5); return err }, want: taski.ErrOutOfBounds},
+		{name: "get negative", run: func() error { _, err := repo.Get(ctx, -1); return err }, want: taski.ErrOutOfBounds},
+		{name: "update out of bounds", run: func() error { _, err := repo.Update(ctx, 3, taski.Update{Title: &title}); return err }, want: taski.ErrOutOfBounds},
+		{name: "update nothing", run: func() error { _, err := repo.Update(ctx, 0, taski.Update{}); return err }, want: taski.ErrNoChange},
+		{name: "delete out of bounds", run: func() error { _, err := repo.Delete(ctx, 9); return err }, want: taski.ErrOutOfBounds},
+		{name: "restore out of bounds", run: func() error { _, err := repo.Restore(ctx, 9); return err }, want: taski.ErrOutOfBounds},
+		{name: "add without title", run: func() error { _, err := repo.Add(ctx, taski.Task{}); return err }, want: taski.ErrInvalid},
+		{name: "add bad priority", run: func() error { _, err := repo.Add(ctx, taski.Task{Title: "a", Priority: "urgent"}); return err }, want: taski.ErrInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.run()
+
+			if !errors.Is(err, tc.want) {
+				t.Errorf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := repo.Add(cancelled, taski.Task{Title: "late"})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Add() with cancelled context error = %v, want context.Canceled", err)
+	}
+
+	tasks, _ := repo.List(ctx, taski.ListOptions{})
+
+	if len(tasks) != 1 {
+		t.Errorf("cancelled Add() wrote to the database: %d tasks", len(tasks))
+	}
+}
+
+func TestRepositoryAddReturnsStoredTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	dbFile := setupTestDB(t, io.Database{})
+	writeHook(t, dbFile, "on-add", "echo '{\"priority\": \"high\"}'\n", 0755)
+	writeConfig(t, dbFile, `{"hooks": {"dir": "hooks"}}`)
+
+	added, err := taski.Open(dbFile).Add(context.Background(), taski.Task{Title: "Triage"})
+
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if stored := readTestDB(t, dbFile).Tasks[0]; added.Priority != "high" || added.UID != stored.UID {
+		t.Errorf("Add() = %+v, want the stored task %+v", added, stored)
+	}
+}
+
+func TestRepositoryPurge(t *testing.T) {
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	repo := taski.Open(setupTestDB(t, io.Database{
+		Size: 1,
+		Tasks: []io.Task{
+			{ID: 0, Title: "Active"},
+			{ID: 1, Title: "Old", IsDeleted: true, DeletedAt: &old},
+			{ID: 2, Title: "Recent", IsDeleted: true, DeletedAt: &recent},
+		},
+	}))
+
+	purged, err := repo.Purge(context.Background(), 30*24*time.Hour)
+
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	if purged != 1 {
+		t.Errorf("Purge() = %d, want 1", purged)
+	}
+
+	tasks, _ := repo.List(context.Background(), taski.ListOptions{IncludeTrash: true})
+
+	if len(tasks) != 2 {
+		t.Errorf("after Purge() got %d tasks, want 2", len(tasks))
+	}
+}
@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/filter"
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/uda"
+)
+
+const fieldsConfig = `{"fields": {
+	"ticket": {"type": "string", "column": true},
+	"severity": {"type": "enum", "values": ["sev1", "sev2", "sev3"], "column": true},
+	"points": {"type": "number"},
+	"estimate": {"type": "duration"},
+	"deadline": {"type": "date"}
+}}`
+
+func TestCustomFields(t *testing.T) {
+	testCases := []struct {
+		name             string
+		command          string
+		args             []string
+		expectedStdout   string
+		unexpected       string
+		expectedStderr   string
+		expectedExitCode int
+		script           string
+		check            func(t *testing.T, db io.Database)
+	}{
+		{
+			name:           "add normalizes values",
+			command:        "RunAdd",
+			args:           []string{"Fix login", "--set", "severity=SEV1", "--set", "estimate=90m", "--set", "points=2.50"},
+			expectedStdout: "Fields: estimate=1h30m, points=2.5, severity=sev1",
+			check: func(t *testing.T, db io.Database) {
+				custom := db.Tasks[3].Custom
+
+				if custom["severity"] != "sev1" || custom["estimate"] != "1h30m" || custom["points"] != "2.5" {
+					t.Errorf("expected canonical values, got %v", custom)
+				}
+			},
+		},
+		{
+			name:             "add rejects an enum value",
+			command:          "RunAdd",
+			args:             []string{"Fix login", "--set", "severity=sev9"},
+			expectedStderr:   `invalid severity "sev9", usable: sev1, sev2, sev3`,
+			expectedExitCode: 2,
+		},
+		{
+			name:             "add rejects an unknown field",
+			command:          "RunAdd",
+			args:             []string{"Fix login", "--set", "customer=acme"},
+			expectedStderr:   `unknown field "customer", usable: deadline, estimate, points, severity, ticket`,
+			expectedExitCode: 2,
+		},
+		{
+			name:    "change sets and removes fields",
+			command: "RunChange",
+			args:    []string{"0", "--set", "ticket=OPS-9", "--set", "points="},
+			check: func(t *testing.T, db io.Database) {
+				custom := db.Tasks[0].Custom
+
+				if custom["ticket"] != "OPS-9" || custom["severity"] != "sev2" {
+					t.Errorf("expected ticket to change and severity to stay, got %v", custom)
+				}
+
+				if _, found := custom["points"]; found {
+					t.Errorf("expected points to be removed, got %v", custom)
+				}
+
+				if _, found := db.Tasks[0].Clocks[io.FieldCustom]; !found {
+					t.Errorf("expected the custom fields to be stamped, got clocks %v", db.Tasks[0].Clocks)
+				}
+			},
+		},
+		{
+			name:             "change rejects a number",
+			command:          "RunChange",
+			args:             []string{"0", "--set", "points=many"},
+			expectedStderr:   `invalid number "many" for points`,
+			expectedExitCode: 2,
+		},
+		{
+			name:           "view shows field columns",
+			command:        "RunView",
+			args:           []string{},
+			expectedStdout: "SEVERITY  TICKET  TITLE",
+		},
+		{
+			name:           "view filters by enum order",
+			command:        "RunView",
+			args:           []string{"--where", "severity:<=sev2", "--columns", "points"},
+			expectedStdout: "ID  STATUS  POINTS  TITLE\n0   [ ]     8       Deploy\n2   [ ]     13      Migrate\n",
+		},
+		{
+			name:           "view filters by duration",
+			command:        "RunView",
+			args:           []string{"--where", "estimate:<1d", "--compact"},
+			expectedStdout: "Backups",
+			unexpected:     "Deploy",
+		},
+		{
+			name:           "view sorts numbers descending with empty values last",
+			command:        "RunView",
+			args:           []string{"--sort", "-points", "--columns", "points", "--compact"},
+			expectedStdout: "2  [ ]  Migrate\n0  [ ]  Deploy\n1  [ ]  Backups\n",
+		},
+		{
+			name:             "view rejects a sort key",
+			command:          "RunView",
+			args:             []string{"--sort", "customer"},
+			expectedStderr:   `unknown sort key "customer"`,
+			expectedExitCode: 2,
+		},
+		{
+			name:           "show lists fields",
+			command:        "RunShow",
+			args:           []string{"0"},
+			expectedStdout: "points: 8\nseverity: sev2\nticket: OPS-1\n",
+		},
+		{
+			name:           "csv export adds columns",
+			command:        "RunExport",
+			args:           []string{"--format", "csv"},
+			expectedStdout: ",status,notes,estimate,points,severity,ticket\n",
+		},
+		{
+			name:           "todo.txt export",
+			command:        "RunExport",
+			args:           []string{"--format", "todotxt"},
+			expectedStdout: "Backups estimate:2h severity:sev3 id:1",
+		},
+		{
+			name:    "batch sets fields",
+			command: "RunBatch",
+			script:  "add Audit --set ticket=SEC-4\nchange --where severity:sev3 --set points=1\n",
+			check: func(t *testing.T, db io.Database) {
+				if db.Tasks[3].Custom["ticket"] != "SEC-4" || db.Tasks[1].Custom["points"] != "1" {
+					t.Errorf("expected the batch to set fields, got %v and %v", db.Tasks[3].Custom, db.Tasks[1].Custom)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbFile := setupTestDB(t, io.Database{Size: 3, Tasks: []io.Task{
+				{ID: 0, Title: "Deploy", Custom: map[string]string{"ticket": "OPS-1", "severity": "sev2", "points": "8"}},
+				{ID: 1, Title: "Backups", Custom: map[string]string{"severity": "sev3", "estimate": "2h"}},
+				{ID: 2, Title: "Migrate", Custom: map[string]string{"severity": "sev1", "points": "13", "estimate": "3d"}},
+			}})
+
+			writeConfig(t, dbFile, fieldsConfig)
+
+			args := tc.args
+
+			if tc.script != "" {
+				script := filepath.Join(t.TempDir(), "script.txt")
+
+				if err := os.WriteFile(script, []byte(tc.script), 0644); err != nil {
+					t.Fatalf("failed to write script: %v", err)
+				}
+
+				args = []string{"-f", script}
+			}
+
+			stdout, stderr, exitCode := runTestCommand(t, tc.command, args, dbFile)
+
+			if exitCode != tc.expectedExitCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedExitCode, exitCode, stderr)
+			}
+
+			if !strings.Contains(stdout, tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, stdout)
+			}
+
+			if tc.unexpected != "" && strings.Contains(stdout, tc.unexpected) {
+				t.Errorf("expected stdout not to contain %q, got %q", tc.unexpected, stdout)
+			}
+
+			if !strings.Contains(stderr, tc.expectedStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tc.expectedStderr, stderr)
+			}
+
+			if tc.check != nil {
+				tc.check(t, readTestDB(t, dbFile))
+			}
+		})
+	}
+}
+
+func TestCustomFieldsConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields uda.Fields
+		err    string
+	}{
+		{name: "valid", fields: uda.Fields{"customer": {Type: uda.String}, "size": {Type: uda.Enum, Values: []string{"s", "m"}}}},
+		{name: "reserved name", fields: uda.Fields{"due": {Type: uda.Date}}, err: `field name "due" is taken by a built-in field`},
+		{name: "invalid name", fields: uda.Fields{"Ticket URL": {Type: uda.String}}, err: `invalid field name "Ticket URL"`},
+		{name: "invalid type", fields: uda.Fields{"cost": {Type: "money"}}, err: `field "cost" has invalid type "money"`},
+		{name: "enum without values", fields: uda.Fields{"size": {Type: uda.Enum}}, err: `enum field "size" has no values`},
+		{name: "values on a string", fields: uda.Fields{"size": {Type: uda.String, Values: []string{"s"}}}, err: `field "size" has values but is not an enum`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fields.Check()
+
+			if tc.err == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCustomFieldsCompare(t *testing.T) {
+	fields := uda.Fields{
+		"points":   {Type: uda.Number},
+		"estimate": {Type: uda.Duration},
+		"deadline": {Type: uda.Date},
+		"size":     {Type: uda.Enum, Values: []string{"small", "large"}},
+	}
+
+	testCases := []struct {
+		name     string
+		a, b     string
+		expected int
+	}{
+		{name: "points", a: "9", b: "10", expected: -1},
+		{name: "estimate", a: "1d", b: "20h", expected: 1},
+		{name: "deadline", a: "2026-03-01", b: "2026-03-01T09:00", expected: -1},
+		{name: "size", a: "large", b: "small", expected: 1},
+		{name: "points", a: "", b: "1", expected: 1},
+	}
+
+	for _, tc := range testCases {
+		if got := fields.Compare(tc.name, tc.a, tc.b); got != tc.expected {
+			t.Errorf("Compare(%v, %q, %q) = %d, expected %d", tc.name, tc.a, tc.b, got, tc.expected)
+		}
+	}
+
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.Local)
+
+	if got, err := fields.Normalize("deadline", "tomorrow", now); err != nil || got != "2026-03-05" {
+		t.Errorf("expected tomorrow to be stored as 2026-03-05, got %q, %v", got, err)
+	}
+
+	match, err := filter.ParseFields("points:>=10 size:large", fields)
+
+	if err != nil {
+		t.Fatalf("failed to parse filter: %v", err)
+	}
+
+	if !match.Match(io.Task{Custom: map[string]string{"points": "12", "size": "large"}}) || match.Match(io.Task{Custom: map[string]string{"points": "9", "size": "large"}}) {
+		t.Errorf("expected points:>=10 to compare numbers")
+	}
+}
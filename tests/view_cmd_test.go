@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/table"
 )
 
 func TestRunView(t *testing.T) {
+	due := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
 	testCases := []struct {
 		name           string
 		args           []string
@@ -19,8 +21,37 @@ func TestRunView(t *testing.T) {
 		expectedExitCode int
 	}{
 		{
-			name: "view with tasks",
+			name: "view as table",
 			args: []string{},
+			initialDB: io.Database{
+				Size: 2,
+				Tasks: []io.Task{
+					{ID: 0, Title: "Write report", Priority: io.PriorityHigh, Tags: []string{"work"}, Due: &due},
+					{ID: 1, Title: "Buy milk", Status: io.StatusDone},
+				},
+			},
+			expectedStdout: []string{
+				"ID  STATUS  PRI   DUE         TAGS   TITLE\n",
+				"0   [ ]     high  2024-03-01  +work  Write report\n",
+				"1   [x]                              Buy milk\n",
+			},
+			expectedExitCode: 0,
+		},
+		{
+			name: "compact layout",
+			args: []string{"--compact"},
+			initialDB: io.Database{
+				Size:  1,
+				Tasks: []io.Task{{ID: 0, Title: "Write report", Tags: []string{"work"}, Due: &due}},
+			},
+			expectedStdout: []string{
+				"0  [ ]  Write report +work due:2024-03-01\n",
+			},
+			expectedExitCode: 0,
+		},
+		{
+			name: "long layout",
+			args: []string{"--long"},
 			initialDB: io.Database{
 				Size: 1,
 				Tasks: []io.Task{{
@@ -46,8 +77,7 @@ func TestRunView(t *testing.T) {
 				Tasks: []io.Task{},
 			},
 			expectedStdout: []string{
-				"Here is your Tasks:",
-				"You can Interact with your Tasks with:",
+				"No tasks yet.",
 			},
 			expectedExitCode: 0,
 		},
@@ -59,6 +89,10 @@ func TestRunView(t *testing.T) {
 
 			stdout, _, exitCode := runTestCommand(t, "RunView", tc.args, dbFile)
 
+			if strings.Contains(stdout, "You can Interact") || strings.Contains(stdout, "\x1b[") {
+				t.Errorf("expected no help footer or ANSI styling, got %q", stdout)
+			}
+
 			for _, expected := range tc.expectedStdout {
 				if !strings.Contains(stdout, expected) {
 					t.Errorf("expected stdout to contain %q, got %q", expected, stdout)
@@ -71,3 +105,37 @@ func TestRunView(t *testing.T) {
 		})
 	}
 }
+
+func TestRunViewTruncates(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{
+		{ID: 0, Title: "A very long task title that cannot fit in a narrow terminal"},
+	}})
+	t.Setenv("COLUMNS", "40")
+
+	stdout, _, _ := runTestCommand(t, "RunView", []string{}, dbFile)
+
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if width := len([]rune(line)); width > 40 {
+			t.Errorf("line is %d columns wide, want at most 40: %q", width, line)
+		}
+	}
+
+	if !strings.Contains(stdout, "A very long task title that…") {
+		t.Errorf("expected the title to be cut with an ellipsis, got %q", stdout)
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	tbl := table.Table{Columns: []table.Column{{Header: "ID"}, {Header: "TITLE", Flex: true}}}
+	tbl.Append(table.Cell{Text: "1"}, table.Cell{Text: "Overdue", Style: "1;31"})
+
+	var b strings.Builder
+
+	if err := tbl.Render(&b, 0, true, false); err != nil {
+		t.Fatalf("Render() returned an unexpected error: %v", err)
+	}
+
+	if expected := "1  \x1b[1;31mOverdue\x1b[0m\n"; b.String() != expected {
+		t.Errorf("Render() = %q, want %q", b.String(), expected)
+	}
+}
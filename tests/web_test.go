@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/server"
+)
+
+func TestWebUIAssets(t *testing.T) {
+	ts, _ := startTestServer(t, io.Database{})
+
+	testCases := []struct {
+		path         string
+		expectedType string
+		expectedBody string
+	}{
+		{path: "/", expectedType: "text/html", expectedBody: `<script src="app.js">`},
+		{path: "/app.js", expectedType: "javascript", expectedBody: "EventSource"},
+		{path: "/style.css", expectedType: "text/css", expectedBody: ".task"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			// Static assets are public; only the API needs the token.
+			resp, body := doRequest(t, ts, "GET", tc.path, "", "")
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", resp.StatusCode)
+			}
+
+			if !strings.Contains(resp.Header.Get("Content-Type"), tc.expectedType) {
+				t.Errorf("expected content type %q, got %q", tc.expectedType, resp.Header.Get("Content-Type"))
+			}
+
+			if !strings.Contains(body, tc.expectedBody) {
+				t.Errorf("expected body to contain %q", tc.expectedBody)
+			}
+		})
+	}
+}
+
+func TestWebEventsNotifyExternalChanges(t *testing.T) {
+	dbFile := setupTestDB(t, io.Database{})
+	srv, err := server.New(dbFile, testToken)
+
+	if err != nil {
+		t.Fatalf("server.New() returned an unexpected error: %v", err)
+	}
+
+	srv.PollInterval = 10 * time.Millisecond
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	resp, _ := doRequest(t, ts, "GET", "/events", "", "")
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected event stream to require a token, got %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/events?token="+testToken, nil)
+	stream, err := ts.Client().Do(req)
+
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+
+	defer stream.Body.Close()
+
+	reader := bufio.NewReader(stream.Body)
+
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("expected connection comment, got %q", line)
+	}
+
+	// Another process (here: the io package directly) modifies the file.
+	time.Sleep(20 * time.Millisecond)
+
+	if err := io.AddTask(io.Task{Title: "From the CLI"}, dbFile); err != nil {
+		t.Fatalf("AddTask() returned an unexpected error: %v", err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+
+		if err != nil {
+			t.Fatalf("event stream ended before a change event: %v", err)
+		}
+
+		if strings.TrimSpace(line) == "event: change" {
+			return
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tristnaja/taski/internal/io"
+	"github.com/tristnaja/taski/internal/webhook"
+)
+
+func TestEventsDiff(t *testing.T) {
+	deletedAt := time.Now()
+	old := io.Database{Tasks: []io.Task{
+		{ID: 0, UID: "a", Title: "Keep"},
+		{ID: 1, UID: "b", Title: "Rename me"},
+		{ID: 2, UID: "c", Title: "Delete me"},
+		{ID: 3, UID: "d", Title: "Restore me", IsDeleted: true, DeletedAt: &deletedAt},
+		{ID: 4, UID: "e", Title: "Purge me", IsDeleted: true, DeletedAt: &deletedAt},
+	}}
+	current := io.Database{Tasks: []io.Task{
+		{ID: 0, UID: "a", Title: "Keep"},
+		{ID: 1, UID: "b", Title: "Renamed", Status: io.StatusDone},
+		{ID: 2, UID: "c", Title: "Delete me", IsDeleted: true, DeletedAt: &deletedAt},
+		{ID: 3, UID: "d", Title: "Restore me"},
+		{ID: 4, UID: "f", Title: "New"},
+	}}
+
+	var got []string
+
+	for _, event := range io.Diff(old, current, time.Now()) {
+		got = append(got, event.Type+" "+event.Task.UID+" "+strings.Join(event.Fields, ","))
+	}
+
+	expected := []string{
+		"task.changed b title,status",
+		"task.deleted c ",
+		"task.restored d ",
+		"task.added f ",
+		"task.purged e ",
+	}
+
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Diff() = %q, want %q", got, expected)
+	}
+}
+
+// hookServer records the deliveries it receives and answers with status.
+type hookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func newHookServer(t *testing.T, status int) *hookServer {
+	s := &hookServer{status: status}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		json.NewDecoder(r.Body).Decode(&body)
+
+		s.mu.Lock()
+		s.received = append(s.received, r)
+		s.bodies = append(s.bodies, body)
+		s.mu.Unlock()
+
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestRunWebhookFlush(t *testing.T) {
+	server := newHookServer(t, http.StatusOK)
+	dbFile := setupTestDB(t, io.Database{})
+	writeConfig(t, dbFile, `{"webhooks": [{"url": "`+server.URL+`", "secret": "s3cret"}]}`)
+
+	if _, stderr, exitCode := runTestCommand(t, "RunAdd", []string{"Buy milk"}, dbFile); exitCode != 0 {
+		t.Fatalf("add failed with exit code %d: %s", exitCode, stderr)
+	}
+
+	outbox, err := webhook.Load(dbFile)
+
+	if err != nil || len(outbox.Deliveries) != 1 || outbox.Deliveries[0].Event.Type != io.EventAdded {
+		t.Fatalf("expected one task.added delivery in the outbox, got %+v, %v", outbox, err)
+	}
+
+	stdout, stderr, exitCode := runTestCommand(t, "RunWebhook", []string{"flush"}, dbFile)
+
+	if exitCode != 0 || !strings.Contains(stdout, "Delivered: 1, Pending: 0") {
+		t.Fatalf("flush got exit code %d, stdout %q, stderr %q", exitCode, stdout, stderr)
+	}
+
+	if len(server.received) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(server.received))
+	}
+
+	request, body := server.received[0], server.bodies[0]
+
+	if request.Header.Get("X-Taski-Event") != io.EventAdded || request.Header.Get("X-Taski-Delivery") != outbox.Deliveries[0].ID {
+		t.Errorf("unexpected headers %v", request.Header)
+	}
+
+	if signature := request.Header.Get("X-Taski-Signature"); signature != webhook.Sign("s3cret", body) {
+		t.Errorf("signature %q does not match the body", signature)
+	}
+
+	var event io.Event
+
+	if err := json.Unmarshal(body, &event); err != nil || event.Task.Title != "Buy milk" {
+		t.Errorf("unexpected payload %s: %v", body, err)
+	}
+
+	if outbox, _ := webhook.Load(dbFile); len(outbox.Deliveries) != 0 {
+		t.Errorf("expected an empty outbox after delivery, got %+v", outbox.Deliveries)
+	}
+}
+
+func TestRunWebhookRetry(t *testing.T) {
+	server := newHookServer(t, http.StatusServiceUnavailable)
+	dbFile := setupTestDB(t, io.Database{Size: 1, Tasks: []io.Task{{ID: 0, UID: "a", Title: "Task"}}})
+	writeConfig(t, dbFile, `{"webhooks": [{"url": "`+server.URL+`", "events": ["task.deleted"]}]}`)
+
+	runTestCommand(t, "RunDone", []string{"0"}, dbFile)
+	runTestCommand(t, "RunDelete", []string{"0"}, dbFile)
+
+	_, stderr, exitCode := runTestCommand(t, "RunWebhook", []string{"flush"}, dbFile)
+
+	if exitCode != 0 || !strings.Contains(stderr, "503 Service Unavailable") {
+		t.Errorf("expected a warning about the failed delivery, got exit code %d, stderr %q", exitCode, stderr)
+	}
+
+	outbox, _ := webhook.Load(dbFile)
+
+	if len(outbox.Deliveries) != 1 || outbox.Deliveries[0].Event.Type != io.EventDeleted || outbox.Deliveries[0].Attempts != 1 {
+		t.Fatalf("expected the task.deleted delivery kept with one attempt, got %+v", outbox.Deliveries)
+	}
+
+	if wait := time.Until(outbox.Deliveries[0].NextAttempt); wait < 20*time.Second {
+		t.Errorf("expected the retry to back off, next attempt in %v", wait)
+	}
+
+	stdout, _, _ := runTestCommand(t, "RunWebhook", []string{"list"}, dbFile)
+
+	if !strings.Contains(stdout, "(task.deleted, unsigned)") || !strings.Contains(stdout, "Outbox: 1 pending\n  task.deleted #0 Task -> "+server.URL+" (1 failed") {
+		t.Errorf("unexpected list output:\n%s", stdout)
+	}
+}
+
+func TestWebhookPurgeEvent(t *testing.T) {
+	deletedAt := time.Now().Add(-40 * 24 * time.Hour)
+	dbFile := setupTestDB(t, io.Database{Tasks: []io.Task{{ID: 0, UID: "old", Title: "Old", IsDeleted: true, DeletedAt: &deletedAt}}})
+	writeConfig(t, dbFile, `{"webhooks": [{"url": "http://127.0.0.1:1/hook"}]}`)
+
+	if _, err := io.CleanUp(dbFile, 30*24*time.Hour); err != nil {
+		t.Fatalf("CleanUp() returned an unexpected error: %v", err)
+	}
+
+	outbox, _ := webhook.Load(dbFile)
+
+	if len(outbox.Deliveries) != 1 || outbox.Deliveries[0].Event.Type != io.EventPurged || outbox.Deliveries[0].Event.Task.UID != "old" {
+		t.Errorf("expected a task.purged delivery, got %+v", outbox.Deliveries)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	for attempts, expected := range map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 30: webhook.MaxRetry} {
+		if got := webhook.Backoff(attempts); got != expected {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, expected)
+		}
+	}
+}